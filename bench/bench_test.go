@@ -0,0 +1,265 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bench
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"robpike.io/ivy/config"
+	"robpike.io/ivy/exec"
+	"robpike.io/ivy/parse"
+	"robpike.io/ivy/run"
+	"robpike.io/ivy/scan"
+	"robpike.io/ivy/value"
+)
+
+var (
+	baseline  = flag.Bool("baseline", false, "compare benchmarks against the recorded baseline")
+	update    = flag.Bool("update", false, "record the benchmarks as the new baseline")
+	tolerance = flag.Float64("tolerance", 1.5, "allowed slowdown factor relative to the baseline")
+)
+
+const baselineFile = "testdata/baseline.txt"
+
+// benchmarks lists the benchmarks checked against the baseline.
+var benchmarks = []struct {
+	name string
+	fn   func(*testing.B)
+}{
+	{"IntArith", BenchmarkIntArith},
+	{"BigIntMul", BenchmarkBigIntMul},
+	{"VectorAdd", BenchmarkVectorAdd},
+	{"RationalSum", BenchmarkRationalSum},
+	{"ParseScript", BenchmarkParseScript},
+	{"EvalScript", BenchmarkEvalScript},
+	{"IndexedAssign", BenchmarkIndexedAssign},
+}
+
+func newContext() value.Context {
+	conf := new(config.Config)
+	conf.SetFormat("")
+	conf.SetMaxBits(1e9)
+	conf.SetMaxDigits(1e5)
+	conf.SetOrigin(1)
+	conf.SetRandomSeed(0)
+	return exec.NewContext(conf)
+}
+
+// digits returns a string of n pseudo-random decimal digits
+// determined by seed, with a non-zero leading digit.
+func digits(n int, seed int64) string {
+	r := rand.New(rand.NewSource(seed))
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('0' + r.Intn(10))
+	}
+	b[0] = byte('1' + r.Intn(9))
+	return string(b)
+}
+
+// script returns an ivy program of n lines mixing definitions,
+// assignments and expressions of the kinds found in real programs.
+func script(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		switch i % 4 {
+		case 0:
+			fmt.Fprintf(&b, "x%d = %d + 3 * iota 10\n", i%100, i)
+		case 1:
+			fmt.Fprintf(&b, "op f%d y = +/ y * %d/7\n", i%100, i)
+		case 2:
+			fmt.Fprintf(&b, "(2 3 rho iota 6) +.* 3 2 rho %d 1 2\n", i)
+		case 3:
+			fmt.Fprintf(&b, "'abc' , 'def' # comment %d\n", i)
+		}
+	}
+	return b.String()
+}
+
+// BenchmarkIntArith measures the dispatch overhead of arithmetic on
+// small integers, where the arithmetic itself is almost free.
+func BenchmarkIntArith(b *testing.B) {
+	c := newContext()
+	var v value.Value = value.Int(0)
+	for i := 0; i < b.N; i++ {
+		v = c.EvalBinary(v, "+", value.Int(3))
+		v = c.EvalBinary(v, "*", value.Int(1))
+		v = c.EvalBinary(v, "-", value.Int(2))
+	}
+}
+
+// BenchmarkBigIntMul multiplies two 10,000-digit integers.
+func BenchmarkBigIntMul(b *testing.B) {
+	c := newContext()
+	x, err := value.Parse(c.Config(), digits(1e4, 1))
+	if err != nil {
+		b.Fatal(err)
+	}
+	y, err := value.Parse(c.Config(), digits(1e4, 2))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.EvalBinary(x, "*", y)
+	}
+}
+
+// BenchmarkVectorAdd adds two vectors of a million Ints elementwise.
+func BenchmarkVectorAdd(b *testing.B) {
+	c := newContext()
+	elems := make([]int64, 1e6)
+	for i := range elems {
+		elems[i] = int64(i % 1000)
+	}
+	u := value.IntVector(elems)
+	v := value.IntVector(elems)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.EvalBinary(u, "+", v)
+	}
+}
+
+// BenchmarkRationalSum sums 1/1 + 1/2 + ... + 1/200, reducing
+// the rational to lowest terms and shrinking it at each step.
+func BenchmarkRationalSum(b *testing.B) {
+	c := newContext()
+	for i := 0; i < b.N; i++ {
+		var sum value.Value = value.Int(0)
+		for j := 1; j <= 200; j++ {
+			sum = c.EvalBinary(sum, "+", c.EvalBinary(value.Int(1), "/", value.Int(j)))
+		}
+	}
+}
+
+// BenchmarkParseScript parses, without evaluating, a 10,000-line program.
+func BenchmarkParseScript(b *testing.B) {
+	src := script(1e4)
+	b.SetBytes(int64(len(src)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c := newContext()
+		scanner := scan.New(c, "bench", bufio.NewReader(strings.NewReader(src)))
+		parser := parse.NewParser("bench", scanner, c)
+		for {
+			if _, ok := parser.Line(); !ok {
+				break
+			}
+		}
+	}
+}
+
+// BenchmarkEvalScript runs a 1,000-line program through the same
+// entry point as the interpreter and its tests.
+func BenchmarkEvalScript(b *testing.B) {
+	src := script(1e3)
+	b.SetBytes(int64(len(src)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var stdout, stderr bytes.Buffer
+		run.Ivy(newContext(), src, &stdout, &stderr)
+		if stderr.Len() > 0 {
+			b.Fatal(stderr.String())
+		}
+	}
+}
+
+// BenchmarkIndexedAssign assigns to successive elements of a vector
+// of 10,000 elements, which takes constant time only if the vector
+// is not copied for each assignment.
+func BenchmarkIndexedAssign(b *testing.B) {
+	c := newContext()
+	c.AssignGlobal("x", value.NewIntVector(make([]int, 1e4)))
+	scanner := scan.New(c, "bench", bufio.NewReader(strings.NewReader("x[i] = i\n")))
+	exprs, _ := parse.NewParser("bench", scanner, c).Line()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.AssignGlobal("i", value.Int(1+i%1e4))
+		c.Eval(exprs)
+	}
+}
+
+// TestBaseline compares the benchmarks against the recorded baseline,
+// or records a new one. See the package documentation.
+func TestBaseline(t *testing.T) {
+	if !*baseline && !*update {
+		t.Skip("use -baseline to compare against the baseline or -update to record it")
+	}
+	got := make(map[string]int64)
+	for _, bench := range benchmarks {
+		got[bench.name] = testing.Benchmark(bench.fn).NsPerOp()
+	}
+	if *update {
+		if err := writeBaseline(got); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := readBaseline()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bench := range benchmarks {
+		base, ok := want[bench.name]
+		if !ok {
+			t.Errorf("%s: no baseline; run with -update", bench.name)
+			continue
+		}
+		ns := got[bench.name]
+		t.Logf("%s: %d ns/op, baseline %d ns/op", bench.name, ns, base)
+		if float64(ns) > *tolerance*float64(base) {
+			t.Errorf("%s: %d ns/op is more than %.2f times the baseline of %d ns/op", bench.name, ns, *tolerance, base)
+		}
+	}
+}
+
+// readBaseline reads the baseline file, which holds lines of the form
+//
+//	name ns/op
+func readBaseline() (map[string]int64, error) {
+	data, err := os.ReadFile(baselineFile)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]int64)
+	for i, line := range strings.Split(string(data), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var name string
+		var ns int64
+		if _, err := fmt.Sscanf(line, "%s %d", &name, &ns); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", baselineFile, i+1, err)
+		}
+		m[name] = ns
+	}
+	return m, nil
+}
+
+func writeBaseline(m map[string]int64) error {
+	var names []string
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "# Benchmark results in ns/op. Regenerate with go test -run=Baseline -update.")
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s %d\n", name, m[name])
+	}
+	if err := os.MkdirAll(filepath.Dir(baselineFile), 0777); err != nil {
+		return err
+	}
+	return os.WriteFile(baselineFile, b.Bytes(), 0666)
+}
@@ -0,0 +1,31 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package bench holds benchmarks of the hot paths of ivy: scalar
+arithmetic through the evaluator's dispatch, big integer
+multiplication, elementwise vector arithmetic, rational arithmetic,
+and parsing. The inputs are generated deterministically so runs
+are comparable. Run them with
+
+	go test -bench=. robpike.io/ivy/bench
+
+The package also guards against performance regressions, such as a
+change to type promotion that doubles the cost of every operation.
+Timings depend on the machine, so the guard compares against a
+baseline recorded on the same machine, in testdata/baseline.txt.
+The file is not checked in; each machine records its own. To record
+the baseline, before making a change, run
+
+	go test -run=Baseline -update robpike.io/ivy/bench
+
+and to compare against it, run
+
+	go test -run=Baseline -baseline robpike.io/ivy/bench
+
+The comparison fails if a benchmark is slower than its baseline by
+more than the factor set by -tolerance, 1.5 by default. Without
+either flag, the comparison is skipped.
+*/
+package bench // import "robpike.io/ivy/bench"
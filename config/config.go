@@ -5,6 +5,8 @@
 package config // import "robpike.io/ivy/config"
 
 import (
+	crand "crypto/rand"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"math/big"
@@ -12,6 +14,8 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -21,29 +25,67 @@ var DebugFlags = [...]string{
 	"panic",
 	"parse",
 	"tokens",
+	"trace",
 	"types",
 }
 
 // A Config holds information about the configuration of the system.
 // The zero value of a Config represents the default values for all settings.
+//
+// A Config is safe for concurrent use: its methods lock it as needed.
+// To avoid taking the lock for every element of a large value, code
+// that prints a value should work from a Snapshot of the Config.
+// The generator returned by Random is not safe for concurrent use.
 type Config struct {
+	changes   uint64 // See Changes. First, so it is aligned for atomic access.
+	mu        sync.RWMutex
+	once      sync.Once
+	frozen    bool // Whether this is a snapshot, which cannot change.
+	listeners []func(field string)
+	settings
+}
+
+// settings holds the values of a Config, which are copied
+// by Clone and Snapshot.
+type settings struct {
 	prompt      string
+	echo        string // Printed before each echoed input line; empty means no echo.
+	echoAssign  bool   // Whether to print the results of assignments.
 	output      io.Writer
 	errOutput   io.Writer
 	format      string
 	ratFormat   string
+	separator   string // Between elements of a printed vector.
+	empty       string // Printed in place of an empty vector or matrix.
+	decimalSep  rune   // Decimal point in printed numbers; 0 means '.'.
+	width       int    // Width of output lines; 0 means use the terminal's.
+	widthProbe  func() int
+	progress    func(done, total int)
+	lookupEnv   func(name string) (string, bool)
 	formatVerb  byte // The verb if format is floating-point.
 	formatPrec  int  // The precision if format is floating-point.
 	formatFloat bool // Whether format is floating-point.
 	origin      int
 	bigOrigin   *big.Int
 	seed        int64
+	randomMode  RandomMode
+	rounding    RoundingMode
+	degrees     bool // Whether angles are in degrees rather than radians.
+	decimalLits bool // Whether literals such as 1.5 are Decimals rather than rationals.
+	quoScale    int  // Decimal places in a Decimal quotient; -1 means it must be exact.
+	strictBool  bool // Whether comparisons return Bools, which are not numbers.
+	boolWords   bool // Whether Bools print as true and false rather than 1 and 0.
+	noGlyphs    bool // Whether APL symbols such as ⍳ are not accepted as operator names.
+	showTypes   bool // Whether printed results are followed by the names of their types.
 	debug       [len(DebugFlags)]bool
 	source      rand.Source
 	random      *rand.Rand
 	maxBits     uint          // Maximum length of an integer; 0 means no limit.
+	maxShift    uint          // Maximum count of a left shift; 0 means no limit.
+	shiftWidth  uint          // Word width for logical right shifts; 0 means arithmetic shifts.
 	maxDigits   uint          // Above this size, ints print in floating format.
 	maxStack    uint          // Maximum call stack depth.
+	maxRead     uint          // Maximum size of a file read by readbytes; 0 means no limit.
 	floatPrec   uint          // Length of mantissa of a BigFloat.
 	realTime    time.Duration // Elapsed time of last interactive command.
 	userTime    time.Duration // User time of last interactive command.
@@ -54,55 +96,221 @@ type Config struct {
 	mobile     bool // Running on a mobile platform.
 }
 
+// init sets the defaults for a Config that is still the zero value.
 func (c *Config) init() {
+	c.once.Do(c.setDefaults)
+}
+
+func (c *Config) setDefaults() {
 	if c.output == nil {
 		c.output = os.Stdout
 		c.errOutput = os.Stderr
 		c.origin = 1
+		c.separator = " "
 		c.seed = time.Now().UnixNano()
+		c.randomMode = RandomTime
 		c.bigOrigin = big.NewInt(1)
 		c.source = rand.NewSource(c.seed)
 		c.random = rand.New(c.source)
 		c.maxBits = 1e6
+		c.maxShift = 1e6
 		c.maxDigits = 1e4
 		c.maxStack = 1e5
+		c.maxRead = 1e6
 		c.floatPrec = 256
+		c.quoScale = -1
 		c.mobile = false
 	}
 }
 
-// Output returns the writer to be used for program output.
+// FromEnv returns a Config with the default settings, changed by those
+// of these environment variables that are set to valid values:
+//
+//	IVY_FORMAT  the format for printing numbers, as for SetFormat;
+//	            it must contain a % verb
+//	IVY_ORIGIN  the index origin, a non-negative integer
+//	IVY_PREC    the floating-point precision in bits, 1 to 1e6
+//
+// Variables that are unset, empty or invalid are ignored.
+func FromEnv() *Config {
+	c := new(Config)
+	c.init()
+	if s := os.Getenv("IVY_FORMAT"); strings.Contains(s, "%") {
+		c.SetFormat(s)
+	}
+	if n, ok := envUint("IVY_ORIGIN", 1<<31-1); ok {
+		c.SetOrigin(int(n))
+	}
+	if n, ok := envUint("IVY_PREC", 1e6); ok && n > 0 {
+		c.SetFloatPrec(uint(n))
+	}
+	return c
+}
+
+// envUint returns the value of the environment variable, which must
+// be a decimal integer from 0 to max, and whether it is valid.
+func envUint(name string, max uint64) (uint64, bool) {
+	n, err := strconv.ParseUint(strings.TrimSpace(os.Getenv(name)), 10, 64)
+	return n, err == nil && n <= max
+}
+
+// rlock locks the Config for reading, unless it is a snapshot.
+func (c *Config) rlock() {
+	if !c.frozen {
+		c.mu.RLock()
+	}
+}
+
+// runlock undoes rlock.
+func (c *Config) runlock() {
+	if !c.frozen {
+		c.mu.RUnlock()
+	}
+}
+
+// lock locks the Config for writing. A snapshot may not be changed.
+func (c *Config) lock() {
+	if c.frozen {
+		panic("config: cannot change a snapshot")
+	}
+	c.mu.Lock()
+}
+
+// unlock unlocks the Config and tells the functions installed
+// by OnChange that the named field has changed.
+func (c *Config) unlock(field string) {
+	listeners := c.listeners
+	atomic.AddUint64(&c.changes, 1)
+	c.mu.Unlock()
+	for _, fn := range listeners {
+		fn(field)
+	}
+}
+
+// Changes returns the number of changes made so far to the settings,
+// counting each call of Random as one, since drawing a number changes the
+// state of the generator. A caller that sees the same count twice knows
+// that evaluation in between could not have been affected by the Config.
+func (c *Config) Changes() uint64 {
+	return atomic.LoadUint64(&c.changes)
+}
+
+// OnChange installs a function to be called, after the change is made,
+// whenever a setting of the Config is changed. Its argument names the
+// setting, using the name of the special command that sets it, such as
+// "format" or "debug", or "output", "erroutput", "widthprobe",
+// "cputime" or "mobile" for settings that have no such command.
+// The function must not call OnChange.
+func (c *Config) OnChange(fn func(field string)) {
+	c.init()
+	c.lock()
+	c.listeners = append(c.listeners[:len(c.listeners):len(c.listeners)], fn)
+	c.mu.Unlock()
+}
+
+// Clone returns a deep copy of the Config that may be changed without
+// affecting the original. It has its own random number generator,
+// in the same mode and, if seeded, with the same seed, and no
+// functions installed by OnChange. The output writers and the width
+// probe are shared.
+func (c *Config) Clone() *Config {
+	c.init()
+	c.rlock()
+	defer c.runlock()
+	n := &Config{settings: c.settings}
+	n.once.Do(func() {})
+	n.bigOrigin = new(big.Int).Set(c.bigOrigin)
+	switch n.randomMode {
+	case RandomCrypto:
+		n.setRandom(n.randomMode, cryptoSource{})
+	default:
+		n.setRandom(n.randomMode, rand.NewSource(n.seed))
+	}
+	return n
+}
+
+// Snapshot returns a copy of the Config that cannot be changed and so
+// can be read without locking. Its setters panic. It is meant for
+// printing a value, which consults many settings for each element.
+// It shares the random number generator of the original.
+func (c *Config) Snapshot() *Config {
+	if c.frozen {
+		return c
+	}
+	c.init()
+	c.rlock()
+	defer c.runlock()
+	n := &Config{settings: c.settings, frozen: true}
+	n.once.Do(func() {})
+	return n
+}
+
+// Output returns the writer to be used for program output. Diagnostic
+// output, such as the tree printed by ) debug parse and timings, goes
+// there too, so it can be captured along with the results.
 func (c *Config) Output() io.Writer {
 	c.init()
+	c.rlock()
+	defer c.runlock()
 	return c.output
 }
 
 // SetOutput sets the writer to which program output is printed; default is os.Stdout.
 func (c *Config) SetOutput(output io.Writer) {
 	c.init()
+	c.lock()
+	defer c.unlock("output")
 	c.output = output
 }
 
 // ErrOutput returns the writer to be used for error output.
 func (c *Config) ErrOutput() io.Writer {
 	c.init()
+	c.rlock()
+	defer c.runlock()
 	return c.errOutput
 }
 
 // SetErrOutput sets the writer to which error output is printed; default is os.Stderr.
 func (c *Config) SetErrOutput(output io.Writer) {
 	c.init()
+	c.lock()
+	defer c.unlock("erroutput")
 	c.errOutput = output
 }
 
+// ProgressFunc returns the function set by SetProgressFunc, or nil.
+func (c *Config) ProgressFunc() func(done, total int) {
+	c.rlock()
+	defer c.runlock()
+	return c.progress
+}
+
+// SetProgressFunc sets a function to be called periodically during long
+// operations on vectors, such as reductions and elementwise arithmetic,
+// with the number of elements done so far and the total. It is called
+// last with done equal to total. The calls for one operation are never
+// concurrent, but the function must not evaluate ivy code. The default,
+// nil, means no progress is reported.
+func (c *Config) SetProgressFunc(fn func(done, total int)) {
+	c.init()
+	c.lock()
+	defer c.unlock("progress")
+	c.progress = fn
+}
+
 // Format returns the formatting string. If empty, the default
 // formatting is used, as defined by the bases.
 func (c *Config) Format() string {
+	c.rlock()
+	defer c.runlock()
 	return c.format
 }
 
 // Format returns the formatting string for rationals.
 func (c *Config) RatFormat() string {
+	c.rlock()
+	defer c.runlock()
 	return c.ratFormat
 }
 
@@ -110,6 +318,8 @@ func (c *Config) RatFormat() string {
 // is just this format applied twice with a / in between.
 func (c *Config) SetFormat(s string) {
 	c.init()
+	c.lock()
+	defer c.unlock("format")
 	c.formatVerb = 0
 	c.formatPrec = 0
 	c.formatFloat = false
@@ -141,11 +351,15 @@ func (c *Config) SetFormat(s string) {
 // FloatFormat returns the parsed information about the format,
 // if it's a floating-point format.
 func (c *Config) FloatFormat() (verb byte, prec int, ok bool) {
+	c.rlock()
+	defer c.runlock()
 	return c.formatVerb, c.formatPrec, c.formatFloat
 }
 
 // Debug returns the value of the specified boolean debugging flag.
 func (c *Config) Debug(flag string) bool {
+	c.rlock()
+	defer c.runlock()
 	for i, f := range DebugFlags {
 		if f == flag {
 			return c.debug[i]
@@ -158,6 +372,8 @@ func (c *Config) Debug(flag string) bool {
 // It returns false if the flag is unknown.
 func (c *Config) SetDebug(flag string, state bool) bool {
 	c.init()
+	c.lock()
+	defer c.unlock("debug")
 	for i, f := range DebugFlags {
 		if f == flag {
 			c.debug[i] = state
@@ -169,83 +385,591 @@ func (c *Config) SetDebug(flag string, state bool) bool {
 
 // Origin returns the index origin, default 1.
 func (c *Config) Origin() int {
+	c.rlock()
+	defer c.runlock()
 	return c.origin
 }
 
 // BigOrigin returns the index origin as a *big.Int.
 func (c *Config) BigOrigin() *big.Int {
+	c.rlock()
+	defer c.runlock()
 	return c.bigOrigin
 }
 
 // SetOrigin sets the index origin.
 func (c *Config) SetOrigin(origin int) {
 	c.init()
+	c.lock()
+	defer c.unlock("origin")
 	c.origin = origin
 	c.bigOrigin = big.NewInt(int64(origin))
 }
 
+// Separator returns the string printed between the elements of a vector.
+func (c *Config) Separator() string {
+	c.init()
+	c.rlock()
+	defer c.runlock()
+	return c.separator
+}
+
+// SetSeparator sets the string printed between the elements of a vector.
+// The default is a single space. Vectors of characters are printed
+// without separators.
+func (c *Config) SetSeparator(sep string) {
+	c.init()
+	c.lock()
+	defer c.unlock("separator")
+	c.separator = sep
+}
+
+// DecimalSeparator returns the character printed as the decimal point
+// of rationals and floats shown in floating-point format.
+func (c *Config) DecimalSeparator() rune {
+	c.rlock()
+	defer c.runlock()
+	if c.decimalSep == 0 {
+		return '.'
+	}
+	return c.decimalSep
+}
+
+// SetDecimalSeparator sets the character printed as the decimal point
+// of rationals and floats shown in floating-point format. The default
+// is '.'. It affects output only; numbers are always read with '.'.
+func (c *Config) SetDecimalSeparator(sep rune) {
+	c.init()
+	c.lock()
+	defer c.unlock("decimal")
+	c.decimalSep = sep
+}
+
+// A RoundingMode specifies how a rational printed with a fixed
+// number of decimal places is rounded to its last digit.
+type RoundingMode int
+
+const (
+	// RoundHalfAway rounds to the nearest digit, with halves rounded
+	// away from zero. It is the default.
+	RoundHalfAway RoundingMode = iota
+	// RoundHalfEven rounds to the nearest digit, with halves rounded
+	// to an even digit.
+	RoundHalfEven
+	// RoundTowardZero truncates the digits that are not printed.
+	RoundTowardZero
+	// RoundUp rounds toward positive infinity.
+	RoundUp
+	// RoundDown rounds toward negative infinity.
+	RoundDown
+)
+
+var roundingNames = []string{"away", "even", "zero", "up", "down"}
+
+func (m RoundingMode) String() string {
+	if 0 <= m && int(m) < len(roundingNames) {
+		return roundingNames[m]
+	}
+	return fmt.Sprintf("RoundingMode(%d)", int(m))
+}
+
+// ParseRoundingMode returns the rounding mode with the given name, as
+// printed by its String method, and reports whether there is one.
+func ParseRoundingMode(name string) (RoundingMode, bool) {
+	for i, n := range roundingNames {
+		if n == name {
+			return RoundingMode(i), true
+		}
+	}
+	return 0, false
+}
+
+// RoundingMode returns the rounding mode for printing rationals
+// in floating-point format.
+func (c *Config) RoundingMode() RoundingMode {
+	c.rlock()
+	defer c.runlock()
+	return c.rounding
+}
+
+// SetRoundingMode sets the rounding mode for printing rationals
+// in floating-point format. The default is RoundHalfAway.
+func (c *Config) SetRoundingMode(mode RoundingMode) {
+	c.init()
+	c.lock()
+	defer c.unlock("rounding")
+	c.rounding = mode
+}
+
+// Degrees reports whether the trigonometric functions take and return
+// angles in degrees rather than radians.
+func (c *Config) Degrees() bool {
+	c.rlock()
+	defer c.runlock()
+	return c.degrees
+}
+
+// SetDegrees sets whether angles are in degrees rather than radians.
+// The default is radians.
+func (c *Config) SetDegrees(degrees bool) {
+	c.init()
+	c.lock()
+	defer c.unlock("angle")
+	c.degrees = degrees
+}
+
+// DecimalLiterals reports whether numbers written with a decimal point
+// or exponent, such as 19.99, are read as Decimals rather than rationals.
+func (c *Config) DecimalLiterals() bool {
+	c.rlock()
+	defer c.runlock()
+	return c.decimalLits
+}
+
+// SetDecimalLiterals sets whether numbers written with a decimal point
+// or exponent are read as Decimals. The default is false.
+func (c *Config) SetDecimalLiterals(on bool) {
+	c.init()
+	c.lock()
+	defer c.unlock("numbers")
+	c.decimalLits = on
+}
+
+// DecimalScale returns the number of decimal places to which the
+// quotient of Decimals is rounded, or -1 if the quotient must be
+// exact, making one that is not a finite decimal an error.
+func (c *Config) DecimalScale() int {
+	c.init()
+	c.rlock()
+	defer c.runlock()
+	return c.quoScale
+}
+
+// SetDecimalScale sets the number of decimal places to which the
+// quotient of Decimals is rounded, using the rounding mode. The default,
+// -1, requires the quotient to be exact.
+func (c *Config) SetDecimalScale(scale int) {
+	c.init()
+	c.lock()
+	defer c.unlock("scale")
+	if scale < 0 {
+		scale = -1
+	}
+	c.quoScale = scale
+}
+
+// StrictBool reports whether comparisons and logical operators return
+// Bools rather than the Ints 0 and 1. Bools may not be used as numbers.
+func (c *Config) StrictBool() bool {
+	c.rlock()
+	defer c.runlock()
+	return c.strictBool
+}
+
+// SetStrictBool sets whether comparisons and logical operators
+// return Bools. The default is false.
+func (c *Config) SetStrictBool(strict bool) {
+	c.init()
+	c.lock()
+	defer c.unlock("strictbool")
+	c.strictBool = strict
+}
+
+// BoolWords reports whether Bools print as true and false
+// rather than 1 and 0.
+func (c *Config) BoolWords() bool {
+	c.rlock()
+	defer c.runlock()
+	return c.boolWords
+}
+
+// SetBoolWords sets whether Bools print as true and false.
+// The default is false.
+func (c *Config) SetBoolWords(words bool) {
+	c.init()
+	c.lock()
+	defer c.unlock("boolwords")
+	c.boolWords = words
+}
+
+// Glyphs reports whether the scanner accepts APL symbols, such as ⍳ and
+// ×, in place of the names of the operators they stand for.
+func (c *Config) Glyphs() bool {
+	c.rlock()
+	defer c.runlock()
+	return !c.noGlyphs
+}
+
+// SetGlyphs sets whether the scanner accepts APL symbols in place of
+// operator names. The default is true.
+func (c *Config) SetGlyphs(glyphs bool) {
+	c.init()
+	c.lock()
+	defer c.unlock("glyphs")
+	c.noGlyphs = !glyphs
+}
+
+// ShowTypes reports whether each printed result is followed by
+// the name of its type, as reported by the type operator.
+func (c *Config) ShowTypes() bool {
+	c.rlock()
+	defer c.runlock()
+	return c.showTypes
+}
+
+// SetShowTypes sets whether each printed result is followed by
+// the name of its type. The default is false.
+func (c *Config) SetShowTypes(show bool) {
+	c.init()
+	c.lock()
+	defer c.unlock("type")
+	c.showTypes = show
+}
+
+// EmptyVector returns the string printed for an empty vector or matrix.
+func (c *Config) EmptyVector() string {
+	c.rlock()
+	defer c.runlock()
+	return c.empty
+}
+
+// SetEmptyVector sets the string printed for an empty vector or matrix.
+// The default is the empty string, so such values print as a blank line.
+func (c *Config) SetEmptyVector(s string) {
+	c.init()
+	c.lock()
+	defer c.unlock("empty")
+	c.empty = s
+}
+
+// DefaultWidth is the output line width used for a terminal
+// whose width cannot be determined.
+const DefaultWidth = 80
+
+// Width returns the maximum width of an output line, used to
+// wrap long vectors and help text. If it has not been set with SetWidth,
+// it is the width of the terminal as reported by the probe installed
+// with SetWidthProbe, or DefaultWidth if the probe fails. Without a
+// probe, as when output is not a terminal, it is 0, meaning no limit.
+func (c *Config) Width() int {
+	c.rlock()
+	defer c.runlock()
+	if c.width > 0 {
+		return c.width
+	}
+	if c.widthProbe == nil {
+		return 0
+	}
+	// Probe every time, so a terminal can be resized mid-session.
+	if w := c.widthProbe(); w > 0 {
+		return w
+	}
+	return DefaultWidth
+}
+
+// SetWidth sets the maximum width of an output line.
+// Zero, the default, means to use the width of the terminal, if any.
+func (c *Config) SetWidth(width int) {
+	c.init()
+	c.lock()
+	defer c.unlock("width")
+	c.width = width
+}
+
+// SetWidthProbe installs a function that reports the current width
+// of the terminal, or 0 if it is unknown. It should be installed only
+// if output is to a terminal.
+func (c *Config) SetWidthProbe(probe func() int) {
+	c.init()
+	c.lock()
+	defer c.unlock("widthprobe")
+	c.widthProbe = probe
+}
+
+// LookupEnv returns the value of the environment variable and whether
+// it is set, as reported by the function installed with SetLookupEnv,
+// or by os.LookupEnv if there is none.
+func (c *Config) LookupEnv(name string) (string, bool) {
+	c.rlock()
+	lookup := c.lookupEnv
+	c.runlock()
+	if lookup == nil {
+		return os.LookupEnv(name)
+	}
+	return lookup(name)
+}
+
+// SetLookupEnv installs a function to look up environment variables
+// for the env operator, in place of os.LookupEnv. It lets a program
+// that embeds ivy, or a test, supply an environment of its own.
+func (c *Config) SetLookupEnv(lookup func(name string) (string, bool)) {
+	c.init()
+	c.lock()
+	defer c.unlock("lookupenv")
+	c.lookupEnv = lookup
+}
+
 // Prompt returns the interactive prompt.
 func (c *Config) Prompt() string {
+	c.rlock()
+	defer c.runlock()
 	return c.prompt
 }
 
 // SetPrompt sets the interactive prompt.
 func (c *Config) SetPrompt(prompt string) {
 	c.init()
+	c.lock()
+	defer c.unlock("prompt")
 	c.prompt = prompt
 }
 
+// DefaultEcho is the marker printed before echoed input
+// when echoing is turned on without choosing one.
+const DefaultEcho = "> "
+
+// Echo returns the marker printed before each line of input when
+// input is echoed, or the empty string if it is not.
+func (c *Config) Echo() string {
+	c.rlock()
+	defer c.runlock()
+	return c.echo
+}
+
+// SetEcho sets the marker printed before each line of input as it is
+// read, so the output of a script shows the input too. The empty
+// string, the default, turns echoing off.
+func (c *Config) SetEcho(marker string) {
+	c.init()
+	c.lock()
+	defer c.unlock("echo")
+	c.echo = marker
+}
+
+// EchoAssign reports whether the values of assignments are printed,
+// after the name of the variable, as in x = 1024.
+func (c *Config) EchoAssign() bool {
+	c.rlock()
+	defer c.runlock()
+	return c.echoAssign
+}
+
+// SetEchoAssign sets whether the values of assignments are printed.
+// By default they are not.
+func (c *Config) SetEchoAssign(echo bool) {
+	c.init()
+	c.lock()
+	defer c.unlock("echoassign")
+	c.echoAssign = echo
+}
+
 // Random returns the generator for random numbers.
 func (c *Config) Random() *rand.Rand {
 	c.init()
+	atomic.AddUint64(&c.changes, 1) // The caller will draw from it.
+	c.rlock()
+	defer c.runlock()
 	return c.random
 }
 
 // RandomSeed returns the seed used to initialize the random number generator.
+// It is meaningless if the random source is RandomCrypto.
 func (c *Config) RandomSeed() int64 {
+	c.rlock()
+	defer c.runlock()
 	return c.seed
 }
 
-// SetRandomSeed sets the seed for the random number generator.
+// SetRandomSeed sets the seed for the random number generator
+// and sets the random source to RandomSeeded.
 func (c *Config) SetRandomSeed(seed int64) {
 	c.init()
+	c.lock()
+	defer c.unlock("seed")
 	c.seed = seed
-	c.source.Seed(seed)
+	c.setRandom(RandomSeeded, rand.NewSource(seed))
+}
+
+// A RandomMode specifies where random numbers come from.
+type RandomMode int
+
+const (
+	// RandomSeeded is a pseudo-random generator with a seed set
+	// by SetRandomSeed. It produces a reproducible sequence.
+	RandomSeeded RandomMode = iota
+	// RandomTime is a pseudo-random generator seeded by the time of day.
+	// It is the default.
+	RandomTime
+	// RandomCrypto draws from the operating system's cryptographically
+	// secure generator, crypto/rand.
+	RandomCrypto
+)
+
+func (m RandomMode) String() string {
+	switch m {
+	case RandomSeeded:
+		return "seed"
+	case RandomTime:
+		return "time"
+	case RandomCrypto:
+		return "crypto"
+	}
+	return fmt.Sprintf("RandomMode(%d)", int(m))
+}
+
+// RandomSource returns the current source of random numbers.
+func (c *Config) RandomSource() RandomMode {
+	c.init()
+	c.rlock()
+	defer c.runlock()
+	return c.randomMode
+}
+
+// SetRandomSource sets the source of random numbers. For RandomSeeded,
+// the generator is reset using the current seed; for RandomTime, a
+// new seed is taken from the time of day.
+func (c *Config) SetRandomSource(mode RandomMode) {
+	c.init()
+	c.lock()
+	defer c.unlock("seed")
+	switch mode {
+	case RandomSeeded:
+		c.setRandom(mode, rand.NewSource(c.seed))
+	case RandomTime:
+		c.seed = time.Now().UnixNano()
+		c.setRandom(mode, rand.NewSource(c.seed))
+	case RandomCrypto:
+		c.setRandom(mode, cryptoSource{})
+	default:
+		panic(fmt.Sprintf("unknown random source %d", int(mode)))
+	}
+}
+
+func (c *Config) setRandom(mode RandomMode, source rand.Source) {
+	c.randomMode = mode
+	c.source = source
+	c.random = rand.New(source)
+}
+
+// cryptoSource is a rand.Source backed by crypto/rand.
+// The methods of rand.Rand and big.Int.Rand that produce a value
+// in a range sample by rejection, so they remain unbiased
+// when drawing from it.
+type cryptoSource struct{}
+
+func (cryptoSource) Int63() int64 {
+	return int64(cryptoSource{}.Uint64() &^ (1 << 63))
+}
+
+func (cryptoSource) Uint64() uint64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("crypto/rand: %v", err))
+	}
+	return binary.LittleEndian.Uint64(b[:])
 }
 
+func (cryptoSource) Seed(int64) {}
+
 // MaxBits returns the maximum integer size to store, in bits.
 func (c *Config) MaxBits() uint {
 	c.init()
+	c.rlock()
+	defer c.runlock()
 	return c.maxBits
 }
 
 // MaxBits sets the maximum integer size to store, in bits.
 func (c *Config) SetMaxBits(digits uint) {
 	c.init()
+	c.lock()
+	defer c.unlock("maxbits")
 	c.maxBits = digits
 }
 
+// MaxShift returns the maximum count of a left shift, in bits.
+func (c *Config) MaxShift() uint {
+	c.init()
+	c.rlock()
+	defer c.runlock()
+	return c.maxShift
+}
+
+// SetMaxShift sets the maximum count of a left shift, in bits.
+// It guards against shifts that would exhaust memory even
+// when maxbits is 0.
+func (c *Config) SetMaxShift(bits uint) {
+	c.init()
+	c.lock()
+	defer c.unlock("maxshift")
+	c.maxShift = bits
+}
+
+// LogicalShift returns the width, in bits, of the two's-complement
+// word in which right shifts are logical, or 0 if they are arithmetic.
+func (c *Config) LogicalShift() uint {
+	c.init()
+	c.rlock()
+	defer c.runlock()
+	return c.shiftWidth
+}
+
+// SetLogicalShift sets the width, in bits, of the two's-complement word
+// in which right shifts are logical, filling with zeros. If the width is
+// 0, the default, right shifts are arithmetic, propagating the sign.
+func (c *Config) SetLogicalShift(width uint) {
+	c.init()
+	c.lock()
+	defer c.unlock("logicalshift")
+	c.shiftWidth = width
+}
+
 // MaxDigits returns the maximum integer size to print as integer, in digits.
 func (c *Config) MaxDigits() uint {
 	c.init()
+	c.rlock()
+	defer c.runlock()
 	return c.maxDigits
 }
 
 // SetMaxDigits sets the maximum integer size to print as integer, in digits.
 func (c *Config) SetMaxDigits(digits uint) {
 	c.init()
+	c.lock()
+	defer c.unlock("maxdigits")
 	c.maxDigits = digits
 }
 
+// MaxRead returns the maximum size of a file read by readbytes, in bytes.
+func (c *Config) MaxRead() uint {
+	c.init()
+	c.rlock()
+	defer c.runlock()
+	return c.maxRead
+}
+
+// SetMaxRead sets the maximum size of a file read by readbytes, in bytes.
+// A size of 0 means no limit.
+func (c *Config) SetMaxRead(bytes uint) {
+	c.init()
+	c.lock()
+	defer c.unlock("maxread")
+	c.maxRead = bytes
+}
+
 // MaxStack returns the maximum call stack depth.
 func (c *Config) MaxStack() uint {
 	c.init()
+	c.rlock()
+	defer c.runlock()
 	return c.maxStack
 }
 
 // SetMaxStack sets the maximum call stack depth.
 func (c *Config) SetMaxStack(depth uint) {
 	c.init()
+	c.lock()
+	defer c.unlock("maxstack")
 	c.maxStack = depth
 }
 
@@ -253,12 +977,16 @@ func (c *Config) SetMaxStack(depth uint) {
 // The exponent size is fixed by math/big.
 func (c *Config) FloatPrec() uint {
 	c.init()
+	c.rlock()
+	defer c.runlock()
 	return c.floatPrec
 }
 
 // SetFloatPrec sets the floating-point precision in bits.
 func (c *Config) SetFloatPrec(prec uint) {
 	c.init()
+	c.lock()
+	defer c.unlock("prec")
 	if prec == 0 {
 		panic("zero float precision")
 	}
@@ -268,12 +996,16 @@ func (c *Config) SetFloatPrec(prec uint) {
 // CPUTime returns the duration of the last interactive operation.
 func (c *Config) CPUTime() (real, user, sys time.Duration) {
 	c.init()
+	c.rlock()
+	defer c.runlock()
 	return c.realTime, c.userTime, c.sysTime
 }
 
 // SetCPUTime sets the duration of the last interactive operation.
 func (c *Config) SetCPUTime(real, user, sys time.Duration) {
 	c.init()
+	c.lock()
+	defer c.unlock("cputime")
 	c.realTime = real
 	c.userTime = user
 	c.sysTime = sys
@@ -281,6 +1013,8 @@ func (c *Config) SetCPUTime(real, user, sys time.Duration) {
 
 // PrintCPUTime returns a nicely formatted version of the CPU time.
 func (c *Config) PrintCPUTime() string {
+	c.rlock()
+	defer c.runlock()
 	if c.userTime == 0 && c.sysTime == 0 {
 		return printDuration(c.realTime)
 	}
@@ -319,33 +1053,45 @@ func formatDuration(d float64, units string) string {
 
 // Base returns the input and output bases.
 func (c *Config) Base() (inputBase, outputBase int) {
+	c.rlock()
+	defer c.runlock()
 	return c.inputBase, c.outputBase
 }
 
 // InputBase returns the input base.
 func (c *Config) InputBase() int {
+	c.rlock()
+	defer c.runlock()
 	return c.inputBase
 }
 
 // OutputBase returns the output base.
 func (c *Config) OutputBase() int {
+	c.rlock()
+	defer c.runlock()
 	return c.outputBase
 }
 
 // SetBase sets the input and output bases.
 func (c *Config) SetBase(inputBase, outputBase int) {
 	c.init()
+	c.lock()
+	defer c.unlock("base")
 	c.inputBase = inputBase
 	c.outputBase = outputBase
 }
 
 // Mobile reports whether we are running on a mobile platform.
 func (c *Config) Mobile() bool {
+	c.rlock()
+	defer c.runlock()
 	return c.mobile
 }
 
 // SetMobile sets the Mobile bit as specified.
 func (c *Config) SetMobile(mobile bool) {
 	c.init()
+	c.lock()
+	defer c.unlock("mobile")
 	c.mobile = mobile
 }
@@ -0,0 +1,287 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// chiSquared returns the chi-squared statistic for the counts,
+// assuming a uniform distribution.
+func chiSquared(counts []int, n int) float64 {
+	expect := float64(n) / float64(len(counts))
+	var sum float64
+	for _, c := range counts {
+		d := float64(c) - expect
+		sum += d * d / expect
+	}
+	return sum
+}
+
+func TestRandomSourceUniform(t *testing.T) {
+	const (
+		buckets = 10
+		n       = 50000
+		// For 9 degrees of freedom, the probability of exceeding
+		// 40 is about 1e-5, so this will not flake.
+		limit = 40
+	)
+	for _, mode := range []RandomMode{RandomSeeded, RandomTime, RandomCrypto} {
+		var conf Config
+		conf.SetRandomSource(mode)
+		if got := conf.RandomSource(); got != mode {
+			t.Fatalf("RandomSource() = %v, want %v", got, mode)
+		}
+		small := make([]int, buckets)
+		large := make([]int, buckets)
+		ten := big.NewInt(buckets)
+		x := new(big.Int)
+		for i := 0; i < n; i++ {
+			small[conf.Random().Int63n(buckets)]++
+			large[x.Rand(conf.Random(), ten).Int64()]++
+		}
+		if chi := chiSquared(small, n); chi > limit {
+			t.Errorf("%v: Int63n: chi-squared %.1f too large: %v", mode, chi, small)
+		}
+		if chi := chiSquared(large, n); chi > limit {
+			t.Errorf("%v: big.Int.Rand: chi-squared %.1f too large: %v", mode, chi, large)
+		}
+	}
+}
+
+func TestRandomSeedReproducible(t *testing.T) {
+	var conf Config
+	conf.SetRandomSource(RandomCrypto)
+	conf.SetRandomSeed(17)
+	if got := conf.RandomSource(); got != RandomSeeded {
+		t.Fatalf("after SetRandomSeed, RandomSource() = %v, want %v", got, RandomSeeded)
+	}
+	a := conf.Random().Perm(20)
+	conf.SetRandomSeed(17)
+	b := conf.Random().Perm(20)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("same seed gave different permutations: %v %v", a, b)
+		}
+	}
+}
+
+func TestWidth(t *testing.T) {
+	var conf Config
+	if got := conf.Width(); got != 0 {
+		t.Errorf("without a probe, Width() = %d, want 0", got)
+	}
+	termWidth := 100
+	conf.SetWidthProbe(func() int { return termWidth })
+	if got := conf.Width(); got != 100 {
+		t.Errorf("with probe, Width() = %d, want 100", got)
+	}
+	// The terminal is resized.
+	termWidth = 132
+	if got := conf.Width(); got != 132 {
+		t.Errorf("after resize, Width() = %d, want 132", got)
+	}
+	// The probe fails.
+	termWidth = 0
+	if got := conf.Width(); got != DefaultWidth {
+		t.Errorf("with failing probe, Width() = %d, want %d", got, DefaultWidth)
+	}
+	conf.SetWidth(50)
+	termWidth = 100
+	if got := conf.Width(); got != 50 {
+		t.Errorf("after SetWidth(50), Width() = %d, want 50", got)
+	}
+	conf.SetWidth(0)
+	if got := conf.Width(); got != 100 {
+		t.Errorf("after SetWidth(0), Width() = %d, want 100", got)
+	}
+}
+
+func TestRoundingModeNames(t *testing.T) {
+	for _, mode := range []RoundingMode{RoundHalfAway, RoundHalfEven, RoundTowardZero, RoundUp, RoundDown} {
+		got, ok := ParseRoundingMode(mode.String())
+		if !ok || got != mode {
+			t.Errorf("ParseRoundingMode(%q) = %v, %t", mode.String(), got, ok)
+		}
+	}
+	if _, ok := ParseRoundingMode("sideways"); ok {
+		t.Errorf("ParseRoundingMode accepted an unknown mode")
+	}
+	var conf Config
+	if got := conf.RoundingMode(); got != RoundHalfAway {
+		t.Errorf("default rounding mode is %v", got)
+	}
+}
+
+func TestClone(t *testing.T) {
+	var conf Config
+	conf.SetFormat("%.3f")
+	conf.SetOrigin(0)
+	conf.SetRandomSeed(7)
+	clone := conf.Clone()
+	if clone.Format() != "%.3f" || clone.Origin() != 0 || clone.RandomSeed() != 7 {
+		t.Fatalf("clone has format %q, origin %d, seed %d", clone.Format(), clone.Origin(), clone.RandomSeed())
+	}
+	// The clone has its own generator, starting from the seed.
+	if a, b := conf.Random().Int63(), clone.Random().Int63(); a != b {
+		t.Errorf("seeded generators differ: %d %d", a, b)
+	}
+	clone.SetFormat("%d")
+	clone.SetOrigin(1)
+	for _, flag := range DebugFlags {
+		clone.SetDebug(flag, true)
+	}
+	if conf.Format() != "%.3f" || conf.Origin() != 0 {
+		t.Errorf("changing clone changed original: format %q, origin %d", conf.Format(), conf.Origin())
+	}
+	for _, flag := range DebugFlags {
+		if conf.Debug(flag) {
+			t.Errorf("setting debug %s in clone set it in original", flag)
+		}
+	}
+	if verb, prec, _ := conf.FloatFormat(); verb != 'f' || prec != 3 || conf.RatFormat() != "%.3f/%.3f" {
+		t.Errorf("changing clone changed original's parsed format: %c %d %q", verb, prec, conf.RatFormat())
+	}
+	// The clone does not share the original's big origin.
+	clone = conf.Clone()
+	clone.BigOrigin().SetInt64(5)
+	if conf.BigOrigin().Int64() != 0 {
+		t.Errorf("changing clone's BigOrigin changed original to %d", conf.BigOrigin())
+	}
+	// Changes to the original do not reach the clone.
+	conf.SetDebug("cpu", true)
+	conf.SetSeparator(",")
+	if clone.Debug("cpu") || clone.Separator() != " " {
+		t.Errorf("changing original changed clone: debug %t, separator %q", clone.Debug("cpu"), clone.Separator())
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	var conf Config
+	conf.SetFormat("%.2f")
+	snap := conf.Snapshot()
+	conf.SetFormat("")
+	if got := snap.Format(); got != "%.2f" {
+		t.Errorf("snapshot format is %q, want %q", got, "%.2f")
+	}
+	if snap.Snapshot() != snap {
+		t.Errorf("snapshot of a snapshot is a copy")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("changing a snapshot did not panic")
+		}
+	}()
+	snap.SetOrigin(0)
+}
+
+func TestOnChange(t *testing.T) {
+	var conf Config
+	var fields []string
+	conf.OnChange(func(field string) {
+		// The change is visible to the listener.
+		if field == "width" && conf.Width() != 60 {
+			t.Errorf("listener sees width %d", conf.Width())
+		}
+		fields = append(fields, field)
+	})
+	conf.SetWidth(60)
+	conf.SetDebug("cpu", true)
+	conf.SetBase(16, 16)
+	want := "width debug base"
+	if got := strings.Join(fields, " "); got != want {
+		t.Errorf("changes %q, want %q", got, want)
+	}
+	// A clone does not inherit the listener.
+	conf.Clone().SetWidth(10)
+	if len(fields) != 3 {
+		t.Errorf("clone notified listener: %q", fields)
+	}
+}
+
+// TestChanges checks that setting a value or using the random number
+// generator is counted as a change, and reading a setting is not.
+func TestChanges(t *testing.T) {
+	var conf Config
+	n := conf.Changes()
+	conf.Width()
+	if conf.Changes() != n {
+		t.Errorf("reading a setting counted as a change")
+	}
+	conf.SetWidth(60)
+	if conf.Changes() != n+1 {
+		t.Errorf("SetWidth: changes %d, want %d", conf.Changes(), n+1)
+	}
+	conf.Random().Int63()
+	if conf.Changes() != n+2 {
+		t.Errorf("Random: changes %d, want %d", conf.Changes(), n+2)
+	}
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	var conf Config
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			conf.SetDebug("cpu", i%2 == 0)
+			conf.SetFormat("%.3f")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			conf.Debug("cpu")
+			conf.Snapshot().Format()
+		}
+	}()
+	wg.Wait()
+}
+
+func TestFromEnv(t *testing.T) {
+	conf := FromEnv()
+	if conf.Format() != "" || conf.Origin() != 1 || conf.FloatPrec() != 256 {
+		t.Errorf("defaults: format %q origin %d prec %d", conf.Format(), conf.Origin(), conf.FloatPrec())
+	}
+
+	t.Setenv("IVY_FORMAT", "%.3f")
+	t.Setenv("IVY_ORIGIN", "0")
+	t.Setenv("IVY_PREC", " 100 ")
+	conf = FromEnv()
+	if conf.Format() != "%.3f" || conf.Origin() != 0 || conf.FloatPrec() != 100 {
+		t.Errorf("set: format %q origin %d prec %d", conf.Format(), conf.Origin(), conf.FloatPrec())
+	}
+	if verb, prec, ok := conf.FloatFormat(); !ok || verb != 'f' || prec != 3 {
+		t.Errorf("float format %c %d %t", verb, prec, ok)
+	}
+	if conf.MaxBits() != 1e6 || conf.Separator() != " " {
+		t.Errorf("other settings changed: maxbits %d separator %q", conf.MaxBits(), conf.Separator())
+	}
+
+	for _, test := range []struct{ name, value string }{
+		{"IVY_FORMAT", ""},
+		{"IVY_FORMAT", "abc"},
+		{"IVY_ORIGIN", "-1"},
+		{"IVY_ORIGIN", "one"},
+		{"IVY_ORIGIN", "1e3"},
+		{"IVY_ORIGIN", "99999999999"},
+		{"IVY_PREC", "0"},
+		{"IVY_PREC", "2000000"},
+		{"IVY_PREC", "1.5"},
+	} {
+		t.Setenv("IVY_FORMAT", "")
+		t.Setenv("IVY_ORIGIN", "")
+		t.Setenv("IVY_PREC", "")
+		t.Setenv(test.name, test.value)
+		conf := FromEnv()
+		if conf.Format() != "" || conf.Origin() != 1 || conf.FloatPrec() != 256 {
+			t.Errorf("%s=%q: format %q origin %d prec %d", test.name, test.value, conf.Format(), conf.Origin(), conf.FloatPrec())
+		}
+	}
+}
@@ -15,7 +15,18 @@ Unlike APL, the input is ASCII and the results are exact (but see
 the next paragraph).  It uses exact rational arithmetic so it can
 handle arbitrary precision. Values to be input may be integers (3,
 -1), rationals (1/3, -45/67) or floating point values (1e3, -1.5
-(representing 1000 and -3/2)).
+(representing 1000 and -3/2)). As in Go, underscores may separate
+digits for readability: 1_000_000 is a million. An underscore must
+appear between two digits, so 1__0, 10_, 1_/3, 1e_5 and 0x_ff are errors.
+A long integer may instead be written as 0d{...}, whose digits, in the
+input base, may be separated by spaces and newlines, as when a number
+is pasted from a document: 0d{1 000 000} is also a million.
+
+A backslash at the end of a line joins the line to the next. This
+works anywhere, even inside a number or string, so a long number
+wrapped across lines may be entered by ending each line but the last
+with a backslash. The exception is a comment, which always ends with
+its line.
 
 Some functions such as sqrt are irrational. When ivy evaluates an
 irrational function, the result is stored in a high-precision
@@ -44,23 +55,141 @@ of 1+2i) is a single token. The individual parts can be rational,
 so 1/2j-3/2 is the complex number 0.5-1.5i and scans as a single
 value.
 
+For calculations with money and the like, ivy also has decimal numbers,
+which hold exactly the digits written, so 19.90 prints as 19.90. After
+) numbers decimal, numbers written with a decimal point or exponent are
+decimals, and the decimal operator converts other numbers. Sums,
+differences and products of decimals are exact, so 0.1+0.2 is 0.3. A
+quotient that is not a finite decimal, such as 1.00/3, is an error
+unless ) scale sets the number of decimal places to round it to, using
+the ) rounding mode. Mixing a decimal with a rational gives a rational.
+
+Comparisons and logical operators such as == and and yield 1 or 0,
+which are ordinary integers, so +/x>0 counts the positive elements of
+x. After ) strictbool 1 they yield booleans instead, which print as 1
+or 0, or as true and false after ) boolwords 1, and which are an error
+when used as numbers, so a slip like (a==b)+1 is caught. Booleans may
+still be combined with logical operators, compared for equality and
+used to select elements with sel.
+
 Indexing uses [] notation: x[1], x[1; 2], and so on. Indexing by a
 vector selects multiple elements: x[1 2] creates a new item from
 x[1] and x[2]. An empty index slot is a shorthand for all the
 elements along that dimension, so x[] is equivalent to x, and x[;3]
 gives the third column of two-dimensional array x.
+A slot may hold a slice, start:stop or start:stop:step, which selects
+the elements from start to stop inclusive: in origin 1, x[2:4] is
+x[2 3 4] and x[5:1:-2] is x[5 3 1]. A missing start or stop means the
+end of the dimension, so x[3:] drops the first two elements and x[::-1]
+runs backwards through x. Bounds beyond the ends of the dimension are errors.
+Indexing can also appear on the left of an assignment, as in
+x[1] = 0. Such an assignment changes only the variable being
+assigned, so after b = a, setting b[1] leaves a unchanged.
+
+As in APL, an operation whose result is a single item by its nature,
+such as indexing by scalars, a reduction of a vector, or looking up a
+scalar with iota or in, yields a scalar. Operations that select from a
+vector, such as take, drop, sel and compression, always yield a vector,
+even of one element, as does ravel. first and ravel convert one into
+the other: first 1 take x is the scalar x[1], and ,3 is a vector.
 
 Only a subset of APL's functionality is implemented, but all numerical
 operations are supported.
 
+Although ivy's operators have ASCII names, many may also be spelled
+with the APL symbols in the tables below, such as × for * (and sgn),
+÷ for / (and reciprocal), ⌈ for max (and ceil), ⍴ for rho, ↑ for take
+(and head), ∧ and ∨ for and and or, and ≤ ≥ ≠ for <= >= !=. The
+mathematical signs − (minus) and ⋆ (star) stand for - and **. The
+symbols work in reductions and products too, as in ×/ and ∨.∧.
+APL's residue, A∣B, is B mod A, so ∣ is only abs. ) glyphs 0 turns
+the symbols off.
+
+A reduction with a left operand, as in 10 +/ x, folds the elements of x
+into that initial value. As reductions evaluate from the right, the
+initial value is placed on the right, so 10 -/ 1 2 3 is 1-(2-(3-10)).
+If x is empty, the result is the initial value.
+
+Without an initial value, the reduction of an empty vector, or of the
+rows of a matrix with no columns, is the identity of the operator: 0 for
++ - or xor | ^, and 1 for * / and. Thus +/ (x > 100) sel x is 0 if no
+element of x is over 100. Other operators, including max and min and
+user-defined ops, have no identity, and their reduction of an empty
+vector is an error.
+
+A scan with max or min gives the running maximum or minimum, so
+max\ 1 3 2 5 4 is 1 3 3 5 5. Like those with + and *, these scans
+take time proportional to the length of x.
+
+An exclusive scan, written with a doubled backslash as in +\\x, is like
+a scan but element k of the result reduces only the elements before
+element k of x, so +\\1 2 3 is 0 1 3, the offsets at which pieces of
+lengths 1, 2 and 3 start when laid end to end. The first element of the
+result is the identity of the operator, which must have one.
+
+The each adverb, written after an operator, applies the operator to each
+item of its operands rather than to the operands as a whole. The items
+of a vector are its elements, and those of a matrix are its rows, or
+more generally its subarrays along the first axis. Thus +/ each m
+sums each row of m, and x , each y joins the rows of x and y in
+pairs. A scalar operand of a binary operator is paired with every item
+of the other operand. As ivy has no nested arrays, the results must be
+scalars, which form a vector, or arrays of a single shape, which form a
+matrix. The exception is text: strings of different lengths are padded
+with blanks to form a matrix with one string per row, so type each x
+lists the types of the elements of x. After an operator, the word each
+is the adverb when an operand follows it, and otherwise a variable.
+
 Semicolons separate multiple statements on a line. Variables are
 alphanumeric and are assigned with the = operator. Assignment is
-an expression.
+an expression. A variable may not have the name of an operator, and
+defining an op with the name of a variable removes the variable.
+A line ending in a semicolon is evaluated but, like an assignment,
+its result is not printed.
+An assignment written with ::= rather than = is printed, after the
+name of the variable, so x ::= 2**10 prints x = 1024. The special
+command ) echo assign on prints all assignments that way.
+
+Expressions are evaluated from right to left, matching the grammar.
+The right operand of a binary operator is evaluated before the left,
+the elements of a vector such as (f 1) (f 2) from last to first, and
+the indexes of x[i; j] from last to first before x itself, so when ops
+have side effects, as an op that assigns a global variable may, they
+happen in that order. Thus x=1000; x + x=2 is 4. The exception is
+&& and ||, which evaluate their left operand first and their right
+only if needed.
 
 After each successful expression evaluation, the result is stored
 in the variable called _ (underscore) so it can be used in the next
 expression.
 
+In a shell pipeline, as in echo '2**100' | ivy -q, the -q flag reads
+standard input without printing prompts or blank lines between results,
+so the output is just the results. The -last flag prints only the result
+of the last line that has one. Errors are printed to standard error.
+The -echo flag prints each line of input, after a "> " marker, before
+its results, so the output of a script reads like a session.
+
+A script can take parameters. The -set flag, which may be repeated,
+sets a variable to a number, or to a vector of numbers separated by
+spaces, before the script runs, as in ivy -set n=1000 primes.ivy.
+Within a script, env 'NAME' reads the number or numbers held in the
+environment variable NAME; it is an error if NAME is unset or does not
+hold numbers.
+
+The environment variables IVY_FORMAT, IVY_ORIGIN and IVY_PREC set the
+defaults for ) format, ) origin and ) prec, as in IVY_ORIGIN=0 ivy.
+Invalid values are ignored, and the -format, -g and -origin flags
+override them.
+
+When ivy reads standard input, it first runs the prelude file
+$HOME/.ivyrc, if it exists, so it can hold personal constants and ops.
+The -rc flag names a different prelude, which is then run whatever
+the input, and -norc skips it. Errors in the prelude are reported with
+the file and line but do not stop ivy. ) save leaves out the variables
+and ops defined by the prelude, unless they have changed since, as the
+prelude will define them again; ) save "file" all writes them too.
+
 The APL operators, adapted from
 https://en.wikipedia.org/wiki/APL_syntax_and_symbols, and their
 correspondence are listed here. The correspondence is incomplete
@@ -72,28 +201,51 @@ Unary operators
 	Roll              ?B    ?       One integer selected randomly from the first B integers
 	Ceiling           ⌈B    ceil    Least integer greater than or equal to B
 	Floor             ⌊B    floor   Greatest integer less than or equal to B
+	Round                   round   Integer nearest to B; halves go to the even integer
+	Truncate                trunc   Integer part of B, rounded toward zero
 	Shape             ⍴B    rho     Number of components in each dimension of B
 	Not               ∼B    not     Logical: not 1 is 0, not 0 is 1
 	Absolute value    ∣B    abs     Magnitude of B
 	Index generator   ⍳B    iota    Vector of the first B integers
 	Exponential       ⋆B    **      e to the B power
 	Negation          −B    -       Changes sign of B
-	Identity          +B    +       No change to B
+	Conjugate         +B    +       Complex conjugate of B; no change to a real B
 	Signum            ×B    sgn     ¯1 if B<0; 0 if B=0; 1 if B>0
 	Reciprocal        ÷B    /       1 divided by B
 	Ravel             ,B    ,       Reshapes B into a vector
-	Matrix inverse    ⌹B            Inverse of matrix B
+	Matrix inverse    ⌹B    inverse Inverse of square matrix B
+	Determinant             det     Determinant of square matrix B
 	Pi times          ○B            Multiply by π
 	Logarithm         ⍟B    log     Natural logarithm of B
 	Reversal          ⌽B    rot     Reverse elements of B along last axis
 	Reversal          ⊖B    flip    Reverse elements of B along first axis
 	Grade up          ⍋B    up      Indices of B which will arrange B in ascending order
 	Grade down        ⍒B    down    Indices of B which will arrange B in descending order
+	Maximum           ⌈/B   max     Largest element of B
+	Minimum           ⌊/B   min     Smallest element of B
+	Index of maximum        maxpos  Index of the first largest element of vector B
+	Index of minimum        minpos  Index of the first smallest element of vector B
+	Differences             diff    Differences of adjacent elements of vector B
+	Unique            ∪B    unique  Distinct elements of B, in order of first appearance
+	First                   head    First element of vector B
+	First                   first   First element of B, of any rank, as a scalar
+	Last                    last    Final element of vector B
+	Tail                    tail    All but the first element of vector B
+	Init                    init    All but the final element of vector B
 	Execute           ⍎B    ivy     Execute an APL (ivy) expression
 	Monadic format    ⍕B    text    A character representation of B
+	Type                    type    The name of the type of B: 'bool', 'int', 'bigint', 'rat',
+	                                'decimal', 'float', 'complex', 'char', 'vector' or 'matrix'
+	Environment             env     The numbers in the environment variable named by B
+	Read bytes              readbytes The bytes of the file named by B, as integers 0 to 255
+	                                  The file may be no larger than ) maxread bytes
 	Monadic transpose ⍉B    transp  Reverse the axes of B
 	Factorial         !B    !       Product of integers 1 to B
 	Bitwise not             ^       Bitwise complement of B (integer only)
+	Population count        popcount Number of one bits in B (non-negative integer only)
+	Bit length              bitlength Number of bits needed to hold abs B (integer only)
+	To bits                 tobits  Binary digits of B, most significant first: tobits 10 is 1 0 1 0
+	From bits               frombits Integer with binary digits B, most significant first
 	Square root       B⋆.5  sqrt    Square root of B.
 	Sine                    sin     sin(A); APL uses binary ○ (see below)
 	Cosine                  cos     cos(A); ditto
@@ -101,6 +253,9 @@ Unary operators
 	Arcsine                 asin    arcsin(B)
 	Arccosine               acos    arccos(B)
 	Arctangent              atan    arctan(B)
+	                                Angles are in radians unless ) angle degrees is set
+	Degrees to radians      torad   B degrees in radians, in floating point: torad 180 is pi
+	Radians to degrees      todeg   B radians in degrees, in floating point: todeg pi is 180
 	Hyperbolic sine         sinh    sinh(B)
 	Hyperbolic cosine       cosh    cosh(B)
 	Hyperbolic tangent      tanh    tanh(B)
@@ -108,6 +263,9 @@ Unary operators
 	Hyperbolic arccosine    acosh   arccosh(B)
 	Hyperbolic arctangent   atanh   arctanh(B)
 	Rotation by 90°         j       Multiplication by sqrt(-1)
+	Numerator               num     Numerator of B in lowest terms, with the sign of B
+	Denominator             den     Denominator of B in lowest terms; 1 for integers
+	Mixed fraction          mixed   Whole part of B, toward zero, and the rest: mixed -7/3 is -2 -1/3
 	Real part               real    Real component of the value
 	Imaginary part          imag    Imaginary component of the value
 	Phase                   phase   Phase of the value in the complex plane (-π to π)
@@ -140,21 +298,51 @@ Binary operators
 	                            mod     A modulo B (Euclidean)
 	                            imod    A modulo B (Go)
 	Catenation            A,B   ,       Elements of B appended to the elements of A
+	                                    Each element keeps its type, so 1 , 1/2 is 1 1/2
 	Expansion             A\B   fill    Insert zeros (or blanks) in B corresponding to zeros in A
 	                                    In ivy: abs(A) gives count, A <= 0 inserts zero (or blank)
 	Compression           A/B   sel     Select elements in B corresponding to ones in A
 	                                    In ivy: abs(A) gives count, A <= 0 inserts zero
 	Index of              A⍳B   iota    The location (index) of B in A; 1+⌈/⍳⍴A if not found
 	                                    In ivy: origin-1 if not found (i.e. 0 if one-indexed)
-	Matrix divide         A⌹B           Solution to system of linear equations Ax = B
+	Arithmetic sequence         range   B numbers starting at A[1] in steps of A[2]
+	                                    2 3 range 4 is 2 5 8 11
+	Pick                        pick    For each 1 or 0 in A, the element of the first or second row of B
+	                                    B has two rows as long as A: 1 0 pick 2 2 rho 1 2 3 4 is 1 4
+	Interleave                  zip     The elements of A and B alternately
+	                                    1 2 3 zip 4 5 6 is 1 4 2 5 3 6
+	Partition                   partition
+	                                    Matrix whose rows are the successive A elements of B
+	                                    The last row is padded with zeros (or blanks)
+	Sliding windows             windows Matrix whose rows are the runs of A elements of B
+	                                    (+/ 3 windows B)/3 is the moving average of B
+	Match                 A≡B   match   1 if A and B have the same shape and elements; 0 if not
+	Lexical comparison          lexcmp  -1, 0 or 1 as vector A sorts before, with or after B
+	                                    Elements are compared in turn; a prefix sorts first
+	Promotion                   promote B with every element converted to the type named by A
+	                                    A is 'int', 'rat', 'decimal', 'float' or 'complex'
+	                                    'int' promote 1/3 is an error; nothing is truncated
+	Matrix divide         A⌹B   solve   Solution x of the linear equations A +.* x == B, exactly
+	                                    A is square; B is a vector, or a matrix of columns to solve
+	                                    (2 2 rho 2 1 1 3) solve 3 5 is 4/5 7/5
 	Rotation              A⌽B   rot     The elements of B are rotated A positions left
 	Rotation              A⊖B   flip    The elements of B are rotated A positions along the first axis
 	Logarithm             A⍟B   log     Logarithm of B to base A
+	Square root                 sqrtn   Square root of B truncated to A decimal places, as an
+	                                    exact rational: 2 sqrtn 2 is 141/100
+	Round to multiple           roundto Multiple of B nearest to A, exactly; B must be positive
+	                                    Halves go to the even multiple
+	                                    3.14159 roundto 1/100 is 157/50
 	Dyadic format         A⍕B   text    Format B into a character matrix according to A
 	                                    A is the textual format (see format special command);
 	                                    otherwise result depends on length of A:
 	                                    1 gives decimal count, 2 gives width and decimal count,
 	                                    3 gives width, decimal count, and style ('d', 'e', 'f', etc.).
+	Format                      fmt     Format B as text according to A without changing settings
+	                                    An integer A is the base, as for ) obase: 16 fmt 255 is 'ff'
+	                                    Otherwise A is a format as for text: '%08b' fmt 5 is '00000101'
+	Write bytes                 writebytes Write integers 0 to 255 in B to the file named by A
+	                                    as bytes, replacing its contents; the result is the count
 	General transpose     A⍉B   transp  The axes of B are ordered by A
 	Combinations          A!B   !       Number of combinations of B taken A at a time
 	Less than             A<B   <       Comparison: 1 if true, 0 if false
@@ -173,15 +361,43 @@ Binary operators
 	Bitwise xor                 ^       Bitwise A exclusive or B (integer only)
 	Left shift                  <<      A shifted left B bits (integer only)
 	Right Shift                 >>      A shifted right B bits (integer only)
+	                                    Arithmetic: negative A stays negative, rounding down,
+	                                    so -5 >> 1 is -3; see ) logicalshift
+	Bit                         bit     Bit B of A, 0 or 1, counting from ) origin at the
+	                                    least significant bit (integer only)
+	Set bit                     setbit  A with bit B set to 1 (integer only)
+	Clear bit                   clearbit A with bit B set to 0 (integer only)
+	Rotate left                 rotl    Values in A rotated left B bits within a word;
+	                                    A is the word width followed by the values
+	                                    Negative values are truncated to the width as
+	                                    two's complement, and B is taken modulo the width
+	                                    (32 0x80000001) rotl 1 is 3
+	Rotate right                rotr    Values in A rotated right B bits within a word,
+	                                    as for rotl
+	Wrapping add                wadd    Sum of the pair B in a word of A bits, wrapping around;
+	                                    A is a width, for signed words, or a width
+	                                    followed by 1 for signed or 0 for unsigned
+	                                    8 wadd 100 100 is -56; (8 0) wadd 200 100 is 44
+	Wrapping subtract           wsub    Difference of the pair B, wrapping as for wadd
+	Wrapping multiply           wmul    Product of the pair B, wrapping as for wadd
+	Saturating add              sadd    Sum of the pair B in a word of A bits, limited
+	                                    to the range of the word; A is as for wadd
+	                                    8 sadd 100 100 is 127
+	Saturating subtract         ssub    Difference of the pair B, saturating as for sadd
+	Saturating multiply         smul    Product of the pair B, saturating as for sadd
 	Complex construction        j       The complex number A+Bi
 
 Operators and axis indicator
 
 	Name                APL  Ivy  APL Example  Ivy Example  Meaning (of example)
 	Reduce (last axis)  /    /    +/B          +/B          Sum across B
+	                                           A +/B        Sum across B, starting from A
 	Reduce (first axis) ⌿         +⌿B                       Sum down B
 	Scan (last axis)    \    \    +\B          +\B          Running sum across B
+	Exclusive scan           \\                +\\B         Sums of the elements before each of B
 	Scan (first axis)   ⍀         +⍀B                       Running sum down B
+	Each                ¨    each +/¨B         +/ each B    Sum of each item of B
+	                              A,¨B         A , each B   Join items of A and B in pairs
 	Inner product       .    .    A+.×B        A +.* B      Matrix product of A and B
 	Outer product       ∘.   o.   A∘.×B        A o.* B      Outer product of A and B
 	                                                    (lower case o; may need preceding space)
@@ -194,12 +410,13 @@ Type-converting operations
 	Float                   float B The floating-point representation of B;
 	                                for complex numbers, the result is
 	                                (float A)j(float B)
+	Decimal                 decimal B The exact decimal representation of B
 
 Pre-defined constants
 
-The constants e (base of natural logarithms) and pi (π) are pre-defined to high
-precision, about 3000 decimal digits truncated according to the floating point
-precision setting.
+The constants e (base of natural logarithms) and pi (π) are pre-defined to the
+floating point precision setting, and are recomputed when it changes. They are
+read-only.
 
 Character data
 
@@ -249,6 +466,14 @@ the value of the function. Otherwise, execution continues normally. The ":"
 operator has a lower precedence than any other operator; in effect it breaks
 the line into two separate expressions.
 
+The binary operators && and || are logical and and or that evaluate
+their right operand only if needed: 0 && x and 1 || x do not evaluate x.
+Unlike other operators they evaluate their left operand first. Both
+operands must be 0 or 1; for vectors, use the elementwise and and or.
+As with other operators, the left operand is the single operand to
+the left, so a condition is usually parenthesized:
+	(n != 0) && (100/n) > 3
+
 Example: average of a vector (unary):
 	op avg x = (+/x)/rho x
 	avg iota 11
@@ -281,6 +506,43 @@ Example: greatest common divisor (binary):
 	1562 gcd !11
 	result: 22
 
+The left argument of a binary operator may instead be an operator parameter,
+written in parentheses, as in "op (f) name arg". The caller passes the name
+of a binary operator, built-in or user-defined, in parentheses as the left
+operand, and the body applies it under the parameter's name, including in
+reductions and products such as f/ and o.f. Operators are passed only by
+name; an operator in parentheses is not a value and can be used only as
+such an operand.
+
+Example: fold and table (operator parameter):
+	op (f) fold v = (rho v) == 1: v[1]; v[1] f (f) fold 1 drop v
+	(max) fold 3 1 4 1 5
+	result: 5
+	op (f) table v = v o.f v
+	(*) table iota 3
+	result:
+	1 2 3
+	2 4 6
+	3 6 9
+
+The bind operator makes a unary operator from a binary one by fixing one
+of its operands: A bind (f) applies f with A as its left operand, and
+(f) bind B with B as its right one. The result may be assigned to a
+variable, which is then applied like a unary operator, including with
+each. It is not a number, though, and using it as an operand is an error.
+Like any unary operator, it applies to everything to its right, so with
+double as below, double + 1 is double applied to +1, which is 2.
+A bound operator is applied by name only when it is held in a global
+variable assigned on an earlier line.
+
+Example: bind:
+	double = 2 bind (*)
+	double 21
+	result: 42
+	dec = (-) bind 1
+	dec each 5 10
+	result: 4 9
+
 On mobile platforms only, due to I/O restrictions, user-defined operators
 must be presented on a single line. Use semicolons to separate expressions:
 
@@ -320,21 +582,52 @@ base 10 and must be non-negative on input.
 	) help
 		Describe the special commands. Run )help <topic> to learn more
 		about a topic, )help <op> to learn more about an operator.
+	) angle radians
+		Set the units of the angles taken by sin, cos and tan and
+		returned by asin, acos and atan: radians or degrees. With
+		degrees, an exact angle is reduced exactly modulo 360 before
+		it is converted to radians, so sin 30 is 1/2 to the floating-
+		point precision however many times 360 is added to 30.
 	) base 0
 		Set the number base for input and output. The commands ibase and
 		obase control setting of the base for input and output alone,
-		respectively.  Base 0 allows C-style input: decimal, with 037 being
-		octal and 0x10 being hexadecimal. Bases above 16 are disallowed.
+		respectively.  Base 0 allows Go-style input: decimal, with 0o37 being
+		octal, 0x10 being hexadecimal and 0b101 being binary. Other leading
+		zeros are ignored, so 037 is decimal. Bases above 16 are disallowed.
 		To output large integers and rationals, base must be one of
 		0 2 8 10 16. Floats are always printed base 10.
+	) boolwords 0
+		If 1, print booleans, made by comparisons when ) strictbool is
+		set, as true and false rather than 1 and 0.
+	) builtin name
+		Say whether the name is that of an operator, built in or defined
+		by op, or of a variable, or is free to use.
 	) cpu
 		Print the duration of the last interactive calculation.
 	) debug name 0|1
 		Toggle or set the named debugging flag. With no argument, lists
-		the settings.
+		the settings. The trace flag prints each operation as it is
+		evaluated, applied to the values of its operands, and its result,
+		such as 3 * 4 -> 12, innermost first.
+	) decimal "."
+		Set the character printed as the decimal point of numbers shown
+		in floating-point format, such as ) decimal ",". It affects output
+		only; input always uses a period, as do saved files.
 	) demo
 		Run a line-by-line interactive demo. On mobile platforms,
 		use the Demo menu option instead.
+	) echo 0
+		If 1, print each non-blank line of input, including comments,
+		after the marker "> " as it is read, so the output of a script
+		shows the input too. ) echo "marker" turns echoing on with
+		another marker, such as "\t".
+	) echo assign off
+		If on (or 1), print the value of each statement that is an
+		assignment, after the name of the variable, as in x = 1024.
+	) empty ""
+		Set the string printed for a value with no elements, such as
+		iota 0. By default it is empty, so such values print as a blank
+		line; APL programmers may prefer ) empty "⍬".
 	) format ""
 		Set the format for printing values. If empty, the output is printed
 		using the output base. If non-empty, the format determines the
@@ -344,6 +637,22 @@ base 10 and must be non-negative on input.
 		Read input from the named file; return to interactive execution
 		afterwards. If no file is specified, read from "save.ivy".
 		(Unimplemented on mobile.)
+	) glyphs on
+		If on (or 1), the default, APL symbols such as ⍳ and × may be
+		used in place of the names of the operators they stand for, as
+		in ⍳5. If off (or 0), they are not recognized.
+	) import "file"
+		Read and execute the named file, typically a set of definitions
+		to use in the session. Unlike ) get, errors are reported line by
+		line and the rest of the file is still read. An unquoted name,
+		as in ) import stats, reads stats.ivy.
+		(Unimplemented on mobile.)
+	) logicalshift 0
+		If non-zero, right shifts are logical rather than arithmetic:
+		a negative value is treated as the two's-complement word of this
+		many bits and shifted in zeros, so after ) logicalshift 8,
+		-8 >> 1 is 124. A value that does not fit in the word is an error.
+		If 0, the default, -8 >> 1 is -4.
 	) maxbits 1e6
 		To avoid consuming too much memory, if an integer result would
 		require more than this many bits to store, abort the calculation.
@@ -352,9 +661,22 @@ base 10 and must be non-negative on input.
 		To avoid overwhelming amounts of output, if an integer has more
 		than this many digits, print it using the defined floating-point
 		format. If maxdigits is 0, integers are always printed as integers.
+	) maxread 1e6
+		The maximum size, in bytes, of a file read by readbytes; a larger
+		file is an error. If maxread is 0, there is no limit.
+	) maxshift 1e6
+		To avoid consuming too much memory, a left shift by more than
+		this many bits is an error, whatever the setting of maxbits.
+		If maxshift is 0, there is no limit; the default is 1e6.
 	) maxstack 1e5
 		To avoid using too much stack, the number of nested active calls to
 		user-defined operators is limited to maxstack.
+		An op that calls itself, directly or through other ops, with
+		the same exact operands before any global variable changes would
+		never stop, and is reported at once rather than at the limit.
+	) numbers rational
+		Set the kind of number written with a decimal point or exponent,
+		as in 1.5 or 1e-3: rational (the default) or decimal.
 	) op X
 		If X is absent, list all user-defined operators. Otherwise,
 		show the definition of the user-defined operator X. Inside the
@@ -365,15 +687,59 @@ base 10 and must be non-negative on input.
 	) prec 256
 		Set the precision (mantissa length) for floating-point values.
 		The value is in bits. The exponent always has 32 bits.
+	) progress off
+		If on (or 1), report how much of a long operation on a vector,
+		such as a reduction or elementwise arithmetic, is done, as a
+		percentage printed to the error output. It stays off unless
+		that is a terminal. Nothing is printed for an operation that
+		takes less than a second, and the report is erased when it
+		finishes.
 	) prompt ""
 		Set the interactive prompt.
+	) rounding away
+		Set how rationals printed with a fixed number of decimal places,
+		as by ) format "%.2f", are rounded: away (to nearest, with halves
+		away from zero), even (to nearest, with halves to even), zero
+		(truncate), up (toward +∞) or down (toward -∞).
+	) scale off
+		Set the number of decimal places to which quotients of decimals,
+		and rationals converted to decimals, are rounded. If off,
+		the default, a result that is not a finite decimal is an error.
 	) save "save.ivy"
 		Write definitions of user-defined operators and variables to the
 		named file, as ivy textual source. If no file is specified, save to
-		"save.ivy".
+		"save.ivy". Definitions from the prelude are left out unless
+		the word all follows, as in ) save "save.ivy" all.
 		(Unimplemented on mobile.)
+	) separator " "
+		Set the string printed between the elements of a vector.
+		Vectors of characters are printed without separators.
 	) seed 0
-		Set the seed for the ? operator.
+		Set the seed for the ? operator, making its results reproducible.
+		) seed time seeds the generator from the time of day (the default),
+		and ) seed crypto draws from the operating system's cryptographically
+		secure generator. With no argument, print the seed, or crypto.
+	) state
+		Print the version of ivy, the configuration, the names, types and
+		shapes of the variables, and the user-defined operators, for
+		including in bug reports. ) state json prints the same as JSON.
+	) strictbool 0
+		If 1, comparisons and logical operators yield booleans rather
+		than the integers 1 and 0, and using a boolean as a number,
+		as in (1==1)+1, is an error.
+	) time expression
+		Evaluate the expression, print its value and then how long
+		the parsing and evaluation took, as in ) time 3**100000.
+	) write "file"
+		Write the value of _, the last result printed, to the named
+		file as raw bytes, as by "file" writebytes _.
+	) type off
+		If on (or 1), print the name of the type of each result after
+		it, as the type operator reports it, as in 1/3 (rat).
+	) width 0
+		Set the maximum width of an output line. Longer vectors and
+		help text are wrapped to fit. The default, 0, means the width of
+		the terminal, or no limit if output is not to a terminal.
 
 */
 package main
@@ -0,0 +1,183 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMain runs the ivy command itself, rather than the tests,
+// when the test binary is run as a subprocess by runDriver.
+func TestMain(m *testing.M) {
+	if os.Getenv("IVY_TEST_DRIVER") == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runDriver runs ivy with the arguments and standard input, with HOME
+// set to the home directory, and returns its standard output and
+// standard error.
+func runDriver(t *testing.T, home, input string, args ...string) (stdout, stderr string) {
+	t.Helper()
+	cmd := osexec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "IVY_TEST_DRIVER=1", "HOME="+home)
+	cmd.Stdin = strings.NewReader(input)
+	var out, errOut bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*osexec.ExitError); !ok {
+			t.Fatal(err)
+		}
+	}
+	return out.String(), errOut.String()
+}
+
+func TestDriverOutput(t *testing.T) {
+	tests := []struct {
+		args   []string
+		input  string
+		stdout string
+		stderr string
+	}{
+		{[]string{"-q"}, "2**100\n", "1267650600228229401496703205376\n", ""},
+		{[]string{"-q"}, "x = 3\nx+1\n", "4\n", ""},
+		{[]string{"-q"}, "x = 3;\nx+1;\nx*2\n", "6\n", ""},
+		{[]string{"-q"}, "1 / 0\n2\n", "2\n", "division by zero\n"},
+		{[]string{"-last"}, "1\n2\n3\n", "3\n", ""},
+		{[]string{"-last"}, "1\n2\nx = 3\n", "2\n", ""},
+		{[]string{"-last"}, "1\n2;\n", "1\n", ""},
+		{[]string{"-last", "-e", "1; 2+3"}, "", "1 5\n", ""},
+		{[]string{"-q", "-echo"}, "x = 3\n# c\n\nx+1\n", "> x = 3\n> # c\n> x+1\n4\n", ""},
+		{[]string{"-q", "-echo"}, "1 / 0\n2\n", "> 1 / 0\n> 2\n2\n", "division by zero\n"},
+		{[]string{"-set", "n=10", "-set", "v=1 2 3", "-e", "n*v"}, "", "10 20 30\n", ""},
+		{[]string{"-q", "-set", "n=10"}, "n = n+1\nn\n", "11\n", ""},
+	}
+	home := t.TempDir()
+	for _, test := range tests {
+		stdout, stderr := runDriver(t, home, test.input, test.args...)
+		if stdout != test.stdout || stderr != test.stderr {
+			t.Errorf("ivy %s <<< %q:\nstdout %q, want %q\nstderr %q, want %q",
+				strings.Join(test.args, " "), test.input, stdout, test.stdout, stderr, test.stderr)
+		}
+	}
+}
+
+// TestDriverEnv checks that the environment sets defaults
+// that flags override.
+func TestDriverEnv(t *testing.T) {
+	t.Setenv("IVY_ORIGIN", "0")
+	t.Setenv("IVY_FORMAT", "%.2f")
+	home := t.TempDir()
+	if stdout, _ := runDriver(t, home, "", "-e", "iota 3"); stdout != "0.00 1.00 2.00\n" {
+		t.Errorf("environment: stdout %q", stdout)
+	}
+	if stdout, _ := runDriver(t, home, "", "-origin", "1", "-format", "", "-e", "iota 3"); stdout != "1 2 3\n" {
+		t.Errorf("flags: stdout %q", stdout)
+	}
+}
+
+func TestPrelude(t *testing.T) {
+	home := t.TempDir()
+	writeFile := func(name, text string) string {
+		t.Helper()
+		path := filepath.Join(home, name)
+		if err := os.WriteFile(path, []byte(text), 0666); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	rcFile := writeFile(".ivyrc", "x = 10\nop double y = 2*y\n1 / 0\ny = 3\n")
+	other := writeFile("other.ivy", "x = 100\n")
+	first := writeFile("first.ivy", "w = double x\n")
+	rcErr := rcFile + ":3:3: division by zero\n1 / 0\n  ^\n"
+	tests := []struct {
+		args   []string
+		input  string
+		stdout string
+		stderr string
+	}{
+		// The prelude runs before -f, and an error in it is not fatal.
+		{[]string{"-q"}, "double x\ny\n", "20\n3\n", rcErr},
+		{[]string{"-q", "-f", first}, "w\n", "20\n", rcErr},
+		{[]string{"-q", "-norc"}, "x\n", "", "undefined global variable \"x\"\n"},
+		{[]string{"-q", "-rc", other}, "x\n", "100\n", ""},
+		{[]string{"-q", "-rc", other, "-norc"}, "x\n", "", "undefined global variable \"x\"\n"},
+		// Without -rc, the prelude is only for standard input.
+		{[]string{"-e", "x"}, "", "", "<args>:1:1: undefined global variable \"x\"\nx\n^\n"},
+		{[]string{"-rc", other, "-e", "x+1"}, "", "101\n", ""},
+		// Variables set on the command line override the prelude.
+		{[]string{"-q", "-set", "x=5"}, "x\n", "5\n", rcErr},
+		{[]string{"-q", "-rc", filepath.Join(home, "missing")}, "2\n", "2\n", "ivy: open " + filepath.Join(home, "missing") + ": no such file or directory\n"},
+	}
+	for _, test := range tests {
+		stdout, stderr := runDriver(t, home, test.input, test.args...)
+		if stdout != test.stdout || stderr != test.stderr {
+			t.Errorf("ivy %s <<< %q:\nstdout %q, want %q\nstderr %q, want %q",
+				strings.Join(test.args, " "), test.input, stdout, test.stdout, stderr, test.stderr)
+		}
+	}
+
+	// A missing default prelude is not an error.
+	stdout, stderr := runDriver(t, t.TempDir(), "2\n", "-q")
+	if stdout != "2\n" || stderr != "" {
+		t.Errorf("no prelude: stdout %q, stderr %q", stdout, stderr)
+	}
+}
+
+func TestPreludeSave(t *testing.T) {
+	home := t.TempDir()
+	rcFile := filepath.Join(home, ".ivyrc")
+	if err := os.WriteFile(rcFile, []byte("x = 10\ny = 20\nop double n = 2*n\nop half n = n/2\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	saved := filepath.Join(home, "saved.ivy")
+	all := filepath.Join(home, "all.ivy")
+	input := "y = 21\nz = 30\nop half n = n div 2\nop quad n = double double n\n" +
+		")save \"" + saved + "\"\n)save \"" + all + "\" all\n"
+	if _, stderr := runDriver(t, home, input, "-q"); stderr != "" {
+		t.Fatalf("save: %s", stderr)
+	}
+	read := func(file string) string {
+		t.Helper()
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	// Unchanged prelude definitions are left out, and so is the
+	// forward declaration of double, which would replace it.
+	text := read(saved)
+	for _, want := range []string{"y = 21\n", "z = 30\n", "op half n = n div 2\n", "op quad n = double double n\n"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("save: missing %q in:\n%s", want, text)
+		}
+	}
+	for _, bad := range []string{"x = 10", "op double", "op half n = n/2"} {
+		if strings.Contains(text, bad) {
+			t.Errorf("save: unexpected %q in:\n%s", bad, text)
+		}
+	}
+	// With all, everything is saved.
+	text = read(all)
+	for _, want := range []string{"x = 10\n", "y = 21\n", "op double n = 2 * n\n", "op quad n = double double n\n"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("save all: missing %q in:\n%s", want, text)
+		}
+	}
+	// The saved file restores the session on top of the prelude.
+	stdout, stderr := runDriver(t, home, ")get \""+saved+"\"\nx y z (half 7) (quad 1)\n", "-q")
+	if stdout != "10 21 30 3 4\n" || stderr != "" {
+		t.Errorf("get: stdout %q, stderr %q", stdout, stderr)
+	}
+}
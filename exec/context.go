@@ -5,6 +5,9 @@
 package exec // import "robpike.io/ivy/exec"
 
 import (
+	"context"
+	"fmt"
+	"sort"
 	"strings"
 
 	"robpike.io/ivy/config"
@@ -17,13 +20,26 @@ type Symtab map[string]value.Value
 // Context holds execution context, specifically the binding of names to values and operators.
 // It is the only implementation of ../value/Context, but since it references the value
 // package, there would be a cycle if that package depended on this type definition.
+//
+// A Context must not be used by more than one goroutine at a time, but separate
+// contexts may evaluate concurrently, even if they share a Config. Within one
+// evaluation, built-in operators may split large vectors across goroutines;
+// user-defined ops, which run on the context's stack, never are.
 type Context struct {
 	// config is the configuration state used for evaluation, printing, etc.
 	// Accessed through the value.Context Config method.
 	config *config.Config
 
-	frameSizes []int // size of each stack frame on the call stack
+	frameSizes []int     // size of each stack frame on the call stack
+	boundOps   []boundOp // operator bound to the operator parameter of each frame, if any
 	stack      []value.Value
+	calls      map[callKey]uint64 // writes plus config changes at the start of each active call; see enterCall
+	writes     uint64             // number of assignments to global variables
+	// ownedGlobals and ownedLocals record the variables whose value
+	// indexed assignment may update in place; see Own. Locals are
+	// keyed by their position on the stack.
+	ownedGlobals map[string]bool
+	ownedLocals  map[int]bool
 
 	Globals Symtab
 
@@ -36,6 +52,14 @@ type Context struct {
 	Defs []OpDef
 	// Names of variables declared in the currently-being-parsed function.
 	variables []string
+	// Names of operator parameters of the currently-being-parsed function.
+	opParams []string
+	// prelude and preludeOps record the variables and ops defined by
+	// the startup prelude and not redefined since. See MarkPrelude.
+	prelude    map[string]bool
+	preludeOps map[OpDef]bool
+	// ctx, if not nil, stops evaluation when it is done. See SetContext.
+	ctx context.Context
 }
 
 // NewContext returns a new execution context: the stack and variables,
@@ -68,6 +92,27 @@ func (c *Context) Global(name string) value.Value {
 	return c.Globals[name]
 }
 
+// GlobalNames returns the names of the global variables in sorted
+// order, omitting the constants e and pi that every context defines.
+func (c *Context) GlobalNames() []string {
+	var names []string
+	for name := range c.Globals {
+		if name != "pi" && name != "e" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Op returns the user-defined op described by def.
+func (c *Context) Op(def OpDef) *Function {
+	if def.IsBinary {
+		return c.BinaryFn[def.Name]
+	}
+	return c.UnaryFn[def.Name]
+}
+
 // Local returns the value of the local variable with index i.
 func (c *Context) Local(i int) value.Value {
 	return c.stack[len(c.stack)-i]
@@ -76,6 +121,7 @@ func (c *Context) Local(i int) value.Value {
 // AssignLocal assigns the local variable with the given index the value.
 func (c *Context) AssignLocal(i int, value value.Value) {
 	c.stack[len(c.stack)-i] = value
+	c.Share(i, "")
 }
 
 // Assign assigns the global variable the value. The variable must
@@ -83,15 +129,56 @@ func (c *Context) AssignLocal(i int, value value.Value) {
 // Inside a function, new variables become locals.
 func (c *Context) AssignGlobal(name string, val value.Value) {
 	c.Globals[name] = val
+	c.Share(0, name)
+	c.writes++
+	delete(c.prelude, name)
+}
+
+// MarkPrelude records that the global variables and ops defined so far
+// come from the startup prelude, so )save can leave them out. Assigning
+// or defining one again clears its mark.
+func (c *Context) MarkPrelude() {
+	c.prelude = make(map[string]bool)
+	for _, name := range c.GlobalNames() {
+		c.prelude[name] = true
+	}
+	c.preludeOps = make(map[OpDef]bool)
+	for _, def := range c.Defs {
+		c.preludeOps[def] = true
+	}
+}
+
+// FromPrelude reports whether the global variable was defined by the
+// prelude and has not been assigned since.
+func (c *Context) FromPrelude(name string) bool {
+	return c.prelude[name]
 }
 
-// push pushes a new local frame onto the context stack.
-func (c *Context) push(fn *Function) {
+// OpFromPrelude reports whether the op was defined by the prelude
+// and has not been redefined since.
+func (c *Context) OpFromPrelude(def OpDef) bool {
+	return c.preludeOps[def]
+}
+
+// A boundOp records the operator passed to an op's operator parameter.
+type boundOp struct {
+	param string // The name of the parameter, as in op (param) name arg.
+	op    string // The name of the operator bound to it.
+}
+
+// push pushes a new local frame onto the context stack. If op is not
+// empty, it is bound to fn's operator parameter for the frame.
+func (c *Context) push(fn *Function, op string) {
 	n := len(c.stack)
 	for cap(c.stack) < n+len(fn.Locals) {
 		c.stack = append(c.stack[:cap(c.stack)], nil)
 	}
 	c.frameSizes = append(c.frameSizes, len(fn.Locals))
+	bound := boundOp{}
+	if op != "" {
+		bound = boundOp{fn.Left, op}
+	}
+	c.boundOps = append(c.boundOps, bound)
 	c.stack = c.stack[:n+len(fn.Locals)]
 }
 
@@ -99,13 +186,48 @@ func (c *Context) push(fn *Function) {
 func (c *Context) pop() {
 	n := c.frameSizes[len(c.frameSizes)-1]
 	c.frameSizes = c.frameSizes[:len(c.frameSizes)-1]
+	c.boundOps = c.boundOps[:len(c.boundOps)-1]
+	for i := 1; i <= n && len(c.ownedLocals) > 0; i++ {
+		c.Share(i, "")
+	}
 	c.stack = c.stack[:len(c.stack)-n]
 }
 
+// boundOp returns the name of the operator bound to op in the current
+// frame, if op is the frame's operator parameter, or otherwise op.
+func (c *Context) boundOp(op string) string {
+	if n := len(c.boundOps); n > 0 && c.boundOps[n-1].param == op && op != "" {
+		return c.boundOps[n-1].op
+	}
+	return op
+}
+
+// SetContext sets the context.Context that controls evaluation and returns
+// the previous one. Once ctx is done, evaluation stops with an error at the
+// next operator it evaluates. A nil ctx, the default, never stops it.
+func (c *Context) SetContext(ctx context.Context) context.Context {
+	old := c.ctx
+	c.ctx = ctx
+	return old
+}
+
+// checkDone stops evaluation with an error if the context.Context is done.
+func (c *Context) checkDone() {
+	if c.ctx == nil {
+		return
+	}
+	select {
+	case <-c.ctx.Done():
+		value.Errorf("interrupted: %v", c.ctx.Err())
+	default:
+	}
+}
+
 // Eval evaluates a list of expressions.
 func (c *Context) Eval(exprs []value.Expr) []value.Value {
 	var values []value.Value
 	for _, expr := range exprs {
+		c.checkDone()
 		v := expr.Eval(c)
 		if v != nil {
 			values = append(values, v)
@@ -116,6 +238,10 @@ func (c *Context) Eval(exprs []value.Expr) []value.Value {
 
 // EvalUnary evaluates a unary operator, including reductions and scans.
 func (c *Context) EvalUnary(op string, right value.Value) value.Value {
+	c.checkDone()
+	if len(op) > 2 && strings.HasSuffix(op, "\\\\") {
+		return value.ExclusiveScan(c, op[:len(op)-2], right)
+	}
 	if len(op) > 1 {
 		switch op[len(op)-1] {
 		case '/':
@@ -140,21 +266,30 @@ func (c *Context) Unary(op string) value.UnaryOp {
 	if builtin != nil {
 		return builtin
 	}
+	if bound, ok := c.Globals[op].(*value.BoundOp); ok {
+		return bound
+	}
 	return nil
 }
 
 func (c *Context) UserDefined(op string, isBinary bool) bool {
 	if isBinary {
-		return c.BinaryFn[op] != nil
+		return c.BinaryFn[op] != nil || c.isOpParam(op)
 	}
 	return c.UnaryFn[op] != nil
 }
 
-// EvalBinary evaluates a binary operator, including products.
+// EvalBinary evaluates a binary operator, including products
+// and reductions with an initial value.
 func (c *Context) EvalBinary(left value.Value, op string, right value.Value) value.Value {
+	c.checkDone()
+	op = c.boundOp(op)
 	if strings.Contains(op, ".") {
 		return value.Product(c, left, op, right)
 	}
+	if len(op) > 1 && op[len(op)-1] == '/' {
+		return value.Fold(c, left, op[:len(op)-1], right)
+	}
 	fn := c.Binary(op)
 	if fn == nil {
 		value.Errorf("binary %q not implemented", op)
@@ -162,6 +297,35 @@ func (c *Context) EvalBinary(left value.Value, op string, right value.Value) val
 	return fn.EvalBinary(c, left, right)
 }
 
+// EvalOpArg evaluates the user-defined binary op fnName, whose left
+// operand is an operator parameter, with the operator op bound to it.
+// If op is itself the operator parameter of the current frame, the
+// operator bound to that is passed on.
+func (c *Context) EvalOpArg(op, fnName string, right value.Value) value.Value {
+	c.checkDone()
+	fn := c.BinaryFn[fnName]
+	if fn == nil || !fn.LeftOp {
+		value.Errorf("%s does not take an operator as its left operand", fnName)
+	}
+	op = c.boundOp(op)
+	if c.Binary(op) == nil {
+		value.Errorf("binary %q not implemented", op)
+	}
+	return fn.evalBinary(c, op, nil, right)
+}
+
+// BindOp returns the name of the binary operator op for the bind
+// operator to record. If op is the operator parameter of the current
+// frame, the result is the name of the operator bound to that, so the
+// derived operator still works once the frame is gone.
+func (c *Context) BindOp(op string) string {
+	op = c.boundOp(op)
+	if c.Binary(op) == nil {
+		value.Errorf("binary %q not implemented", op)
+	}
+	return op
+}
+
 func (c *Context) Binary(op string) value.BinaryOp {
 	user := c.BinaryFn[op]
 	if user != nil {
@@ -179,6 +343,7 @@ func (c *Context) Binary(op string) value.BinaryOp {
 // information used by the save method.
 func (c *Context) Define(fn *Function) {
 	c.noVar(fn.Name)
+	delete(c.preludeOps, OpDef{fn.Name, fn.IsBinary})
 	if fn.IsBinary {
 		c.BinaryFn[fn.Name] = fn
 	} else {
@@ -205,11 +370,10 @@ func (c *Context) Define(fn *Function) {
 }
 
 // noVar guarantees that there is no global variable with that name,
-// preventing an op from being defined with the same name as a variable,
-// which could cause problems. A variable with value zero is considered to
-// be OK, so one can clear a variable before defining a symbol. A cleared
-// variable is removed from the global symbol table.
-// noVar also prevents defining builtin variables as ops.
+// as an op with the same name as a variable would be hidden by it.
+// Defining the op removes the variable, with a warning unless its
+// value is zero, so one can clear a variable quietly before defining
+// a symbol. noVar also prevents defining builtin variables as ops.
 func (c *Context) noVar(name string) {
 	if name == "_" || name == "pi" || name == "e" { // Cannot redefine these.
 		value.Errorf(`cannot define op with name %q`, name)
@@ -218,11 +382,11 @@ func (c *Context) noVar(name string) {
 	if sym == nil {
 		return
 	}
-	if i, ok := sym.(value.Int); ok && i == 0 {
-		delete(c.Globals, name)
-		return
+	if i, ok := sym.(value.Int); !ok || i != 0 {
+		fmt.Fprintf(c.config.ErrOutput(), "warning: op %s replaces variable %[1]s\n", name)
 	}
-	value.Errorf("cannot define op %s; it is a variable (%[1]s=0 to clear)", name)
+	delete(c.Globals, name)
+	c.Share(0, name)
 }
 
 // noOp is the dual of noVar. It also checks for assignment to builtins.
@@ -242,9 +406,25 @@ func (c *Context) Declare(name string) {
 	c.variables = append(c.variables, name)
 }
 
-// ForgetAll forgets the declared variables.
+// DeclareOp makes the name an operator parameter while parsing
+// the next function.
+func (c *Context) DeclareOp(name string) {
+	c.opParams = append(c.opParams, name)
+}
+
+// ForgetAll forgets the declared variables and operator parameters.
 func (c *Context) ForgetAll() {
 	c.variables = nil
+	c.opParams = nil
+}
+
+func (c *Context) isOpParam(op string) bool {
+	for _, s := range c.opParams {
+		if op == s {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *Context) isVariable(op string) bool {
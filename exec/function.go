@@ -13,6 +13,7 @@ import (
 // Function represents a unary or binary user-defined operator.
 type Function struct {
 	IsBinary bool
+	LeftOp   bool // Left is an operator parameter, as in op (f) fold v.
 	Name     string
 	Left     string
 	Right    string
@@ -23,7 +24,9 @@ type Function struct {
 
 func (fn *Function) String() string {
 	left := ""
-	if fn.IsBinary {
+	if fn.LeftOp {
+		left = "(" + fn.Left + ") "
+	} else if fn.IsBinary {
 		left = fn.Left + " "
 	}
 	s := fmt.Sprintf("op %s%s %s =", left, fn.Name, fn.Right)
@@ -45,7 +48,9 @@ func (fn *Function) EvalUnary(context value.Context, right value.Value) value.Va
 	if uint(len(c.frameSizes)) >= c.config.MaxStack() {
 		value.Errorf("stack overflow calling %q", fn.Name)
 	}
-	c.push(fn)
+	key, prev := c.enterCall(fn, "", nil, right)
+	defer c.leaveCall(key, prev)
+	c.push(fn, "")
 	defer c.pop()
 	c.AssignLocal(1, right)
 	v := value.EvalFunctionBody(c, fn.Name, fn.Body)
@@ -56,15 +61,25 @@ func (fn *Function) EvalUnary(context value.Context, right value.Value) value.Va
 }
 
 func (fn *Function) EvalBinary(context value.Context, left, right value.Value) value.Value {
+	if fn.LeftOp {
+		value.Errorf("left operand of %q must be an operator, such as (+)", fn.Name)
+	}
+	// It's known to be an exec.Context.
+	return fn.evalBinary(context.(*Context), "", left, right)
+}
+
+// evalBinary calls the binary op with op bound to its operator
+// parameter, if it has one, or otherwise the left operand.
+func (fn *Function) evalBinary(c *Context, op string, left, right value.Value) value.Value {
 	if fn.Body == nil {
 		value.Errorf("binary %q undefined", fn.Name)
 	}
-	// It's known to be an exec.Context.
-	c := context.(*Context)
 	if uint(len(c.frameSizes)) >= c.config.MaxStack() {
 		value.Errorf("stack overflow calling %q", fn.Name)
 	}
-	c.push(fn)
+	key, prev := c.enterCall(fn, op, left, right)
+	defer c.leaveCall(key, prev)
+	c.push(fn, op)
 	defer c.pop()
 	c.AssignLocal(1, left)
 	c.AssignLocal(2, right)
@@ -74,3 +89,91 @@ func (fn *Function) EvalBinary(context value.Context, left, right value.Value) v
 	}
 	return v
 }
+
+// A callKey identifies a call of a user-defined op: the op, the operator
+// bound to its operator parameter, if any, and the keys of its operands.
+type callKey struct {
+	fn   *Function
+	op   string
+	args string
+}
+
+// enterCall records the start of a call of fn, with op bound to its
+// operator parameter, on the operands; left is nil for a unary op.
+// If the same call is already active and since it began no global
+// variable has been assigned, no setting changed and no random number
+// drawn, the op is calling itself, directly or through other ops, with
+// nothing changed, and would never stop, so enterCall reports an error
+// rather than waiting for the stack to overflow.
+// Calls whose operands have no key, such as floats or large vectors,
+// are not checked. It returns the key of the call and the previous
+// record of it, which the caller must pass to leaveCall when the call
+// returns.
+func (c *Context) enterCall(fn *Function, op string, left, right value.Value) (callKey, uint64) {
+	args, ok := value.Key(right)
+	if ok && left != nil {
+		var leftKey string
+		leftKey, ok = value.Key(left)
+		args = leftKey + args
+	}
+	if !ok {
+		return callKey{}, 0
+	}
+	key := callKey{fn, op, args}
+	state := c.writes + c.config.Changes()
+	prev, active := c.calls[key]
+	if active && prev == state {
+		what := "argument"
+		if left != nil {
+			what = "arguments"
+		}
+		value.Errorf("infinite recursion detected: %s calls itself with unchanged %s", c.callString(fn, left, right), what)
+	}
+	if c.calls == nil {
+		c.calls = make(map[callKey]uint64)
+	}
+	c.calls[key] = state
+	if !active {
+		// Mark the key absent, so leaveCall deletes it.
+		prev = noCall
+	}
+	return key, prev
+}
+
+// noCall is the record passed to leaveCall for a call that was not active.
+const noCall = ^uint64(0)
+
+// leaveCall restores the record of the call key to prev, as returned
+// by enterCall.
+func (c *Context) leaveCall(key callKey, prev uint64) {
+	switch {
+	case key.fn == nil:
+	case prev == noCall:
+		delete(c.calls, key)
+	default:
+		c.calls[key] = prev
+	}
+}
+
+// callString returns the call of fn on the operands, as it might be typed.
+// A matrix is described by its shape, as its elements span lines.
+func (c *Context) callString(fn *Function, left, right value.Value) string {
+	s := fn.Name + " " + c.operandString(right, false)
+	if left == nil {
+		return s
+	}
+	return c.operandString(left, true) + " " + s
+}
+
+// operandString returns the operand v of a call, parenthesized
+// if it is the left operand and not a scalar.
+func (c *Context) operandString(v value.Value, left bool) string {
+	if m, ok := v.(*value.Matrix); ok {
+		return fmt.Sprintf("(matrix of shape %s)", value.NewIntVector(m.Shape()).Sprint(c.config))
+	}
+	s := v.Sprint(c.config)
+	if left && v.Rank() > 0 {
+		s = "(" + s + ")"
+	}
+	return s
+}
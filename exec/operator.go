@@ -8,7 +8,7 @@ import "robpike.io/ivy/value"
 
 // Predefined reports whether the operator is predefined, a built-in.
 func Predefined(op string) bool {
-	return value.BinaryOps[op] != nil || value.UnaryOps[op] != nil
+	return value.IsOperator(op)
 }
 
 // DefinedOp reports whether the operator is known.
@@ -16,7 +16,7 @@ func (c *Context) DefinedOp(op string) bool {
 	if c.isVariable(op) {
 		return false
 	}
-	return Predefined(op) || c.BinaryFn[op] != nil || c.UnaryFn[op] != nil
+	return Predefined(op) || c.BinaryFn[op] != nil || c.UnaryFn[op] != nil || c.isOpParam(op)
 }
 
 // DefinedBinary reports whether the operator is a known binary.
@@ -24,7 +24,7 @@ func (c *Context) DefinedBinary(op string) bool {
 	if c.isVariable(op) {
 		return false
 	}
-	return c.BinaryFn[op] != nil || value.BinaryOps[op] != nil
+	return c.BinaryFn[op] != nil || value.BinaryOps[op] != nil || c.isOpParam(op)
 }
 
 // DefinedUnary reports whether the operator is a known unary.
@@ -34,3 +34,13 @@ func (c *Context) DefinedUnary(op string) bool {
 	}
 	return c.UnaryFn[op] != nil || value.UnaryOps[op] != nil
 }
+
+// DefinedBound reports whether the name is a global variable holding
+// a bound op, made by bind, which may be applied like a unary op.
+func (c *Context) DefinedBound(name string) bool {
+	if c.isVariable(name) {
+		return false
+	}
+	_, ok := c.Globals[name].(*value.BoundOp)
+	return ok
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package exec
+
+import "robpike.io/ivy/value"
+
+// Values are immutable and assignment shares them, so after b = a both
+// variables hold the same Vector or Matrix. Indexed assignment updates
+// its target in place, so the variable must first have a copy of its
+// own, or the update would be visible through everything sharing it.
+// Copying on every indexed assignment would make a loop of them
+// quadratic, so the context remembers which variables hold a copy that
+// nothing else has seen, and copies only the first time.
+
+// Own returns the value of the variable, the local with index local if
+// local >= 1 or otherwise the named global, for indexed assignment to
+// update in place. Unless the variable's Vector or Matrix is already its
+// own, Own first gives it a copy. It returns nil if the variable is not
+// defined.
+func (c *Context) Own(local int, name string) value.Value {
+	var v value.Value
+	if local >= 1 {
+		v = c.Local(local)
+	} else {
+		v = c.Global(name)
+		// The update changes the global even if no copy is needed.
+		c.writes++
+	}
+	if c.owns(local, name) {
+		return v
+	}
+	switch x := v.(type) {
+	case value.Vector:
+		v = x.Copy()
+	case *value.Matrix:
+		v = x.Copy()
+	default:
+		return v
+	}
+	if local >= 1 {
+		c.AssignLocal(local, v)
+		if c.ownedLocals == nil {
+			c.ownedLocals = make(map[int]bool)
+		}
+		c.ownedLocals[len(c.stack)-local] = true
+	} else {
+		c.AssignGlobal(name, v)
+		if c.ownedGlobals == nil {
+			c.ownedGlobals = make(map[string]bool)
+		}
+		c.ownedGlobals[name] = true
+	}
+	return v
+}
+
+// Share records that the value of the variable, identified as for Own,
+// may now be held elsewhere too, as it may be once it is read or
+// assigned, so the next indexed assignment to it must copy it.
+func (c *Context) Share(local int, name string) {
+	if local >= 1 {
+		if len(c.ownedLocals) > 0 {
+			delete(c.ownedLocals, len(c.stack)-local)
+		}
+		return
+	}
+	if len(c.ownedGlobals) > 0 {
+		delete(c.ownedGlobals, name)
+	}
+}
+
+// owns reports whether the variable, identified as for Own, holds a
+// value that nothing else shares.
+func (c *Context) owns(local int, name string) bool {
+	if local >= 1 {
+		return c.ownedLocals[len(c.stack)-local]
+	}
+	return c.ownedGlobals[name]
+}
@@ -0,0 +1,153 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package exec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"robpike.io/ivy/config"
+	"robpike.io/ivy/value"
+)
+
+// A snapshot is a compact binary form of the variables and ops of a
+// Context, for programs that keep many sessions and want to save and
+// restore them cheaply. It holds:
+//
+//	the magic string "ivysnap" and the format version, a uvarint
+//	the number of global variables, a uvarint
+//	for each variable, its name, a uvarint length and the bytes,
+//	and its value, encoded by value.AppendBinary
+//	the ivy source text that defines the ops, stored like a name
+//
+// The ops are stored as source text because their bodies are parse
+// trees, which only package parse knows how to build. The configuration
+// is not part of the snapshot; it is provided when restoring.
+
+const (
+	snapshotMagic   = "ivysnap"
+	snapshotVersion = 1
+)
+
+// OpText returns the ivy source text that defines the user-defined ops
+// of the context, in an order that, when parsed, recreates them. DefineOps
+// parses such text, which must hold only op definitions, and installs the
+// ops in the context. They are installed by package parse; they are nil if
+// that package is not linked in.
+var (
+	OpText    func(c *Context) string
+	DefineOps func(c *Context, src string) error
+)
+
+// Snapshot returns an encoding of the global variables and
+// user-defined ops of the context. See RestoreContext.
+func (c *Context) Snapshot() []byte {
+	b := []byte(snapshotMagic)
+	b = appendUvarint(b, snapshotVersion)
+	names := c.GlobalNames()
+	b = appendUvarint(b, uint64(len(names)))
+	for _, name := range names {
+		b = appendString(b, name)
+		b = value.AppendBinary(b, c.Globals[name])
+	}
+	ops := ""
+	if len(c.Defs) > 0 {
+		if OpText == nil {
+			value.Errorf("cannot snapshot ops: package parse is not linked in")
+		}
+		ops = OpText(c)
+	}
+	return appendString(b, ops)
+}
+
+// RestoreContext returns a new context with the given configuration
+// holding the variables and ops recorded in data by Snapshot.
+// Restoring ops requires package parse to be linked in. Corrupt or
+// truncated data, or data written by another version of the format,
+// yields an error.
+func RestoreContext(conf *config.Config, data []byte) (*Context, error) {
+	if !bytes.HasPrefix(data, []byte(snapshotMagic)) {
+		return nil, errors.New("restore: not an ivy snapshot")
+	}
+	data = data[len(snapshotMagic):]
+	version, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, errors.New("restore: bad snapshot version")
+	}
+	if version != snapshotVersion {
+		return nil, fmt.Errorf("restore: snapshot version %d; this ivy reads only version %d", version, snapshotVersion)
+	}
+	data = data[n:]
+	nvars, n := binary.Uvarint(data)
+	if n <= 0 || nvars > uint64(len(data)) {
+		return nil, errors.New("restore: bad variable count")
+	}
+	data = data[n:]
+	globals := make(Symtab)
+	var err error
+	for i := uint64(0); i < nvars; i++ {
+		var name string
+		name, data, err = readString(data)
+		if err != nil {
+			return nil, fmt.Errorf("restore: variable name: %v", err)
+		}
+		if name == "" || name == "pi" || name == "e" {
+			return nil, fmt.Errorf("restore: bad variable name %q", name)
+		}
+		var v value.Value
+		v, data, err = value.DecodeBinary(data)
+		if err != nil {
+			return nil, fmt.Errorf("restore: variable %s: %v", name, err)
+		}
+		globals[name] = v
+	}
+	ops, data, err := readString(data)
+	if err != nil {
+		return nil, fmt.Errorf("restore: ops: %v", err)
+	}
+	if len(data) != 0 {
+		return nil, errors.New("restore: trailing data in snapshot")
+	}
+
+	c := NewContext(conf).(*Context)
+	if ops != "" {
+		if DefineOps == nil {
+			return nil, errors.New("restore: cannot define ops: package parse is not linked in")
+		}
+		if err := DefineOps(c, ops); err != nil {
+			return nil, fmt.Errorf("restore: %v", err)
+		}
+	}
+	for name, v := range globals {
+		if c.UnaryFn[name] != nil || c.BinaryFn[name] != nil {
+			return nil, fmt.Errorf("restore: variable %s is also an op", name)
+		}
+		c.AssignGlobal(name, v)
+	}
+	return c, nil
+}
+
+func appendUvarint(b []byte, x uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], x)]...)
+}
+
+func appendString(b []byte, s string) []byte {
+	b = appendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// readString reads a string written by appendString
+// and returns it and the rest of the data.
+func readString(data []byte) (string, []byte, error) {
+	length, n := binary.Uvarint(data)
+	if n <= 0 || length > uint64(len(data)-n) {
+		return "", nil, errors.New("data too short")
+	}
+	data = data[n:]
+	return string(data[:length]), data[length:], nil
+}
@@ -0,0 +1,29 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.18
+
+package exec_test
+
+import (
+	"testing"
+
+	"robpike.io/ivy/exec"
+)
+
+// FuzzRestoreContext checks that corrupt snapshots
+// are reported as errors rather than causing panics.
+func FuzzRestoreContext(f *testing.F) {
+	for _, src := range []string{session, "x = 1", "op f x = x\ny = 'abc'; z = 2 2 rho 1j2"} {
+		c := exec.NewContext(newConfig())
+		ivy(f, c, src)
+		f.Add(c.(*exec.Context).Snapshot())
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		c, err := exec.RestoreContext(newConfig(), data)
+		if err == nil {
+			c.Snapshot()
+		}
+	})
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package exec_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"robpike.io/ivy/config"
+	"robpike.io/ivy/exec"
+	"robpike.io/ivy/run"
+	"robpike.io/ivy/value"
+)
+
+func newConfig() *config.Config {
+	conf := new(config.Config)
+	conf.SetFormat("")
+	conf.SetMaxBits(1e9)
+	conf.SetMaxDigits(1e4)
+	conf.SetOrigin(1)
+	return conf
+}
+
+// ivy runs the program in the context and returns its output.
+func ivy(t testing.TB, c value.Context, src string) string {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	run.Ivy(c, src, &stdout, &stderr)
+	if stderr.Len() > 0 {
+		t.Fatalf("%q: %s", src, stderr.String())
+	}
+	return strings.TrimSpace(stdout.String())
+}
+
+const session = `
+i = 42
+big = 3**200
+negbig = -big
+r = 22/7
+f = float 1/3
+d = decimal 19.90
+z = 3j-1/2
+ch = 'x'
+s = 'hello, world'
+v = 1 2/3 (float 2) 'a' 1j2 (2**100)
+m = 2 3 4 rho iota 24
+hetero = 2 2 rho 1/3 'a' (2**70) (float 1j2)
+empty = iota 0
+op even n = 0 == n mod 2
+op odd n = n == 0: 0; even n - 1
+op even n = n == 0: 1; odd n - 1
+op a choose b = (!b) / (!a) * !b - a
+op fact n =
+	n <= 1: 1
+	n * fact n - 1
+
+third = (/) bind 3
+pairs = 2 bind (choose)
+`
+
+// check lists expressions whose values must survive a snapshot.
+var check = []string{
+	"i", "big", "negbig", "r", "f", "d", "z", "ch", "s", "v", "m", "hetero", "rho empty",
+	"odd 7", "even 10", "3 choose 10", "fact 30",
+	"rho m", "m[2; ; 3]", "hetero[2]",
+	"third", "third 1 2", "pairs", "pairs 5",
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	orig := exec.NewContext(newConfig())
+	ivy(t, orig, session)
+	data := orig.(*exec.Context).Snapshot()
+
+	restored, err := exec.RestoreContext(newConfig(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A snapshot of the restored context is the same as the original.
+	if again := restored.Snapshot(); !bytes.Equal(again, data) {
+		t.Errorf("snapshot of restored context differs:\n%q\n%q", again, data)
+	}
+	for _, expr := range check {
+		want := ivy(t, orig, expr)
+		got := ivy(t, restored, expr)
+		if got != want {
+			t.Errorf("%s: got %q; want %q", expr, got, want)
+		}
+	}
+	// The restored context is independent of the original.
+	ivy(t, restored, "i = 0")
+	if got := ivy(t, orig, "i"); got != "42" {
+		t.Errorf("original i changed to %s", got)
+	}
+}
+
+func TestSnapshotBase(t *testing.T) {
+	conf := newConfig()
+	orig := exec.NewContext(conf)
+	ivy(t, orig, ")base 16\nop inc x = x + 10\nv = inc 1")
+	data := orig.(*exec.Context).Snapshot()
+	restored, err := exec.RestoreContext(conf, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ivy(t, restored, "(inc 0) == 10"); got != "1" {
+		t.Errorf("inc 0 == 10 (base 16): got %s", got)
+	}
+	if got := ivy(t, restored, "v"); got != "11" {
+		t.Errorf("v: got %s; want 11", got)
+	}
+}
+
+func TestSnapshotErrors(t *testing.T) {
+	orig := exec.NewContext(newConfig())
+	ivy(t, orig, "x = 2**100 3\nop f x = x")
+	data := orig.(*exec.Context).Snapshot()
+
+	version := []byte("ivysnap")
+	version = append(version, 99)
+	tests := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{"empty", nil, "not an ivy snapshot"},
+		{"magic", []byte("not a snapshot"), "not an ivy snapshot"},
+		{"version", append(version, data[len(version):]...), "version 99"},
+		{"truncated", data[:len(data)-3], "restore:"},
+		{"trailing", append(data[:len(data):len(data)], 0), "trailing data"},
+		{"bad op", replace(data, "op f x = x", "x = 9 9 99"), "expected op definition"},
+	}
+	for _, test := range tests {
+		_, err := exec.RestoreContext(newConfig(), test.data)
+		if err == nil {
+			t.Errorf("%s: no error", test.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: error %q does not contain %q", test.name, err, test.err)
+		}
+	}
+	// Truncation anywhere is an error.
+	for i := range data {
+		if _, err := exec.RestoreContext(newConfig(), data[:i]); err == nil {
+			t.Errorf("no error for snapshot truncated to %d bytes", i)
+		}
+	}
+}
+
+// replace replaces old with new, a string of the same length, in data.
+func replace(data []byte, old, new string) []byte {
+	return bytes.Replace(data, []byte(old), []byte(new), 1)
+}
@@ -0,0 +1,238 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package interp is the interface for programs that embed ivy. It wires
+// together the scanner, parser and evaluator so a program can run ivy
+// source, as the ivy command does, or evaluate an expression and get
+// its value.
+//
+// For example,
+//
+//	conf := interp.NewConfig(interp.Origin(0), interp.Format("%.4g"))
+//	v, err := interp.Eval(conf, "+/iota 10")
+//
+// sets v to 45.
+package interp // import "robpike.io/ivy/interp"
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"robpike.io/ivy/config"
+	"robpike.io/ivy/exec"
+	"robpike.io/ivy/parse"
+	"robpike.io/ivy/run"
+	"robpike.io/ivy/scan"
+	"robpike.io/ivy/value"
+)
+
+// Config is the configuration of a session, such as the index origin
+// and the format for printing numbers. See package config for its methods.
+type Config = config.Config
+
+// Value is the value of an ivy expression. Its Sprint method
+// formats it as ivy prints it.
+type Value = value.Value
+
+// An Option changes a setting of a Config. See NewConfig.
+type Option func(*Config)
+
+// Origin sets the index origin.
+func Origin(origin int) Option {
+	return func(c *Config) { c.SetOrigin(origin) }
+}
+
+// Format sets the format for printing numbers, as by ) format.
+func Format(format string) Option {
+	return func(c *Config) { c.SetFormat(format) }
+}
+
+// Prec sets the precision of floating-point numbers, in bits.
+func Prec(bits uint) Option {
+	return func(c *Config) { c.SetFloatPrec(bits) }
+}
+
+// MaxBits sets the maximum size of an integer, in bits; 0 means no limit.
+func MaxBits(bits uint) Option {
+	return func(c *Config) { c.SetMaxBits(bits) }
+}
+
+// MaxDigits sets the number of digits above which integers
+// print in floating-point format; 0 means no limit.
+func MaxDigits(digits uint) Option {
+	return func(c *Config) { c.SetMaxDigits(digits) }
+}
+
+// MaxStack sets the maximum depth of the call stack.
+func MaxStack(depth uint) Option {
+	return func(c *Config) { c.SetMaxStack(depth) }
+}
+
+// NewConfig returns a Config with the default settings
+// changed by the options.
+func NewConfig(opts ...Option) *Config {
+	conf := new(Config)
+	for _, opt := range opts {
+		opt(conf)
+	}
+	return conf
+}
+
+// Run executes the ivy program read from r in a new session, printing
+// its results to w and its errors to the error output of the Config.
+// It stops at the first error, which it returns. The program runs with
+// a copy of conf, so settings it changes do not affect conf. A nil conf
+// means the default settings.
+func Run(r io.Reader, w io.Writer, conf *Config) error {
+	if conf == nil {
+		conf = new(Config)
+	}
+	conf = conf.Clone()
+	conf.SetOutput(w)
+	return NewSession(conf).Run("<input>", r, 0)
+}
+
+// Eval evaluates the ivy source in expr in a new session with the
+// Config and returns the value of its last expression. See Session.Eval.
+func Eval(conf *Config, expr string) (Value, error) {
+	if conf == nil {
+		conf = new(Config)
+	}
+	return NewSession(conf).Eval(expr)
+}
+
+// A Session holds the variables and ops of an ivy session,
+// which persist from one call of its methods to the next.
+// Sessions are independent: several may run at once, each with its own
+// Config, but a single Session must not be used concurrently.
+type Session struct {
+	context value.Context
+	parser  *parse.Parser // Reused by Run; nil while in use.
+}
+
+// NewSession returns a new session with the Config.
+// Output is printed to the Config's output and errors
+// to its error output.
+func NewSession(conf *Config) *Session {
+	return &Session{context: exec.NewContext(conf)}
+}
+
+// Context returns the execution context of the session.
+func (s *Session) Context() value.Context {
+	return s.context
+}
+
+// SetVar sets the global variable name to the number, or vector of
+// numbers separated by spaces, in text, read as ivy reads number literals.
+// It is for passing parameters to a program, as the -set flag of the ivy
+// command does; the program may assign the variable again as usual.
+func (s *Session) SetVar(name, text string) error {
+	if !scan.IsIdentifier(name) || name == "pi" || name == "e" ||
+		value.IsOperator(name) || s.context.UserDefined(name, false) || s.context.UserDefined(name, true) {
+		return fmt.Errorf("cannot set variable %q", name)
+	}
+	v, err := value.ParseNumbers(s.context.Config(), text)
+	if err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	s.context.AssignGlobal(name, v)
+	return nil
+}
+
+// A Mode controls how Session.Run executes its input.
+type Mode uint
+
+const (
+	// Interactive prints the prompt before each line of input
+	// and a blank line after each result, as for a terminal.
+	Interactive Mode = 1 << iota
+	// KeepGoing continues after an error rather than stopping.
+	KeepGoing
+	// LastOnly prints only the values of the last line
+	// of input that has any.
+	LastOnly
+)
+
+// Run executes the ivy program read from r, printing the results.
+// The name, such as a file name, identifies the input in error
+// messages. Errors are printed as they occur; Run returns the first,
+// with its location, or nil if there was none.
+func (s *Session) Run(name string, r io.Reader, mode Mode) error {
+	return s.RunContext(context.Background(), name, r, mode)
+}
+
+// RunContext is like Run, but once ctx is done, the line being
+// evaluated stops with an error, as do any that follow.
+func (s *Session) RunContext(ctx context.Context, name string, r io.Reader, mode Mode) error {
+	defer s.setContext(ctx)()
+	scanner := scan.NewReader(s.context, name, r)
+	parser := s.parser
+	if parser == nil {
+		parser = parse.NewParser(name, scanner, s.context)
+	} else {
+		// Take the parser, so a Run from within this one gets its own.
+		s.parser = nil
+		parser.Reset(name, scanner)
+	}
+	defer func() { s.parser = parser }()
+	var first error
+	for {
+		var err error
+		if mode&LastOnly != 0 {
+			err = run.ExecLast(parser, s.context)
+		} else {
+			err = run.Exec(parser, s.context, mode&Interactive != 0)
+		}
+		if err == nil {
+			return first
+		}
+		if first == nil {
+			first = err
+		}
+		if mode&KeepGoing == 0 {
+			return first
+		}
+	}
+}
+
+// Eval evaluates the ivy source in expr and returns the value of its
+// last expression. The values of any earlier lines are printed. If
+// there is nothing to evaluate, the result is an empty vector.
+func (s *Session) Eval(expr string) (Value, error) {
+	return s.EvalContext(context.Background(), expr)
+}
+
+// EvalContext is like Eval, but if ctx is done before the evaluation
+// finishes, it stops and returns an error.
+func (s *Session) EvalContext(ctx context.Context, expr string) (result Value, err error) {
+	defer s.setContext(ctx)()
+	defer func() {
+		if e := recover(); e != nil {
+			switch e := e.(type) {
+			case value.Error:
+				err = e
+			case big.ErrNaN: // Floating point error from math/big.
+				err = e
+			default:
+				panic(e)
+			}
+		}
+	}()
+	v := run.IvyEval(s.context, strings.TrimSuffix(expr, "\n")+"\n")
+	if a, ok := v.(parse.Assignment); ok {
+		v = a.Value
+	}
+	return v, nil
+}
+
+// setContext makes ctx control evaluation in the session and
+// returns a function that restores the previous setting.
+func (s *Session) setContext(ctx context.Context) func() {
+	c := s.context.(*exec.Context)
+	old := c.SetContext(ctx)
+	return func() { c.SetContext(old) }
+}
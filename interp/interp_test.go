@@ -0,0 +1,305 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	var out, errs bytes.Buffer
+	conf := NewConfig()
+	conf.SetErrOutput(&errs)
+	prog := "x = iota 5\n+/x\nop double n = 2*n\ndouble x\n"
+	if err := Run(strings.NewReader(prog), &out, conf); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.String(), "15\n2 4 6 8 10\n"; got != want {
+		t.Errorf("output %q, want %q", got, want)
+	}
+	if errs.Len() != 0 {
+		t.Errorf("errors: %s", errs.String())
+	}
+}
+
+func TestRunStopsAtError(t *testing.T) {
+	var out, errs bytes.Buffer
+	conf := NewConfig()
+	conf.SetErrOutput(&errs)
+	err := Run(strings.NewReader("1\n2 / 0\n3\n"), &out, conf)
+	if err == nil || err.Error() != "<input>:2:3: division by zero" {
+		t.Errorf("error %v", err)
+	}
+	if got := out.String(); got != "1\n" {
+		t.Errorf("output %q, want %q", got, "1\n")
+	}
+	if !strings.Contains(errs.String(), "division by zero") {
+		t.Errorf("error not reported: %q", errs.String())
+	}
+}
+
+func TestRunCopiesConfig(t *testing.T) {
+	var out bytes.Buffer
+	conf := NewConfig(Origin(0))
+	if err := Run(strings.NewReader(")origin 1\niota 3\n"), &out, conf); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "1 2 3\n" {
+		t.Errorf("output %q", got)
+	}
+	if conf.Origin() != 0 {
+		t.Errorf("program changed origin of caller's Config to %d", conf.Origin())
+	}
+}
+
+func TestOptions(t *testing.T) {
+	conf := NewConfig(Origin(0), Format("%.3f"), Prec(100), MaxBits(100), MaxDigits(5), MaxStack(10))
+	if conf.Origin() != 0 || conf.Format() != "%.3f" || conf.FloatPrec() != 100 ||
+		conf.MaxBits() != 100 || conf.MaxDigits() != 5 || conf.MaxStack() != 10 {
+		t.Errorf("options not applied")
+	}
+	var out bytes.Buffer
+	if err := Run(strings.NewReader("iota 3\n1/3\n"), &out, conf); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.String(), "0.000 1.000 2.000\n0.333\n"; got != want {
+		t.Errorf("output %q, want %q", got, want)
+	}
+	if err := Run(strings.NewReader("1 << 200\n"), &out, conf); err == nil {
+		t.Errorf("no error for result beyond MaxBits")
+	}
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		conf *Config
+		expr string
+		want string
+	}{
+		{nil, "+/iota 10", "55"},
+		{NewConfig(Origin(0)), "+/iota 10", "45"},
+		{NewConfig(Origin(0)), "iota 3", "0 1 2"},
+		{nil, "x = 3\nx*x", "9"},
+		{nil, "x = 3", "3"},
+		{nil, "", ""},
+	}
+	for _, test := range tests {
+		v, err := Eval(test.conf, test.expr)
+		if err != nil {
+			t.Errorf("Eval(%q): %v", test.expr, err)
+			continue
+		}
+		conf := test.conf
+		if conf == nil {
+			conf = NewConfig()
+		}
+		if got := v.Sprint(conf); got != test.want {
+			t.Errorf("Eval(%q) = %q, want %q", test.expr, got, test.want)
+		}
+	}
+	if _, err := Eval(nil, "1 / 0"); err == nil || err.Error() != "division by zero" {
+		t.Errorf("Eval(1 / 0): error %v", err)
+	}
+}
+
+// TestSession uses a session as an interactive program would,
+// a line at a time, keeping variables and ops between lines.
+func TestSession(t *testing.T) {
+	var out, errs bytes.Buffer
+	conf := NewConfig()
+	conf.SetOutput(&out)
+	conf.SetErrOutput(&errs)
+	conf.SetPrompt("> ")
+	s := NewSession(conf)
+	if _, err := s.Eval("op sq n = n*n"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Eval("x = 7"); err != nil {
+		t.Fatal(err)
+	}
+	v, err := s.Eval("sq x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := v.Sprint(conf); got != "49" {
+		t.Errorf("sq x = %s, want 49", got)
+	}
+	if _, err := s.Eval("undefined"); err == nil {
+		t.Errorf("no error for undefined variable")
+	}
+	// The session survives the error.
+	if v, err := s.Eval("x+1"); err != nil || v.Sprint(conf) != "8" {
+		t.Errorf("after error, x+1 = %v, %v", v, err)
+	}
+
+	// Interactive mode prompts and continues after errors.
+	err = s.Run("<stdin>", strings.NewReader("sq 3\n1 / 0\nx\n"), Interactive|KeepGoing)
+	if err == nil || err.Error() != "division by zero" {
+		t.Errorf("Run: error %v", err)
+	}
+	if got, want := out.String(), "> 9\n\n> \n> 7\n\n> "; got != want {
+		t.Errorf("interactive output %q, want %q", got, want)
+	}
+
+	// LastOnly prints only the last result.
+	out.Reset()
+	if err := s.Run("script", strings.NewReader("1\n2\nsq 5\n"), LastOnly); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "25\n" {
+		t.Errorf("LastOnly output %q", got)
+	}
+}
+
+// TestSessionRunsSeveralInputs checks that an input stopped by an
+// error mid-line leaves nothing behind for the next one but its
+// variables, as when the ivy command runs several files.
+func TestSessionRunsSeveralInputs(t *testing.T) {
+	var out, errs bytes.Buffer
+	conf := NewConfig()
+	conf.SetOutput(&out)
+	conf.SetErrOutput(&errs)
+	s := NewSession(conf)
+	err := s.Run("first", strings.NewReader("x = 3\ny = x + ) 2\nx = 100\n"), 0)
+	if err == nil || err.Error() != `first:2:9: unexpected RightParen: ")"` {
+		t.Errorf("first: error %v", err)
+	}
+	err = s.Run("second", strings.NewReader("x * 2\n\nx / 0\n"), 0)
+	if err == nil || err.Error() != "second:3:3: division by zero" {
+		t.Errorf("second: error %v", err)
+	}
+	if got := out.String(); got != "6\n" {
+		t.Errorf("output %q, want %q", got, "6\n")
+	}
+}
+
+// TestConcurrentSessions runs interpreters with different settings at
+// the same time. Nothing one does may affect the results of another.
+func TestConcurrentSessions(t *testing.T) {
+	tests := []struct {
+		conf *Config
+		want string
+	}{
+		{NewConfig(Origin(0), Prec(64)), "0 1 2 3 4 10 3.14159265359"},
+		{NewConfig(Origin(1), Prec(256)), "1 2 3 4 5 15 3.14159265359"},
+		{NewConfig(Origin(3), Format("%.20f")), "3.00000000000000000000 4.00000000000000000000 5.00000000000000000000 6.00000000000000000000 7.00000000000000000000 25.00000000000000000000 3.14159265358979323846"},
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, len(tests))
+	for i, test := range tests {
+		wg.Add(1)
+		go func(i int, conf *Config, want string) {
+			defer wg.Done()
+			s := NewSession(conf)
+			for j := 0; j < 50; j++ {
+				v, err := s.Eval("x = iota 5\nx, (+/x), 2 * asin 1")
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				if got := v.Sprint(conf); got != want {
+					errs[i] = fmt.Errorf("origin %d: got %q, want %q", conf.Origin(), got, want)
+					return
+				}
+			}
+		}(i, test.conf, test.want)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+// TestEvalContext checks that canceling the context stops a long
+// computation promptly and leaves the session usable.
+func TestEvalContext(t *testing.T) {
+	s := NewSession(NewConfig())
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := s.EvalContext(ctx, "*/iota 1000000")
+	if err == nil || err.Error() != "interrupted: context deadline exceeded" {
+		t.Fatalf("error %v", err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("took %s to stop", d)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.EvalContext(ctx, "op f n = f n + 1\nf 1"); err == nil {
+		t.Errorf("no error from canceled context")
+	}
+	if v, err := s.Eval("+/iota 4"); err != nil || v.Sprint(s.Context().Config()) != "10" {
+		t.Errorf("after cancel, +/iota 4 = %v, %v", v, err)
+	}
+
+	var errs bytes.Buffer
+	s.Context().Config().SetErrOutput(&errs)
+	err = s.RunContext(ctx, "script", strings.NewReader("1 2 3 * 4\n"), 0)
+	if err == nil || err.Error() != "script:1: interrupted: context canceled" {
+		t.Errorf("RunContext: error %v", err)
+	}
+}
+
+// TestSetVar checks that variables set before a program runs,
+// as by the -set flag, and values read from a fake environment
+// are available to it.
+func TestSetVar(t *testing.T) {
+	var out, errs bytes.Buffer
+	conf := NewConfig()
+	conf.SetOutput(&out)
+	conf.SetErrOutput(&errs)
+	env := map[string]string{"SIZE": " 4 ", "BASES": "2 0x10", "WORD": "hello"}
+	conf.SetLookupEnv(func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	})
+	s := NewSession(conf)
+	if err := s.SetVar("n", "1000"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetVar("v", "1 -2 3/4"); err != nil {
+		t.Fatal(err)
+	}
+	prog := "n\nv\nv = 5\nv\n(env 'SIZE') * env 'BASES'\n"
+	if err := s.Run("script", strings.NewReader(prog), 0); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.String(), "1000\n1 -2 3/4\n5\n8 64\n"; got != want {
+		t.Errorf("output %q, want %q", got, want)
+	}
+
+	for _, test := range []struct{ name, text, err string }{
+		{"pi", "3", `cannot set variable "pi"`},
+		{"iota", "3", `cannot set variable "iota"`},
+		{"2x", "3", `cannot set variable "2x"`},
+		{"", "3", `cannot set variable ""`},
+		{"x", "", `x: no number in ""`},
+		{"x", "1 two", `x: bad number syntax: two`},
+	} {
+		if err := s.SetVar(test.name, test.text); err == nil || err.Error() != test.err {
+			t.Errorf("SetVar(%q, %q): error %v, want %q", test.name, test.text, err, test.err)
+		}
+	}
+
+	for _, test := range []struct{ expr, err string }{
+		{"env 'MISSING'", "env: $MISSING is not set"},
+		{"env 'WORD'", "env: $WORD: bad number syntax: hello"},
+		{"env 3", "unary env not implemented on type int"},
+	} {
+		if _, err := s.Eval(test.expr); err == nil || err.Error() != test.err {
+			t.Errorf("%s: error %v, want %q", test.expr, err, test.err)
+		}
+	}
+}
@@ -5,20 +5,19 @@
 package main // import "robpike.io/ivy"
 
 import (
-	"bufio"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"runtime/pprof"
 	"strings"
 
 	"robpike.io/ivy/config"
 	"robpike.io/ivy/exec"
-	"robpike.io/ivy/parse"
-	"robpike.io/ivy/run"
-	"robpike.io/ivy/scan"
-	"robpike.io/ivy/value"
+	"robpike.io/ivy/interp"
 )
 
 var (
@@ -32,14 +31,39 @@ var (
 	maxstack        = flag.Uint("stack", 100000, "maximum call stack `depth` allowed")
 	origin          = flag.Int("origin", 1, "set index origin to `n` (must be >=0)")
 	prompt          = flag.String("prompt", "", "command `prompt`")
+	quiet           = flag.Bool("q", false, "read standard input quietly, without prompts or blank lines between results")
+	last            = flag.Bool("last", false, "print only the result of the last line of input that has one")
+	echo            = flag.Bool("echo", false, "print each line of input, after a \"> \" marker, before its results")
+	rc              = flag.String("rc", "", "run prelude `file` before input; default $HOME/.ivyrc when reading standard input")
+	norc            = flag.Bool("norc", false, "do not run the prelude file")
 	debugFlag       = flag.String("debug", "", "comma-separated `names` of debug settings to enable")
 )
 
 var (
-	conf    config.Config
-	context value.Context
+	conf    *config.Config
+	session *interp.Session
+	sets    setFlags
 )
 
+func init() {
+	flag.Var(&sets, "set", "set variable before running, as in -set n=1000; `name=value` may repeat")
+}
+
+// setFlags holds the settings of the repeatable -set flag, in order.
+type setFlags []string
+
+func (s *setFlags) String() string {
+	return strings.Join(*s, " ")
+}
+
+func (s *setFlags) Set(arg string) error {
+	if !strings.Contains(arg, "=") {
+		return fmt.Errorf("expected name=value, not %q", arg)
+	}
+	*s = append(*s, arg)
+	return nil
+}
+
 func main() {
 	flag.Usage = usage
 	flag.Parse()
@@ -53,16 +77,28 @@ func main() {
 		os.Exit(2)
 	}
 
+	// Flags override the settings from the environment,
+	// but only if they are given.
+	conf = config.FromEnv()
+	given := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { given[f.Name] = true })
 	if *gformat {
 		*format = "%.12g"
+		given["format"] = true
+	}
+	if given["format"] {
+		conf.SetFormat(*format)
+	}
+	if given["origin"] {
+		conf.SetOrigin(*origin)
 	}
-
-	conf.SetFormat(*format)
 	conf.SetMaxBits(*maxbits)
 	conf.SetMaxDigits(*maxdigits)
 	conf.SetMaxStack(*maxstack)
-	conf.SetOrigin(*origin)
 	conf.SetPrompt(*prompt)
+	if terminalWidth() > 0 {
+		conf.SetWidthProbe(terminalWidth)
+	}
 
 	if len(*debugFlag) > 0 {
 		for _, debug := range strings.Split(*debugFlag, ",") {
@@ -73,22 +109,41 @@ func main() {
 		}
 	}
 
-	context = exec.NewContext(&conf)
+	session = interp.NewSession(conf)
+
+	// The default prelude is for sessions that read standard input.
+	if !*norc && (*rc != "" || *execute == "" && flag.NArg() == 0) {
+		runPrelude(*rc)
+	}
+
+	// Variables from the command line override the prelude.
+	for _, set := range sets {
+		i := strings.Index(set, "=")
+		if err := session.SetVar(set[:i], set[i+1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "ivy: -set: %s\n", err)
+			os.Exit(2)
+		}
+	}
+
+	// Echo the input that follows the prelude.
+	if *echo {
+		conf.SetEcho(config.DefaultEcho)
+	}
 
 	if *file != "" {
-		if !runFile(context, *file) {
+		if !runFile(*file) {
 			os.Exit(1)
 		}
 	}
 
 	if *executeContinue != "" {
-		if !runString(context, *executeContinue) {
+		if !runString(*executeContinue) {
 			os.Exit(1)
 		}
 	}
 
 	if *execute != "" {
-		if !runString(context, *execute) {
+		if !runString(*execute) {
 			os.Exit(1)
 		}
 		return
@@ -96,21 +151,35 @@ func main() {
 
 	if flag.NArg() > 0 {
 		for i := 0; i < flag.NArg(); i++ {
-			if !runFile(context, flag.Arg(i)) {
+			if !runFile(flag.Arg(i)) {
 				os.Exit(1)
 			}
 		}
 		return
 	}
 
-	scanner := scan.New(context, "<stdin>", bufio.NewReader(os.Stdin))
-	parser := parse.NewParser("<stdin>", scanner, context)
-	for !run.Run(parser, context, true) {
+	session.Run("<stdin>", os.Stdin, mode(!*quiet)|interp.KeepGoing)
+}
+
+// mode returns the mode in which to run input, which is interactive
+// if requested and -last is not set.
+func mode(interactive bool) interp.Mode {
+	if *last {
+		return interp.LastOnly
+	}
+	if interactive {
+		return interp.Interactive
 	}
+	return 0
 }
 
+// terminalWidth reports the width of the terminal that is standard
+// output, or 0 if it is not a terminal. It is replaced by system-specific
+// files, like termwidth_unix.go.
+var terminalWidth = func() int { return 0 }
+
 // runFile executes the contents of the file as an ivy program.
-func runFile(context value.Context, file string) bool {
+func runFile(file string) bool {
 	var fd io.Reader
 	var err error
 	interactive := false
@@ -125,16 +194,36 @@ func runFile(context value.Context, file string) bool {
 		fmt.Fprintf(os.Stderr, "ivy: %s\n", err)
 		os.Exit(1)
 	}
-	scanner := scan.New(context, file, bufio.NewReader(fd))
-	parser := parse.NewParser(file, scanner, context)
-	return run.Run(parser, context, interactive)
+	return session.Run(file, fd, mode(interactive && !*quiet)) == nil
+}
+
+// runPrelude executes the prelude file, $HOME/.ivyrc if file is empty,
+// and marks what it defines so )save can leave it out. Errors are
+// reported but are not fatal, and a missing default prelude is ignored.
+func runPrelude(file string) {
+	explicit := file != ""
+	if !explicit {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return
+		}
+		file = filepath.Join(home, ".ivyrc")
+	}
+	fd, err := os.Open(file)
+	if err != nil {
+		if explicit || !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "ivy: %s\n", err)
+		}
+		return
+	}
+	defer fd.Close()
+	session.Run(file, fd, interp.KeepGoing)
+	session.Context().(*exec.Context).MarkPrelude()
 }
 
 // runString executes the string, typically a command-line argument, as an ivy program.
-func runString(context value.Context, str string) bool {
-	scanner := scan.New(context, "<args>", strings.NewReader(str))
-	parser := parse.NewParser("<args>", scanner, context)
-	return run.Run(parser, context, false)
+func runString(str string) bool {
+	return session.Run("<args>", strings.NewReader(str), mode(false)) == nil
 }
 
 func usage() {
@@ -170,8 +170,21 @@ func reset() {
 	testConf.SetFormat("")
 	testConf.SetMaxBits(1e9)
 	testConf.SetMaxDigits(1e4)
+	testConf.SetMaxShift(1e6)
+	testConf.SetLogicalShift(0)
 	testConf.SetOrigin(1)
 	testConf.SetPrompt("")
+	testConf.SetEcho("")
 	testConf.SetBase(0, 0)
 	testConf.SetRandomSeed(0)
+	testConf.SetSeparator(" ")
+	testConf.SetEmptyVector("")
+	testConf.SetWidth(0)
+	testConf.SetDecimalSeparator(0)
+	testConf.SetRoundingMode(config.RoundHalfAway)
+	testConf.SetDegrees(false)
+	testConf.SetDecimalLiterals(false)
+	testConf.SetDecimalScale(-1)
+	testConf.SetStrictBool(false)
+	testConf.SetBoolWords(false)
 }
@@ -8,6 +8,8 @@ package parse
 // validity checks.
 
 import (
+	"robpike.io/ivy/config"
+	"robpike.io/ivy/exec"
 	"robpike.io/ivy/value"
 )
 
@@ -16,6 +18,34 @@ import (
 // such as is done in the interpreter to avoid printing the results of assignment expressions.
 type Assignment struct {
 	value.Value
+	// Name, if not empty, is the target of the assignment as written,
+	// such as x or x[2]. It is set when the result is to be printed
+	// anyway, as Name = Value: if the assignment is written with ::=
+	// or if ) echo assign is on.
+	Name string
+}
+
+// assigned returns the Assignment that is the result of b, which
+// assigned v.
+func assigned(context value.Context, b *binary, v value.Value) Assignment {
+	a := Assignment{Value: v}
+	if b.op == "::=" || context.Config().EchoAssign() {
+		a.Name = b.left.ProgString()
+	}
+	return a
+}
+
+// printed returns the printed form of v, the value of an expression,
+// and whether it is printed at all, which the result of an assignment
+// is only if it has a Name.
+func printed(conf *config.Config, v value.Value) (string, bool) {
+	if a, ok := v.(Assignment); ok {
+		if a.Name == "" {
+			return "", false
+		}
+		return a.Name + " = " + a.Value.Sprint(conf), true
+	}
+	return v.Sprint(conf), true
 }
 
 var scalarShape = []int{1} // The assignment shape vector for a scalar value.
@@ -32,12 +62,12 @@ func assignment(context value.Context, b *binary) value.Value {
 		} else {
 			context.AssignGlobal(lhs.name, rhs)
 		}
-		return Assignment{Value: rhs}
+		return assigned(context, b, rhs)
 	case *index:
-		switch lhs.left.(type) {
+		switch v := lhs.left.(type) {
 		case *variableExpr:
-			value.IndexAssign(context, lhs, lhs.left, lhs.right, b.right, rhs)
-			return Assignment{Value: rhs}
+			value.IndexAssign(context, lhs, indexedVar{v, true}, lhs.right, b.right, rhs)
+			return assigned(context, b, rhs)
 		case *index:
 			// Old x[i][j]. Show new syntax.
 			n := 0
@@ -58,3 +88,20 @@ func assignment(context value.Context, b *binary) value.Value {
 	value.Errorf("cannot assign to %s", b.left.ProgString())
 	panic("not reached")
 }
+
+// indexedVar is a variable being indexed, as x in x[i] or x[i] = v.
+// Unlike the variable itself, it does not share its value when
+// evaluated, as indexing copies the elements it selects. As the target
+// of an indexed assignment, which updates it in place, it first makes
+// sure the variable has its own copy of its value; see exec.Context.Own.
+type indexedVar struct {
+	*variableExpr
+	assign bool
+}
+
+func (v indexedVar) Eval(context value.Context) value.Value {
+	if v.assign {
+		return v.defined(context.(*exec.Context).Own(v.local, v.name))
+	}
+	return v.lookup(context)
+}
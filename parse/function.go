@@ -17,6 +17,7 @@ import (
 //	"op" name arg <eol>
 //	"op" name arg '=' statements <eol>
 //	"op" arg name arg '=' statements <eol>
+//	"op" '(' arg ')' name arg '=' statements <eol>
 //
 // statements:
 //	expressionList
@@ -27,11 +28,29 @@ func (p *Parser) functionDefn() {
 	fn := new(exec.Function)
 	// Two identifiers means: op arg.
 	// Three identifiers means: arg op arg.
+	// A parenthesized first identifier is an operator parameter,
+	// which makes the op binary: (f) op arg.
 	idents := make([]string, 2, 3)
-	idents[0] = p.need(scan.Identifier).Text
-	idents[1] = p.need(scan.Identifier).Text
-	if p.peek().Type == scan.Identifier {
-		idents = append(idents, p.next().Text)
+	if p.peek().Type == scan.LeftParen {
+		p.next()
+		fn.LeftOp = true
+		idents[0] = p.need(scan.Identifier).Text
+		p.need(scan.RightParen)
+		idents[1] = p.need(scan.Identifier).Text
+		// Once declared, the operator parameter scans as an operator,
+		// but it may be repeated as a placeholder, as in op (_) f _,
+		// which declares f, as )save writes.
+		tok := p.need(scan.Identifier, scan.Operator)
+		if tok.Type == scan.Operator && tok.Text != idents[0] {
+			p.errorf("expected Identifier, got %s", tok)
+		}
+		idents = append(idents, tok.Text)
+	} else {
+		idents[0] = p.need(scan.Identifier).Text
+		idents[1] = p.need(scan.Identifier).Text
+		if p.peek().Type == scan.Identifier {
+			idents = append(idents, p.next().Text)
+		}
 	}
 	tok := p.next()
 	// Install the function in the symbol table so recursive ops work. (As if.)
@@ -44,7 +63,11 @@ func (p *Parser) functionDefn() {
 		fn.Left = idents[0]
 		fn.Name = idents[1]
 		fn.Right = idents[2]
-		p.context.Declare(fn.Left)
+		if fn.LeftOp {
+			p.context.DeclareOp(fn.Left)
+		} else {
+			p.context.Declare(fn.Left)
+		}
 		p.context.Declare(fn.Right)
 		installMap = p.context.BinaryFn
 	} else {
@@ -73,6 +96,12 @@ func (p *Parser) functionDefn() {
 
 	switch tok.Type {
 	case scan.Assign:
+		if tok.Text != "=" {
+			p.errorf("expected = in function declaration, found %s", tok.Text)
+		}
+		if fn.LeftOp && fn.Left == fn.Right {
+			p.errorf("operator parameter %q is also argument name", fn.Left)
+		}
 		// Either one line:
 		//	op x a = expression
 		// or multiple lines terminated by a blank line:
@@ -136,6 +165,21 @@ func references(c *exec.Context, body []value.Expr) []exec.OpDef {
 				if c.BinaryFn[e.op] != nil {
 					addReference(&refs, e.op, true)
 				}
+			case *opArg:
+				if c.BinaryFn[e.name] != nil {
+					addReference(&refs, e.name, true)
+				}
+			case *bind:
+				if c.BinaryFn[e.op] != nil {
+					addReference(&refs, e.op, true)
+				}
+			case *each:
+				if e.left == nil && c.UnaryFn[e.op] != nil {
+					addReference(&refs, e.op, false)
+				}
+				if e.left != nil && c.BinaryFn[e.op] != nil {
+					addReference(&refs, e.op, true)
+				}
 			}
 		})
 	}
@@ -211,7 +255,17 @@ func walk(expr value.Expr, assign bool, f func(value.Expr, bool)) {
 		walk(e.binary, false, f)
 	case *binary:
 		walk(e.right, false, f)
-		walk(e.left, e.op == "=", f)
+		walk(e.left, e.op == "=" || e.op == "::=", f)
+	case *shortCircuit:
+		walk(e.right, false, f)
+		walk(e.left, false, f)
+	case *each:
+		walk(e.right, false, f)
+		if e.left != nil {
+			walk(e.left, false, f)
+		}
+	case *bind:
+		walk(e.arg, false, f)
 	case *index:
 		for i := len(e.right) - 1; i >= 0; i-- {
 			x := e.right[i]
@@ -220,14 +274,23 @@ func walk(expr value.Expr, assign bool, f func(value.Expr, bool)) {
 			}
 		}
 		walk(e.left, false, f)
+	case *value.Slice:
+		for _, x := range []value.Expr{e.Step, e.Stop, e.Start} {
+			if x != nil {
+				walk(x, false, f)
+			}
+		}
 	case *variableExpr:
+	case *opArg:
 	case sliceExpr:
 		for i := len(e) - 1; i >= 0; i-- {
 			walk(e[i], false, f)
 		}
+	case value.Bool:
 	case value.Char:
 	case value.Int:
 	case value.BigInt:
+	case value.Decimal:
 	case value.BigRat:
 	case value.BigFloat:
 	case value.Complex:
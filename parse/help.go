@@ -10,7 +10,18 @@ var helpLines = []string{
 	"the next paragraph).  It uses exact rational arithmetic so it can",
 	"handle arbitrary precision. Values to be input may be integers (3,",
 	"-1), rationals (1/3, -45/67) or floating point values (1e3, -1.5",
-	"(representing 1000 and -3/2)).",
+	"(representing 1000 and -3/2)). As in Go, underscores may separate",
+	"digits for readability: 1_000_000 is a million. An underscore must",
+	"appear between two digits, so 1__0, 10_, 1_/3, 1e_5 and 0x_ff are errors.",
+	"A long integer may instead be written as 0d{...}, whose digits, in the",
+	"input base, may be separated by spaces and newlines, as when a number",
+	"is pasted from a document: 0d{1 000 000} is also a million.",
+	"",
+	"A backslash at the end of a line joins the line to the next. This",
+	"works anywhere, even inside a number or string, so a long number",
+	"wrapped across lines may be entered by ending each line but the last",
+	"with a backslash. The exception is a comment, which always ends with",
+	"its line.",
 	"",
 	"Some functions such as sqrt are irrational. When ivy evaluates an",
 	"irrational function, the result is stored in a high-precision",
@@ -39,23 +50,141 @@ var helpLines = []string{
 	"so 1/2j-3/2 is the complex number 0.5-1.5i and scans as a single",
 	"value.",
 	"",
+	"For calculations with money and the like, ivy also has decimal numbers,",
+	"which hold exactly the digits written, so 19.90 prints as 19.90. After",
+	") numbers decimal, numbers written with a decimal point or exponent are",
+	"decimals, and the decimal operator converts other numbers. Sums,",
+	"differences and products of decimals are exact, so 0.1+0.2 is 0.3. A",
+	"quotient that is not a finite decimal, such as 1.00/3, is an error",
+	"unless ) scale sets the number of decimal places to round it to, using",
+	"the ) rounding mode. Mixing a decimal with a rational gives a rational.",
+	"",
+	"Comparisons and logical operators such as == and and yield 1 or 0,",
+	"which are ordinary integers, so +/x>0 counts the positive elements of",
+	"x. After ) strictbool 1 they yield booleans instead, which print as 1",
+	"or 0, or as true and false after ) boolwords 1, and which are an error",
+	"when used as numbers, so a slip like (a==b)+1 is caught. Booleans may",
+	"still be combined with logical operators, compared for equality and",
+	"used to select elements with sel.",
+	"",
 	"Indexing uses [] notation: x[1], x[1; 2], and so on. Indexing by a",
 	"vector selects multiple elements: x[1 2] creates a new item from",
 	"x[1] and x[2]. An empty index slot is a shorthand for all the",
 	"elements along that dimension, so x[] is equivalent to x, and x[;3]",
 	"gives the third column of two-dimensional array x.",
+	"A slot may hold a slice, start:stop or start:stop:step, which selects",
+	"the elements from start to stop inclusive: in origin 1, x[2:4] is",
+	"x[2 3 4] and x[5:1:-2] is x[5 3 1]. A missing start or stop means the",
+	"end of the dimension, so x[3:] drops the first two elements and x[::-1]",
+	"runs backwards through x. Bounds beyond the ends of the dimension are errors.",
+	"Indexing can also appear on the left of an assignment, as in",
+	"x[1] = 0. Such an assignment changes only the variable being",
+	"assigned, so after b = a, setting b[1] leaves a unchanged.",
+	"",
+	"As in APL, an operation whose result is a single item by its nature,",
+	"such as indexing by scalars, a reduction of a vector, or looking up a",
+	"scalar with iota or in, yields a scalar. Operations that select from a",
+	"vector, such as take, drop, sel and compression, always yield a vector,",
+	"even of one element, as does ravel. first and ravel convert one into",
+	"the other: first 1 take x is the scalar x[1], and ,3 is a vector.",
 	"",
 	"Only a subset of APL's functionality is implemented, but all numerical",
 	"operations are supported.",
 	"",
+	"Although ivy's operators have ASCII names, many may also be spelled",
+	"with the APL symbols in the tables below, such as × for * (and sgn),",
+	"÷ for / (and reciprocal), ⌈ for max (and ceil), ⍴ for rho, ↑ for take",
+	"(and head), ∧ and ∨ for and and or, and ≤ ≥ ≠ for <= >= !=. The",
+	"mathematical signs − (minus) and ⋆ (star) stand for - and **. The",
+	"symbols work in reductions and products too, as in ×/ and ∨.∧.",
+	"APL's residue, A∣B, is B mod A, so ∣ is only abs. ) glyphs 0 turns",
+	"the symbols off.",
+	"",
+	"A reduction with a left operand, as in 10 +/ x, folds the elements of x",
+	"into that initial value. As reductions evaluate from the right, the",
+	"initial value is placed on the right, so 10 -/ 1 2 3 is 1-(2-(3-10)).",
+	"If x is empty, the result is the initial value.",
+	"",
+	"Without an initial value, the reduction of an empty vector, or of the",
+	"rows of a matrix with no columns, is the identity of the operator: 0 for",
+	"+ - or xor | ^, and 1 for * / and. Thus +/ (x > 100) sel x is 0 if no",
+	"element of x is over 100. Other operators, including max and min and",
+	"user-defined ops, have no identity, and their reduction of an empty",
+	"vector is an error.",
+	"",
+	"A scan with max or min gives the running maximum or minimum, so",
+	"max\\ 1 3 2 5 4 is 1 3 3 5 5. Like those with + and *, these scans",
+	"take time proportional to the length of x.",
+	"",
+	"An exclusive scan, written with a doubled backslash as in +\\\\x, is like",
+	"a scan but element k of the result reduces only the elements before",
+	"element k of x, so +\\\\1 2 3 is 0 1 3, the offsets at which pieces of",
+	"lengths 1, 2 and 3 start when laid end to end. The first element of the",
+	"result is the identity of the operator, which must have one.",
+	"",
+	"The each adverb, written after an operator, applies the operator to each",
+	"item of its operands rather than to the operands as a whole. The items",
+	"of a vector are its elements, and those of a matrix are its rows, or",
+	"more generally its subarrays along the first axis. Thus +/ each m",
+	"sums each row of m, and x , each y joins the rows of x and y in",
+	"pairs. A scalar operand of a binary operator is paired with every item",
+	"of the other operand. As ivy has no nested arrays, the results must be",
+	"scalars, which form a vector, or arrays of a single shape, which form a",
+	"matrix. The exception is text: strings of different lengths are padded",
+	"with blanks to form a matrix with one string per row, so type each x",
+	"lists the types of the elements of x. After an operator, the word each",
+	"is the adverb when an operand follows it, and otherwise a variable.",
+	"",
 	"Semicolons separate multiple statements on a line. Variables are",
 	"alphanumeric and are assigned with the = operator. Assignment is",
-	"an expression.",
+	"an expression. A variable may not have the name of an operator, and",
+	"defining an op with the name of a variable removes the variable.",
+	"A line ending in a semicolon is evaluated but, like an assignment,",
+	"its result is not printed.",
+	"An assignment written with ::= rather than = is printed, after the",
+	"name of the variable, so x ::= 2**10 prints x = 1024. The special",
+	"command ) echo assign on prints all assignments that way.",
+	"",
+	"Expressions are evaluated from right to left, matching the grammar.",
+	"The right operand of a binary operator is evaluated before the left,",
+	"the elements of a vector such as (f 1) (f 2) from last to first, and",
+	"the indexes of x[i; j] from last to first before x itself, so when ops",
+	"have side effects, as an op that assigns a global variable may, they",
+	"happen in that order. Thus x=1000; x + x=2 is 4. The exception is",
+	"&& and ||, which evaluate their left operand first and their right",
+	"only if needed.",
 	"",
 	"After each successful expression evaluation, the result is stored",
 	"in the variable called _ (underscore) so it can be used in the next",
 	"expression.",
 	"",
+	"In a shell pipeline, as in echo '2**100' | ivy -q, the -q flag reads",
+	"standard input without printing prompts or blank lines between results,",
+	"so the output is just the results. The -last flag prints only the result",
+	"of the last line that has one. Errors are printed to standard error.",
+	"The -echo flag prints each line of input, after a \"> \" marker, before",
+	"its results, so the output of a script reads like a session.",
+	"",
+	"A script can take parameters. The -set flag, which may be repeated,",
+	"sets a variable to a number, or to a vector of numbers separated by",
+	"spaces, before the script runs, as in ivy -set n=1000 primes.ivy.",
+	"Within a script, env 'NAME' reads the number or numbers held in the",
+	"environment variable NAME; it is an error if NAME is unset or does not",
+	"hold numbers.",
+	"",
+	"The environment variables IVY_FORMAT, IVY_ORIGIN and IVY_PREC set the",
+	"defaults for ) format, ) origin and ) prec, as in IVY_ORIGIN=0 ivy.",
+	"Invalid values are ignored, and the -format, -g and -origin flags",
+	"override them.",
+	"",
+	"When ivy reads standard input, it first runs the prelude file",
+	"$HOME/.ivyrc, if it exists, so it can hold personal constants and ops.",
+	"The -rc flag names a different prelude, which is then run whatever",
+	"the input, and -norc skips it. Errors in the prelude are reported with",
+	"the file and line but do not stop ivy. ) save leaves out the variables",
+	"and ops defined by the prelude, unless they have changed since, as the",
+	"prelude will define them again; ) save \"file\" all writes them too.",
+	"",
 	"The APL operators, adapted from",
 	"https://en.wikipedia.org/wiki/APL_syntax_and_symbols, and their",
 	"correspondence are listed here. The correspondence is incomplete",
@@ -67,28 +196,51 @@ var helpLines = []string{
 	"\tRoll              ?B    ?       One integer selected randomly from the first B integers",
 	"\tCeiling           ⌈B    ceil    Least integer greater than or equal to B",
 	"\tFloor             ⌊B    floor   Greatest integer less than or equal to B",
+	"\tRound                   round   Integer nearest to B; halves go to the even integer",
+	"\tTruncate                trunc   Integer part of B, rounded toward zero",
 	"\tShape             ⍴B    rho     Number of components in each dimension of B",
 	"\tNot               ∼B    not     Logical: not 1 is 0, not 0 is 1",
 	"\tAbsolute value    ∣B    abs     Magnitude of B",
 	"\tIndex generator   ⍳B    iota    Vector of the first B integers",
 	"\tExponential       ⋆B    **      e to the B power",
 	"\tNegation          −B    -       Changes sign of B",
-	"\tIdentity          +B    +       No change to B",
+	"\tConjugate         +B    +       Complex conjugate of B; no change to a real B",
 	"\tSignum            ×B    sgn     ¯1 if B<0; 0 if B=0; 1 if B>0",
 	"\tReciprocal        ÷B    /       1 divided by B",
 	"\tRavel             ,B    ,       Reshapes B into a vector",
-	"\tMatrix inverse    ⌹B            Inverse of matrix B",
+	"\tMatrix inverse    ⌹B    inverse Inverse of square matrix B",
+	"\tDeterminant             det     Determinant of square matrix B",
 	"\tPi times          ○B            Multiply by π",
 	"\tLogarithm         ⍟B    log     Natural logarithm of B",
 	"\tReversal          ⌽B    rot     Reverse elements of B along last axis",
 	"\tReversal          ⊖B    flip    Reverse elements of B along first axis",
 	"\tGrade up          ⍋B    up      Indices of B which will arrange B in ascending order",
 	"\tGrade down        ⍒B    down    Indices of B which will arrange B in descending order",
+	"\tMaximum           ⌈/B   max     Largest element of B",
+	"\tMinimum           ⌊/B   min     Smallest element of B",
+	"\tIndex of maximum        maxpos  Index of the first largest element of vector B",
+	"\tIndex of minimum        minpos  Index of the first smallest element of vector B",
+	"\tDifferences             diff    Differences of adjacent elements of vector B",
+	"\tUnique            ∪B    unique  Distinct elements of B, in order of first appearance",
+	"\tFirst                   head    First element of vector B",
+	"\tFirst                   first   First element of B, of any rank, as a scalar",
+	"\tLast                    last    Final element of vector B",
+	"\tTail                    tail    All but the first element of vector B",
+	"\tInit                    init    All but the final element of vector B",
 	"\tExecute           ⍎B    ivy     Execute an APL (ivy) expression",
 	"\tMonadic format    ⍕B    text    A character representation of B",
+	"\tType                    type    The name of the type of B: 'bool', 'int', 'bigint', 'rat',",
+	"\t                                'decimal', 'float', 'complex', 'char', 'vector' or 'matrix'",
+	"\tEnvironment             env     The numbers in the environment variable named by B",
+	"\tRead bytes              readbytes The bytes of the file named by B, as integers 0 to 255",
+	"\t                                  The file may be no larger than ) maxread bytes",
 	"\tMonadic transpose ⍉B    transp  Reverse the axes of B",
 	"\tFactorial         !B    !       Product of integers 1 to B",
 	"\tBitwise not             ^       Bitwise complement of B (integer only)",
+	"\tPopulation count        popcount Number of one bits in B (non-negative integer only)",
+	"\tBit length              bitlength Number of bits needed to hold abs B (integer only)",
+	"\tTo bits                 tobits  Binary digits of B, most significant first: tobits 10 is 1 0 1 0",
+	"\tFrom bits               frombits Integer with binary digits B, most significant first",
 	"\tSquare root       B⋆.5  sqrt    Square root of B.",
 	"\tSine                    sin     sin(A); APL uses binary ○ (see below)",
 	"\tCosine                  cos     cos(A); ditto",
@@ -96,6 +248,9 @@ var helpLines = []string{
 	"\tArcsine                 asin    arcsin(B)",
 	"\tArccosine               acos    arccos(B)",
 	"\tArctangent              atan    arctan(B)",
+	"\t                                Angles are in radians unless ) angle degrees is set",
+	"\tDegrees to radians      torad   B degrees in radians, in floating point: torad 180 is pi",
+	"\tRadians to degrees      todeg   B radians in degrees, in floating point: todeg pi is 180",
 	"\tHyperbolic sine         sinh    sinh(B)",
 	"\tHyperbolic cosine       cosh    cosh(B)",
 	"\tHyperbolic tangent      tanh    tanh(B)",
@@ -103,6 +258,9 @@ var helpLines = []string{
 	"\tHyperbolic arccosine    acosh   arccosh(B)",
 	"\tHyperbolic arctangent   atanh   arctanh(B)",
 	"\tRotation by 90°         j       Multiplication by sqrt(-1)",
+	"\tNumerator               num     Numerator of B in lowest terms, with the sign of B",
+	"\tDenominator             den     Denominator of B in lowest terms; 1 for integers",
+	"\tMixed fraction          mixed   Whole part of B, toward zero, and the rest: mixed -7/3 is -2 -1/3",
 	"\tReal part               real    Real component of the value",
 	"\tImaginary part          imag    Imaginary component of the value",
 	"\tPhase                   phase   Phase of the value in the complex plane (-π to π)",
@@ -135,21 +293,51 @@ var helpLines = []string{
 	"\t                            mod     A modulo B (Euclidean)",
 	"\t                            imod    A modulo B (Go)",
 	"\tCatenation            A,B   ,       Elements of B appended to the elements of A",
+	"\t                                    Each element keeps its type, so 1 , 1/2 is 1 1/2",
 	"\tExpansion             A\\B   fill    Insert zeros (or blanks) in B corresponding to zeros in A",
 	"\t                                    In ivy: abs(A) gives count, A <= 0 inserts zero (or blank)",
 	"\tCompression           A/B   sel     Select elements in B corresponding to ones in A",
 	"\t                                    In ivy: abs(A) gives count, A <= 0 inserts zero",
 	"\tIndex of              A⍳B   iota    The location (index) of B in A; 1+⌈/⍳⍴A if not found",
 	"\t                                    In ivy: origin-1 if not found (i.e. 0 if one-indexed)",
-	"\tMatrix divide         A⌹B           Solution to system of linear equations Ax = B",
+	"\tArithmetic sequence         range   B numbers starting at A[1] in steps of A[2]",
+	"\t                                    2 3 range 4 is 2 5 8 11",
+	"\tPick                        pick    For each 1 or 0 in A, the element of the first or second row of B",
+	"\t                                    B has two rows as long as A: 1 0 pick 2 2 rho 1 2 3 4 is 1 4",
+	"\tInterleave                  zip     The elements of A and B alternately",
+	"\t                                    1 2 3 zip 4 5 6 is 1 4 2 5 3 6",
+	"\tPartition                   partition",
+	"\t                                    Matrix whose rows are the successive A elements of B",
+	"\t                                    The last row is padded with zeros (or blanks)",
+	"\tSliding windows             windows Matrix whose rows are the runs of A elements of B",
+	"\t                                    (+/ 3 windows B)/3 is the moving average of B",
+	"\tMatch                 A≡B   match   1 if A and B have the same shape and elements; 0 if not",
+	"\tLexical comparison          lexcmp  -1, 0 or 1 as vector A sorts before, with or after B",
+	"\t                                    Elements are compared in turn; a prefix sorts first",
+	"\tPromotion                   promote B with every element converted to the type named by A",
+	"\t                                    A is 'int', 'rat', 'decimal', 'float' or 'complex'",
+	"\t                                    'int' promote 1/3 is an error; nothing is truncated",
+	"\tMatrix divide         A⌹B   solve   Solution x of the linear equations A +.* x == B, exactly",
+	"\t                                    A is square; B is a vector, or a matrix of columns to solve",
+	"\t                                    (2 2 rho 2 1 1 3) solve 3 5 is 4/5 7/5",
 	"\tRotation              A⌽B   rot     The elements of B are rotated A positions left",
 	"\tRotation              A⊖B   flip    The elements of B are rotated A positions along the first axis",
 	"\tLogarithm             A⍟B   log     Logarithm of B to base A",
+	"\tSquare root                 sqrtn   Square root of B truncated to A decimal places, as an",
+	"\t                                    exact rational: 2 sqrtn 2 is 141/100",
+	"\tRound to multiple           roundto Multiple of B nearest to A, exactly; B must be positive",
+	"\t                                    Halves go to the even multiple",
+	"\t                                    3.14159 roundto 1/100 is 157/50",
 	"\tDyadic format         A⍕B   text    Format B into a character matrix according to A",
 	"\t                                    A is the textual format (see format special command);",
 	"\t                                    otherwise result depends on length of A:",
 	"\t                                    1 gives decimal count, 2 gives width and decimal count,",
 	"\t                                    3 gives width, decimal count, and style ('d', 'e', 'f', etc.).",
+	"\tFormat                      fmt     Format B as text according to A without changing settings",
+	"\t                                    An integer A is the base, as for ) obase: 16 fmt 255 is 'ff'",
+	"\t                                    Otherwise A is a format as for text: '%08b' fmt 5 is '00000101'",
+	"\tWrite bytes                 writebytes Write integers 0 to 255 in B to the file named by A",
+	"\t                                    as bytes, replacing its contents; the result is the count",
 	"\tGeneral transpose     A⍉B   transp  The axes of B are ordered by A",
 	"\tCombinations          A!B   !       Number of combinations of B taken A at a time",
 	"\tLess than             A<B   <       Comparison: 1 if true, 0 if false",
@@ -168,15 +356,43 @@ var helpLines = []string{
 	"\tBitwise xor                 ^       Bitwise A exclusive or B (integer only)",
 	"\tLeft shift                  <<      A shifted left B bits (integer only)",
 	"\tRight Shift                 >>      A shifted right B bits (integer only)",
+	"\t                                    Arithmetic: negative A stays negative, rounding down,",
+	"\t                                    so -5 >> 1 is -3; see ) logicalshift",
+	"\tBit                         bit     Bit B of A, 0 or 1, counting from ) origin at the",
+	"\t                                    least significant bit (integer only)",
+	"\tSet bit                     setbit  A with bit B set to 1 (integer only)",
+	"\tClear bit                   clearbit A with bit B set to 0 (integer only)",
+	"\tRotate left                 rotl    Values in A rotated left B bits within a word;",
+	"\t                                    A is the word width followed by the values",
+	"\t                                    Negative values are truncated to the width as",
+	"\t                                    two's complement, and B is taken modulo the width",
+	"\t                                    (32 0x80000001) rotl 1 is 3",
+	"\tRotate right                rotr    Values in A rotated right B bits within a word,",
+	"\t                                    as for rotl",
+	"\tWrapping add                wadd    Sum of the pair B in a word of A bits, wrapping around;",
+	"\t                                    A is a width, for signed words, or a width",
+	"\t                                    followed by 1 for signed or 0 for unsigned",
+	"\t                                    8 wadd 100 100 is -56; (8 0) wadd 200 100 is 44",
+	"\tWrapping subtract           wsub    Difference of the pair B, wrapping as for wadd",
+	"\tWrapping multiply           wmul    Product of the pair B, wrapping as for wadd",
+	"\tSaturating add              sadd    Sum of the pair B in a word of A bits, limited",
+	"\t                                    to the range of the word; A is as for wadd",
+	"\t                                    8 sadd 100 100 is 127",
+	"\tSaturating subtract         ssub    Difference of the pair B, saturating as for sadd",
+	"\tSaturating multiply         smul    Product of the pair B, saturating as for sadd",
 	"\tComplex construction        j       The complex number A+Bi",
 	"",
 	"Operators and axis indicator",
 	"",
 	"\tName                APL  Ivy  APL Example  Ivy Example  Meaning (of example)",
 	"\tReduce (last axis)  /    /    +/B          +/B          Sum across B",
+	"\t                                           A +/B        Sum across B, starting from A",
 	"\tReduce (first axis) ⌿         +⌿B                       Sum down B",
 	"\tScan (last axis)    \\    \\    +\\B          +\\B          Running sum across B",
+	"\tExclusive scan           \\\\                +\\\\B         Sums of the elements before each of B",
 	"\tScan (first axis)   ⍀         +⍀B                       Running sum down B",
+	"\tEach                ¨    each +/¨B         +/ each B    Sum of each item of B",
+	"\t                              A,¨B         A , each B   Join items of A and B in pairs",
 	"\tInner product       .    .    A+.×B        A +.* B      Matrix product of A and B",
 	"\tOuter product       ∘.   o.   A∘.×B        A o.* B      Outer product of A and B",
 	"\t                                                    (lower case o; may need preceding space)",
@@ -189,12 +405,13 @@ var helpLines = []string{
 	"\tFloat                   float B The floating-point representation of B;",
 	"\t                                for complex numbers, the result is",
 	"\t                                (float A)j(float B)",
+	"\tDecimal                 decimal B The exact decimal representation of B",
 	"",
 	"Pre-defined constants",
 	"",
-	"The constants e (base of natural logarithms) and pi (π) are pre-defined to high",
-	"precision, about 3000 decimal digits truncated according to the floating point",
-	"precision setting.",
+	"The constants e (base of natural logarithms) and pi (π) are pre-defined to the",
+	"floating point precision setting, and are recomputed when it changes. They are",
+	"read-only.",
 	"",
 	"Character data",
 	"",
@@ -244,6 +461,14 @@ var helpLines = []string{
 	"operator has a lower precedence than any other operator; in effect it breaks",
 	"the line into two separate expressions.",
 	"",
+	"The binary operators && and || are logical and and or that evaluate",
+	"their right operand only if needed: 0 && x and 1 || x do not evaluate x.",
+	"Unlike other operators they evaluate their left operand first. Both",
+	"operands must be 0 or 1; for vectors, use the elementwise and and or.",
+	"As with other operators, the left operand is the single operand to",
+	"the left, so a condition is usually parenthesized:",
+	"\t(n != 0) && (100/n) > 3",
+	"",
 	"Example: average of a vector (unary):",
 	"\top avg x = (+/x)/rho x",
 	"\tavg iota 11",
@@ -276,6 +501,43 @@ var helpLines = []string{
 	"\t1562 gcd !11",
 	"\tresult: 22",
 	"",
+	"The left argument of a binary operator may instead be an operator parameter,",
+	"written in parentheses, as in \"op (f) name arg\". The caller passes the name",
+	"of a binary operator, built-in or user-defined, in parentheses as the left",
+	"operand, and the body applies it under the parameter's name, including in",
+	"reductions and products such as f/ and o.f. Operators are passed only by",
+	"name; an operator in parentheses is not a value and can be used only as",
+	"such an operand.",
+	"",
+	"Example: fold and table (operator parameter):",
+	"\top (f) fold v = (rho v) == 1: v[1]; v[1] f (f) fold 1 drop v",
+	"\t(max) fold 3 1 4 1 5",
+	"\tresult: 5",
+	"\top (f) table v = v o.f v",
+	"\t(*) table iota 3",
+	"\tresult:",
+	"\t1 2 3",
+	"\t2 4 6",
+	"\t3 6 9",
+	"",
+	"The bind operator makes a unary operator from a binary one by fixing one",
+	"of its operands: A bind (f) applies f with A as its left operand, and",
+	"(f) bind B with B as its right one. The result may be assigned to a",
+	"variable, which is then applied like a unary operator, including with",
+	"each. It is not a number, though, and using it as an operand is an error.",
+	"Like any unary operator, it applies to everything to its right, so with",
+	"double as below, double + 1 is double applied to +1, which is 2.",
+	"A bound operator is applied by name only when it is held in a global",
+	"variable assigned on an earlier line.",
+	"",
+	"Example: bind:",
+	"\tdouble = 2 bind (*)",
+	"\tdouble 21",
+	"\tresult: 42",
+	"\tdec = (-) bind 1",
+	"\tdec each 5 10",
+	"\tresult: 4 9",
+	"",
 	"On mobile platforms only, due to I/O restrictions, user-defined operators",
 	"must be presented on a single line. Use semicolons to separate expressions:",
 	"",
@@ -315,21 +577,52 @@ var helpLines = []string{
 	"\t) help",
 	"\t\tDescribe the special commands. Run )help <topic> to learn more",
 	"\t\tabout a topic, )help <op> to learn more about an operator.",
+	"\t) angle radians",
+	"\t\tSet the units of the angles taken by sin, cos and tan and",
+	"\t\treturned by asin, acos and atan: radians or degrees. With",
+	"\t\tdegrees, an exact angle is reduced exactly modulo 360 before",
+	"\t\tit is converted to radians, so sin 30 is 1/2 to the floating-",
+	"\t\tpoint precision however many times 360 is added to 30.",
 	"\t) base 0",
 	"\t\tSet the number base for input and output. The commands ibase and",
 	"\t\tobase control setting of the base for input and output alone,",
-	"\t\trespectively.  Base 0 allows C-style input: decimal, with 037 being",
-	"\t\toctal and 0x10 being hexadecimal. Bases above 16 are disallowed.",
+	"\t\trespectively.  Base 0 allows Go-style input: decimal, with 0o37 being",
+	"\t\toctal, 0x10 being hexadecimal and 0b101 being binary. Other leading",
+	"\t\tzeros are ignored, so 037 is decimal. Bases above 16 are disallowed.",
 	"\t\tTo output large integers and rationals, base must be one of",
 	"\t\t0 2 8 10 16. Floats are always printed base 10.",
+	"\t) boolwords 0",
+	"\t\tIf 1, print booleans, made by comparisons when ) strictbool is",
+	"\t\tset, as true and false rather than 1 and 0.",
+	"\t) builtin name",
+	"\t\tSay whether the name is that of an operator, built in or defined",
+	"\t\tby op, or of a variable, or is free to use.",
 	"\t) cpu",
 	"\t\tPrint the duration of the last interactive calculation.",
 	"\t) debug name 0|1",
 	"\t\tToggle or set the named debugging flag. With no argument, lists",
-	"\t\tthe settings.",
+	"\t\tthe settings. The trace flag prints each operation as it is",
+	"\t\tevaluated, applied to the values of its operands, and its result,",
+	"\t\tsuch as 3 * 4 -> 12, innermost first.",
+	"\t) decimal \".\"",
+	"\t\tSet the character printed as the decimal point of numbers shown",
+	"\t\tin floating-point format, such as ) decimal \",\". It affects output",
+	"\t\tonly; input always uses a period, as do saved files.",
 	"\t) demo",
 	"\t\tRun a line-by-line interactive demo. On mobile platforms,",
 	"\t\tuse the Demo menu option instead.",
+	"\t) echo 0",
+	"\t\tIf 1, print each non-blank line of input, including comments,",
+	"\t\tafter the marker \"> \" as it is read, so the output of a script",
+	"\t\tshows the input too. ) echo \"marker\" turns echoing on with",
+	"\t\tanother marker, such as \"\\t\".",
+	"\t) echo assign off",
+	"\t\tIf on (or 1), print the value of each statement that is an",
+	"\t\tassignment, after the name of the variable, as in x = 1024.",
+	"\t) empty \"\"",
+	"\t\tSet the string printed for a value with no elements, such as",
+	"\t\tiota 0. By default it is empty, so such values print as a blank",
+	"\t\tline; APL programmers may prefer ) empty \"⍬\".",
 	"\t) format \"\"",
 	"\t\tSet the format for printing values. If empty, the output is printed",
 	"\t\tusing the output base. If non-empty, the format determines the",
@@ -339,6 +632,22 @@ var helpLines = []string{
 	"\t\tRead input from the named file; return to interactive execution",
 	"\t\tafterwards. If no file is specified, read from \"save.ivy\".",
 	"\t\t(Unimplemented on mobile.)",
+	"\t) glyphs on",
+	"\t\tIf on (or 1), the default, APL symbols such as ⍳ and × may be",
+	"\t\tused in place of the names of the operators they stand for, as",
+	"\t\tin ⍳5. If off (or 0), they are not recognized.",
+	"\t) import \"file\"",
+	"\t\tRead and execute the named file, typically a set of definitions",
+	"\t\tto use in the session. Unlike ) get, errors are reported line by",
+	"\t\tline and the rest of the file is still read. An unquoted name,",
+	"\t\tas in ) import stats, reads stats.ivy.",
+	"\t\t(Unimplemented on mobile.)",
+	"\t) logicalshift 0",
+	"\t\tIf non-zero, right shifts are logical rather than arithmetic:",
+	"\t\ta negative value is treated as the two's-complement word of this",
+	"\t\tmany bits and shifted in zeros, so after ) logicalshift 8,",
+	"\t\t-8 >> 1 is 124. A value that does not fit in the word is an error.",
+	"\t\tIf 0, the default, -8 >> 1 is -4.",
 	"\t) maxbits 1e6",
 	"\t\tTo avoid consuming too much memory, if an integer result would",
 	"\t\trequire more than this many bits to store, abort the calculation.",
@@ -347,9 +656,22 @@ var helpLines = []string{
 	"\t\tTo avoid overwhelming amounts of output, if an integer has more",
 	"\t\tthan this many digits, print it using the defined floating-point",
 	"\t\tformat. If maxdigits is 0, integers are always printed as integers.",
+	"\t) maxread 1e6",
+	"\t\tThe maximum size, in bytes, of a file read by readbytes; a larger",
+	"\t\tfile is an error. If maxread is 0, there is no limit.",
+	"\t) maxshift 1e6",
+	"\t\tTo avoid consuming too much memory, a left shift by more than",
+	"\t\tthis many bits is an error, whatever the setting of maxbits.",
+	"\t\tIf maxshift is 0, there is no limit; the default is 1e6.",
 	"\t) maxstack 1e5",
 	"\t\tTo avoid using too much stack, the number of nested active calls to",
 	"\t\tuser-defined operators is limited to maxstack.",
+	"\t\tAn op that calls itself, directly or through other ops, with",
+	"\t\tthe same exact operands before any global variable changes would",
+	"\t\tnever stop, and is reported at once rather than at the limit.",
+	"\t) numbers rational",
+	"\t\tSet the kind of number written with a decimal point or exponent,",
+	"\t\tas in 1.5 or 1e-3: rational (the default) or decimal.",
 	"\t) op X",
 	"\t\tIf X is absent, list all user-defined operators. Otherwise,",
 	"\t\tshow the definition of the user-defined operator X. Inside the",
@@ -360,15 +682,59 @@ var helpLines = []string{
 	"\t) prec 256",
 	"\t\tSet the precision (mantissa length) for floating-point values.",
 	"\t\tThe value is in bits. The exponent always has 32 bits.",
+	"\t) progress off",
+	"\t\tIf on (or 1), report how much of a long operation on a vector,",
+	"\t\tsuch as a reduction or elementwise arithmetic, is done, as a",
+	"\t\tpercentage printed to the error output. It stays off unless",
+	"\t\tthat is a terminal. Nothing is printed for an operation that",
+	"\t\ttakes less than a second, and the report is erased when it",
+	"\t\tfinishes.",
 	"\t) prompt \"\"",
 	"\t\tSet the interactive prompt.",
+	"\t) rounding away",
+	"\t\tSet how rationals printed with a fixed number of decimal places,",
+	"\t\tas by ) format \"%.2f\", are rounded: away (to nearest, with halves",
+	"\t\taway from zero), even (to nearest, with halves to even), zero",
+	"\t\t(truncate), up (toward +∞) or down (toward -∞).",
+	"\t) scale off",
+	"\t\tSet the number of decimal places to which quotients of decimals,",
+	"\t\tand rationals converted to decimals, are rounded. If off,",
+	"\t\tthe default, a result that is not a finite decimal is an error.",
 	"\t) save \"save.ivy\"",
 	"\t\tWrite definitions of user-defined operators and variables to the",
 	"\t\tnamed file, as ivy textual source. If no file is specified, save to",
-	"\t\t\"save.ivy\".",
+	"\t\t\"save.ivy\". Definitions from the prelude are left out unless",
+	"\t\tthe word all follows, as in ) save \"save.ivy\" all.",
 	"\t\t(Unimplemented on mobile.)",
+	"\t) separator \" \"",
+	"\t\tSet the string printed between the elements of a vector.",
+	"\t\tVectors of characters are printed without separators.",
 	"\t) seed 0",
-	"\t\tSet the seed for the ? operator.",
+	"\t\tSet the seed for the ? operator, making its results reproducible.",
+	"\t\t) seed time seeds the generator from the time of day (the default),",
+	"\t\tand ) seed crypto draws from the operating system's cryptographically",
+	"\t\tsecure generator. With no argument, print the seed, or crypto.",
+	"\t) state",
+	"\t\tPrint the version of ivy, the configuration, the names, types and",
+	"\t\tshapes of the variables, and the user-defined operators, for",
+	"\t\tincluding in bug reports. ) state json prints the same as JSON.",
+	"\t) strictbool 0",
+	"\t\tIf 1, comparisons and logical operators yield booleans rather",
+	"\t\tthan the integers 1 and 0, and using a boolean as a number,",
+	"\t\tas in (1==1)+1, is an error.",
+	"\t) time expression",
+	"\t\tEvaluate the expression, print its value and then how long",
+	"\t\tthe parsing and evaluation took, as in ) time 3**100000.",
+	"\t) write \"file\"",
+	"\t\tWrite the value of _, the last result printed, to the named",
+	"\t\tfile as raw bytes, as by \"file\" writebytes _.",
+	"\t) type off",
+	"\t\tIf on (or 1), print the name of the type of each result after",
+	"\t\tit, as the type operator reports it, as in 1/3 (rat).",
+	"\t) width 0",
+	"\t\tSet the maximum width of an output line. Longer vectors and",
+	"\t\thelp text are wrapped to fit. The default, 0, means the width of",
+	"\t\tthe terminal, or no limit if output is not to a terminal.",
 }
 
 type helpIndexPair struct {
@@ -376,99 +742,156 @@ type helpIndexPair struct {
 }
 
 var helpUnary = map[string]helpIndexPair{
-	"?":      {61, 61},
-	"ceil":   {62, 62},
-	"floor":  {63, 63},
-	"rho":    {64, 64},
-	"not":    {65, 65},
-	"abs":    {66, 66},
-	"iota":   {67, 67},
-	"**":     {68, 68},
-	"-":      {69, 69},
-	"+":      {70, 70},
-	"sgn":    {71, 71},
-	"/":      {72, 72},
-	",":      {73, 73},
-	"log":    {76, 76},
-	"rot":    {77, 77},
-	"flip":   {78, 78},
-	"up":     {79, 79},
-	"down":   {80, 80},
-	"ivy":    {81, 81},
-	"text":   {82, 82},
-	"transp": {83, 83},
-	"!":      {84, 84},
-	"^":      {85, 85},
-	"sqrt":   {86, 86},
-	"sin":    {87, 87},
-	"cos":    {88, 88},
-	"tan":    {89, 89},
-	"asin":   {90, 90},
-	"acos":   {91, 91},
-	"atan":   {92, 92},
-	"sinh":   {93, 93},
-	"cosh":   {94, 94},
-	"tanh":   {95, 95},
-	"asinh":  {96, 96},
-	"acosh":  {97, 97},
-	"atanh":  {98, 98},
-	"j":      {99, 99},
-	"real":   {100, 100},
-	"imag":   {101, 101},
-	"phase":  {102, 102},
-	"code":   {181, 181},
-	"char":   {182, 182},
-	"float":  {183, 185},
+	"?":         {190, 190},
+	"ceil":      {191, 191},
+	"floor":     {192, 192},
+	"round":     {193, 193},
+	"trunc":     {194, 194},
+	"rho":       {195, 195},
+	"not":       {196, 196},
+	"abs":       {197, 197},
+	"iota":      {198, 198},
+	"**":        {199, 199},
+	"-":         {200, 200},
+	"+":         {201, 201},
+	"sgn":       {202, 202},
+	"/":         {203, 203},
+	",":         {204, 204},
+	"inverse":   {205, 205},
+	"det":       {206, 206},
+	"log":       {208, 208},
+	"rot":       {209, 209},
+	"flip":      {210, 210},
+	"up":        {211, 211},
+	"down":      {212, 212},
+	"max":       {213, 213},
+	"min":       {214, 214},
+	"maxpos":    {215, 215},
+	"minpos":    {216, 216},
+	"diff":      {217, 217},
+	"unique":    {218, 218},
+	"head":      {219, 219},
+	"first":     {220, 220},
+	"last":      {221, 221},
+	"tail":      {222, 222},
+	"init":      {223, 223},
+	"ivy":       {224, 224},
+	"text":      {225, 225},
+	"type":      {226, 226},
+	"env":       {228, 228},
+	"readbytes": {229, 229},
+	"transp":    {231, 231},
+	"!":         {232, 232},
+	"^":         {233, 233},
+	"popcount":  {234, 234},
+	"bitlength": {235, 235},
+	"tobits":    {236, 236},
+	"frombits":  {237, 237},
+	"sqrt":      {238, 238},
+	"sin":       {239, 239},
+	"cos":       {240, 240},
+	"tan":       {241, 241},
+	"asin":      {242, 242},
+	"acos":      {243, 243},
+	"atan":      {244, 244},
+	"torad":     {246, 246},
+	"todeg":     {247, 247},
+	"sinh":      {248, 248},
+	"cosh":      {249, 249},
+	"tanh":      {250, 250},
+	"asinh":     {251, 251},
+	"acosh":     {252, 252},
+	"atanh":     {253, 253},
+	"j":         {254, 254},
+	"num":       {255, 255},
+	"den":       {256, 256},
+	"mixed":     {257, 257},
+	"real":      {258, 258},
+	"imag":      {259, 259},
+	"phase":     {260, 260},
+	"code":      {397, 397},
+	"char":      {398, 398},
+	"float":     {399, 401},
+	"decimal":   {402, 402},
 }
 
 var helpBinary = map[string]helpIndexPair{
-	"+":      {107, 107},
-	"-":      {108, 108},
-	"*":      {109, 109},
-	"/":      {110, 112},
-	"**":     {113, 113},
-	"?":      {119, 119},
-	"in":     {120, 120},
-	"max":    {121, 121},
-	"min":    {122, 122},
-	"rho":    {123, 123},
-	"take":   {124, 124},
-	"drop":   {125, 125},
-	"decode": {126, 126},
-	"encode": {127, 127},
-	"mod":    {129, 130},
-	",":      {131, 131},
-	"fill":   {132, 133},
-	"sel":    {134, 135},
-	"iota":   {136, 137},
-	"rot":    {139, 139},
-	"flip":   {140, 140},
-	"log":    {141, 141},
-	"text":   {142, 146},
-	"transp": {147, 147},
-	"!":      {148, 148},
-	"<":      {149, 149},
-	"<=":     {150, 150},
-	"==":     {151, 151},
-	">=":     {152, 152},
-	">":      {153, 153},
-	"!=":     {154, 154},
-	"or":     {155, 155},
-	"and":    {156, 156},
-	"nor":    {157, 157},
-	"nand":   {158, 158},
-	"xor":    {159, 159},
-	"&":      {160, 160},
-	"|":      {161, 161},
-	"^":      {162, 162},
-	"<<":     {163, 163},
-	">>":     {164, 164},
-	"j":      {165, 165},
+	"+":          {265, 265},
+	"-":          {266, 266},
+	"*":          {267, 267},
+	"/":          {268, 268},
+	"div":        {269, 269},
+	"idiv":       {270, 270},
+	"**":         {271, 271},
+	"?":          {277, 277},
+	"in":         {278, 278},
+	"max":        {279, 279},
+	"min":        {280, 280},
+	"rho":        {281, 281},
+	"take":       {282, 282},
+	"drop":       {283, 283},
+	"decode":     {284, 284},
+	"encode":     {285, 285},
+	"mod":        {287, 287},
+	"imod":       {288, 288},
+	",":          {289, 290},
+	"fill":       {291, 292},
+	"sel":        {293, 294},
+	"iota":       {295, 296},
+	"range":      {297, 298},
+	"pick":       {299, 300},
+	"zip":        {301, 302},
+	"partition":  {303, 305},
+	"windows":    {306, 307},
+	"match":      {308, 308},
+	"lexcmp":     {309, 310},
+	"promote":    {311, 313},
+	"solve":      {314, 316},
+	"rot":        {317, 317},
+	"flip":       {318, 318},
+	"log":        {319, 319},
+	"sqrtn":      {320, 321},
+	"roundto":    {322, 324},
+	"text":       {325, 329},
+	"fmt":        {330, 332},
+	"writebytes": {333, 334},
+	"transp":     {335, 335},
+	"!":          {336, 336},
+	"<":          {337, 337},
+	"<=":         {338, 338},
+	"==":         {339, 339},
+	">=":         {340, 340},
+	">":          {341, 341},
+	"!=":         {342, 342},
+	"or":         {343, 343},
+	"and":        {344, 344},
+	"nor":        {345, 345},
+	"nand":       {346, 346},
+	"xor":        {347, 347},
+	"&":          {348, 348},
+	"|":          {349, 349},
+	"^":          {350, 350},
+	"<<":         {351, 351},
+	">>":         {352, 354},
+	"bit":        {355, 356},
+	"setbit":     {357, 357},
+	"clearbit":   {358, 358},
+	"rotl":       {359, 363},
+	"rotr":       {364, 365},
+	"wadd":       {366, 369},
+	"wsub":       {370, 370},
+	"wmul":       {371, 371},
+	"sadd":       {372, 374},
+	"ssub":       {375, 375},
+	"smul":       {376, 376},
+	"j":          {377, 377},
 }
 
 var helpAxis = map[string]helpIndexPair{
-	"/":  {170, 170},
-	"\\": {172, 172},
-	".":  {174, 174},
-	"o.": {175, 175},
+	"/":    {382, 383},
+	"\\":   {385, 385},
+	"\\\\": {386, 386},
+	"each": {388, 389},
+	".":    {390, 390},
+	"o.":   {391, 392},
 }
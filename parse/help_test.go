@@ -0,0 +1,27 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parse
+
+import (
+	"testing"
+
+	"robpike.io/ivy/value"
+)
+
+// TestHelpCoversOps verifies that every operator is documented.
+// The help text is generated from the tables in ../doc.go;
+// after adding an operator, describe it there and run go generate.
+func TestHelpCoversOps(t *testing.T) {
+	for name := range value.UnaryOps {
+		if _, ok := helpUnary[name]; !ok {
+			t.Errorf("unary operator %q has no help text", name)
+		}
+	}
+	for name := range value.BinaryOps {
+		if _, ok := helpBinary[name]; !ok {
+			t.Errorf("binary operator %q has no help text", name)
+		}
+	}
+}
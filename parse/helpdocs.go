@@ -6,8 +6,27 @@ package parse
 
 import (
 	"strings"
+
+	"robpike.io/ivy/value"
 )
 
+func init() {
+	value.OperatorHelp = operatorHelp
+}
+
+// operatorHelp returns the documentation lines for the unary or binary
+// form of the operator, joined by newlines.
+func operatorHelp(name string, binary bool) string {
+	pair, ok := helpUnary[name]
+	if binary {
+		pair, ok = helpBinary[name]
+	}
+	if !ok {
+		return ""
+	}
+	return strings.Join(helpLines[pair.start:pair.end+1], "\n")
+}
+
 func (p *Parser) helpOverview() {
 	p.Println("Overview:")
 	p.Println("\t)help intro")
@@ -42,7 +61,7 @@ func (p *Parser) printHelpBlock(start, end string) {
 				if strings.HasPrefix(line, end) {
 					return
 				}
-				p.Printf("%s\n", line)
+				p.helpLine(line)
 			}
 		}
 	}
@@ -51,7 +70,7 @@ func (p *Parser) printHelpBlock(start, end string) {
 func (p *Parser) helpAbout(str string) { // str is already lowercase.
 	for _, line := range helpLines {
 		if strings.Contains(strings.ToLower(line), str) {
-			p.Printf("%s\n", line)
+			p.helpLine(line)
 		}
 	}
 }
@@ -68,7 +87,7 @@ func (p *Parser) help(str string) {
 		p.Println("Unary operators:")
 		p.Println("	Name              APL   Ivy     Meaning")
 		for i := unaryPair.start; i <= unaryPair.end; i++ {
-			p.Printf("%s\n", helpLines[i])
+			p.helpLine(helpLines[i])
 		}
 	}
 	if binary {
@@ -78,7 +97,7 @@ func (p *Parser) help(str string) {
 		p.Println("Binary operators:")
 		p.Println("	Name                  APL   Ivy     Meaning")
 		for i := binaryPair.start; i <= binaryPair.end; i++ {
-			p.Printf("%s\n", helpLines[i])
+			p.helpLine(helpLines[i])
 		}
 	}
 	if axis {
@@ -88,7 +107,55 @@ func (p *Parser) help(str string) {
 		p.Println("Axis operators:")
 		p.Println("	Name                APL  Ivy  APL Example  Ivy Example  Meaning (of example)")
 		for i := axisPair.start; i <= axisPair.end; i++ {
-			p.Printf("%s\n", helpLines[i])
+			p.helpLine(helpLines[i])
+		}
+	}
+}
+
+// helpLine prints a line of help text. If it is wider than the configured
+// width, it is broken at spaces, and the continuation lines are indented
+// one tab stop more than the first.
+func (p *Parser) helpLine(line string) {
+	width := p.context.Config().Width()
+	if width <= 0 {
+		p.Printf("%s\n", line)
+		return
+	}
+	rest := strings.TrimLeft(line, " \t")
+	prefix := line[:len(line)-len(rest)]
+	indent := prefix + "\t"
+	if displayWidth(indent) > width/2 {
+		// Too deep to be useful.
+		prefix, indent = "\t", "\t"
+	}
+	for displayWidth(prefix+rest) > width {
+		// Break at the last space that fits, or the first if none does.
+		brk := -1
+		for i, r := range rest {
+			if r == ' ' && i > 0 && (brk < 0 || displayWidth(prefix+rest[:i]) <= width) {
+				brk = i
+			}
+		}
+		if brk < 0 {
+			break
+		}
+		p.Printf("%s\n", prefix+strings.TrimRight(rest[:brk], " "))
+		rest = strings.TrimLeft(rest[brk:], " ")
+		prefix = indent
+	}
+	p.Printf("%s\n", prefix+rest)
+}
+
+// displayWidth returns the number of columns occupied by s
+// when printed with tab stops every 8 columns.
+func displayWidth(s string) int {
+	col := 0
+	for _, r := range s {
+		if r == '\t' {
+			col += 8 - col%8
+		} else {
+			col++
 		}
 	}
+	return col
 }
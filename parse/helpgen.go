@@ -178,9 +178,10 @@ func main() {
 		}
 		j := i
 		// If the next few lines have no text at the left, they are a continuation. Pull them in.
+		// A line that names another op in the Ivy column, such as div after /, is not.
 		for ; j < len(lines); j++ {
-			next := lines[j+1]
-			if len(next) < 37 || next[1] != ' ' {
+			next := []rune(lines[j+1])
+			if len(next) < 37 || next[1] != ' ' || strings.TrimSpace(string(next[29:37])) != "" {
 				break
 			}
 		}
@@ -216,7 +217,16 @@ func main() {
 		if len(op) == 0 {
 			continue
 		}
-		fmt.Fprintf(buf, `%q: {%d, %d},`+"\n", string(op), i, i)
+		j := i
+		// If the next few lines have no text at the left, they are a continuation. Pull them in.
+		for ; j+1 < len(lines); j++ {
+			next := lines[j+1]
+			if len(next) < 33 || next[1] != ' ' {
+				break
+			}
+		}
+		fmt.Fprintf(buf, `%q: {%d, %d},`+"\n", string(op), i, j)
+		i = j
 	}
 	s("}")
 
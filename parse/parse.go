@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 
+	"robpike.io/ivy/config"
 	"robpike.io/ivy/exec"
 	"robpike.io/ivy/scan"
 	"robpike.io/ivy/value"
@@ -18,10 +19,14 @@ import (
 // tree formats an expression in an unambiguous form for debugging.
 func tree(e interface{}) string {
 	switch e := e.(type) {
+	case value.Bool:
+		return fmt.Sprintf("<bool %s>", e)
 	case value.Int:
 		return fmt.Sprintf("<int %s>", e)
 	case value.BigInt:
 		return fmt.Sprintf("<bigint %s>", e)
+	case value.Decimal:
+		return fmt.Sprintf("<decimal %s>", e)
 	case value.BigRat:
 		return fmt.Sprintf("<rat %s>", e)
 	case value.BigFloat:
@@ -40,12 +45,28 @@ func tree(e interface{}) string {
 		return s
 	case *variableExpr:
 		return fmt.Sprintf("<var %s>", e.name)
+	case *opArg:
+		return fmt.Sprintf("<op %s>", e.name)
 	case *unary:
-		return fmt.Sprintf("(%s %s)", e.op, tree(e.right))
+		return fmt.Sprintf("(%s %s)", spelling(e.op, e.text), tree(e.right))
 	case *binary:
-		return fmt.Sprintf("(%s %s %s)", tree(e.left), e.op, tree(e.right))
+		return fmt.Sprintf("(%s %s %s)", tree(e.left), spelling(e.op, e.text), tree(e.right))
 	case conditional:
 		return tree(e.binary)
+	case quietExpr:
+		return tree(e.Expr)
+	case *shortCircuit:
+		return fmt.Sprintf("(%s %s %s)", tree(e.left), e.op, tree(e.right))
+	case *each:
+		if e.left == nil {
+			return fmt.Sprintf("(%s each %s)", spelling(e.op, e.text), tree(e.right))
+		}
+		return fmt.Sprintf("(%s %s each %s)", tree(e.left), spelling(e.op, e.text), tree(e.right))
+	case *bind:
+		if e.left {
+			return fmt.Sprintf("(%s bind (%s))", tree(e.arg), e.op)
+		}
+		return fmt.Sprintf("((%s) bind %s)", e.op, tree(e.arg))
 	case *index:
 		s := fmt.Sprintf("(%s[", tree(e.left))
 		for i, v := range e.right {
@@ -56,6 +77,20 @@ func tree(e interface{}) string {
 		}
 		s += "])"
 		return s
+	case *value.Slice:
+		s := ""
+		for i, x := range []value.Expr{e.Start, e.Stop, e.Step} {
+			if i == 2 && x == nil {
+				break
+			}
+			if i > 0 {
+				s += ":"
+			}
+			if x != nil {
+				s += tree(x)
+			}
+		}
+		return s
 	case []value.Expr:
 		if len(e) == 1 {
 			return tree(e[0])
@@ -85,6 +120,9 @@ func (s sliceExpr) Eval(context value.Context) value.Value {
 	// (yielding 4) work.
 	for i := len(s) - 1; i >= 0; i-- {
 		elem := s[i].Eval(context)
+		if b, ok := elem.(*value.BoundOp); ok {
+			value.Errorf("%s is an op, not a value", b.ProgString())
+		}
 		// Each element must be a singleton.
 		if !isScalar(elem) {
 			value.Errorf("vector element must be scalar; have %s", elem)
@@ -134,7 +172,7 @@ func (s sliceExpr) ProgString() string {
 			if i > 0 {
 				b.WriteRune(' ')
 			}
-			if isCompound(v) {
+			if isCompound(v) || isIndexOrVector(v) {
 				b.WriteString("(" + v.ProgString() + ")")
 			} else {
 				b.WriteString(v.ProgString())
@@ -157,20 +195,37 @@ func (s sliceExpr) allChars() bool {
 type variableExpr struct {
 	name  string
 	local int // local index, or 0 for global
+	pos   position
 }
 
 func (e *variableExpr) Eval(context value.Context) value.Value {
+	v := e.lookup(context)
+	// The value may now be stored elsewhere, so it is no longer the
+	// variable's own to update in place.
+	context.(*exec.Context).Share(e.local, e.name)
+	return v
+}
+
+// lookup returns the value of the variable, which must be defined.
+func (e *variableExpr) lookup(context value.Context) value.Value {
 	var v value.Value
 	if e.local >= 1 {
 		v = context.Local(e.local)
 	} else {
 		v = context.Global(e.name)
 	}
+	return e.defined(v)
+}
+
+// defined returns v, the value of the variable, if it is not nil,
+// and otherwise reports that the variable is undefined.
+func (e *variableExpr) defined(v value.Value) value.Value {
 	if v == nil {
 		kind := "global"
 		if e.local >= 1 {
 			kind = "local"
 		}
+		e.pos.mark()
 		value.Errorf("undefined %s variable %q", kind, e.name)
 	}
 	return v
@@ -180,13 +235,31 @@ func (e *variableExpr) ProgString() string {
 	return e.name
 }
 
+// opArg is the name of a binary operator passed as the left operand
+// of an op that has an operator parameter, as the + in (+) fold v.
+// It has no value of its own.
+type opArg struct {
+	name string
+	pos  position
+}
+
+func (a *opArg) Eval(context value.Context) value.Value {
+	a.pos.mark()
+	value.Errorf("operator %s is not a value", a.ProgString())
+	panic("not reached")
+}
+
+func (a *opArg) ProgString() string {
+	return "(" + a.name + ")"
+}
+
 // isCompound reports whether the item is a non-trivial expression tree, one that
 // may require parentheses around it when printed to maintain correct evaluation order.
 func isCompound(x interface{}) bool {
 	switch x := x.(type) {
-	case value.Char, value.Int, value.BigInt, value.BigRat, value.BigFloat, value.Complex, value.Vector, value.Matrix:
+	case value.Bool, value.Char, value.Int, value.BigInt, value.Decimal, value.BigRat, value.BigFloat, value.Complex, value.Vector, value.Matrix:
 		return false
-	case sliceExpr, *variableExpr:
+	case sliceExpr, *variableExpr, *opArg:
 		return false
 	case *index:
 		return isCompound(x.left)
@@ -195,23 +268,91 @@ func isCompound(x interface{}) bool {
 	}
 }
 
+// isIndexOrVector reports whether the item is an index expression or
+// a vector. Neither is compound, but as an element of a vector, each
+// must be in parentheses, lest the index apply to the whole vector
+// or the elements join it.
+func isIndexOrVector(x interface{}) bool {
+	switch x.(type) {
+	case *index, sliceExpr:
+		return true
+	}
+	return false
+}
+
 type unary struct {
 	op    string
+	text  string // The operator as written, if spelled with APL symbols.
 	right value.Expr
+	pos   position
 }
 
 func (u *unary) ProgString() string {
-	return fmt.Sprintf("%s %s", u.op, u.right.ProgString())
+	return fmt.Sprintf("%s %s", spelling(u.op, u.text), u.right.ProgString())
+}
+
+// spelling returns the text of the operator as written, if it was
+// recorded, or otherwise its name.
+func spelling(op, text string) string {
+	if text != "" {
+		return text
+	}
+	return op
 }
 
 func (u *unary) Eval(context value.Context) value.Value {
-	return context.EvalUnary(u.op, u.right.Eval(context).Inner())
+	done := false
+	defer u.pos.unwind(&done)
+	rhs := u.right.Eval(context).Inner()
+	v := context.EvalUnary(u.op, rhs)
+	trace(context, nil, spelling(u.op, u.text), rhs, v)
+	done = true
+	return v
+}
+
+// trace prints, if the trace debugging flag is set, an operation as
+// applied to the values of its operands, and its result, as in
+//
+//	3 * 4 -> 12
+//
+// Left is nil for a unary operation. Since an operation is traced once
+// it has been evaluated, the inner operations of an expression are
+// printed before the outer ones.
+func trace(context value.Context, left value.Value, op string, right, result value.Value) {
+	conf := context.Config()
+	if !conf.Debug("trace") {
+		return
+	}
+	var b strings.Builder
+	if left != nil {
+		b.WriteString(traceOperand(conf, left))
+		b.WriteString(" ")
+	}
+	fmt.Fprintf(&b, "%s %s -> %s\n", op, traceOperand(conf, right), result.Sprint(conf))
+	fmt.Fprint(conf.Output(), b.String())
+}
+
+// traceOperand formats an operand for trace, in parentheses
+// if it would otherwise run into the operator.
+func traceOperand(conf *config.Config, v value.Value) string {
+	s := v.Sprint(conf)
+	switch v := v.(type) {
+	case value.Vector:
+		if len(v) != 1 {
+			return "(" + s + ")"
+		}
+	case *value.Matrix:
+		return "(" + strings.ReplaceAll(s, "\n", " ") + ")"
+	}
+	return s
 }
 
 type binary struct {
 	op    string
+	text  string // The operator as written, if spelled with APL symbols.
 	left  value.Expr
 	right value.Expr
+	pos   position
 }
 
 func (b *binary) ProgString() string {
@@ -221,22 +362,107 @@ func (b *binary) ProgString() string {
 	} else {
 		left = b.left.ProgString()
 	}
-	return fmt.Sprintf("%s %s %s", left, b.op, b.right.ProgString())
+	return fmt.Sprintf("%s %s %s", left, spelling(b.op, b.text), b.right.ProgString())
 }
 
 func (b *binary) Eval(context value.Context) value.Value {
-	if b.op == "=" {
-		return assignment(context, b)
+	done := false
+	defer b.pos.unwind(&done)
+	var v value.Value
+	if b.op == "=" || b.op == "::=" {
+		v = assignment(context, b)
+	} else if arg, ok := b.left.(*opArg); ok {
+		rhs := b.right.Eval(context).Inner()
+		v = context.(*exec.Context).EvalOpArg(arg.name, b.op, rhs)
+	} else {
+		// Evaluation is right to left, so side effects in the right
+		// operand happen first. Keep the operands in separate
+		// statements to make the order explicit.
+		rhs := b.right.Eval(context).Inner()
+		lhs := b.left.Eval(context)
+		v = context.EvalBinary(lhs, b.op, rhs)
+		trace(context, lhs.Inner(), spelling(b.op, b.text), rhs, v)
+	}
+	done = true
+	return v
+}
+
+// each is an operator modified by the each adverb, as in rot each x
+// or x , each y, which applies the operator to the items of its operands
+// one at a time rather than to the operands as a whole. Left is nil if
+// the operator is unary.
+type each struct {
+	op    string
+	text  string // The operator as written, if spelled with APL symbols.
+	left  value.Expr
+	right value.Expr
+	pos   position
+}
+
+func (e *each) ProgString() string {
+	if e.left == nil {
+		return fmt.Sprintf("%s each %s", spelling(e.op, e.text), e.right.ProgString())
+	}
+	var left string
+	if isCompound(e.left) {
+		left = fmt.Sprintf("(%s)", e.left.ProgString())
+	} else {
+		left = e.left.ProgString()
+	}
+	return fmt.Sprintf("%s %s each %s", left, spelling(e.op, e.text), e.right.ProgString())
+}
+
+func (e *each) Eval(context value.Context) value.Value {
+	done := false
+	defer e.pos.unwind(&done)
+	var v value.Value
+	rhs := e.right.Eval(context).Inner()
+	if e.left == nil {
+		v = value.Each(context, e.op, rhs)
+	} else {
+		v = value.EachBinary(context, e.left.Eval(context).Inner(), e.op, rhs)
+	}
+	done = true
+	return v
+}
+
+// bind is the bind operator, which makes a unary op from a binary one by
+// binding one of its operands, as in 2 bind (*) or (-) bind 1. Its value
+// is a value.BoundOp.
+type bind struct {
+	op   string
+	arg  value.Expr
+	left bool // Whether arg is the left operand of op.
+	pos  position
+}
+
+func (b *bind) ProgString() string {
+	if !b.left {
+		return fmt.Sprintf("(%s) bind %s", b.op, b.arg.ProgString())
+	}
+	if isCompound(b.arg) {
+		return fmt.Sprintf("(%s) bind (%s)", b.arg.ProgString(), b.op)
+	}
+	return fmt.Sprintf("%s bind (%s)", b.arg.ProgString(), b.op)
+}
+
+func (b *bind) Eval(context value.Context) value.Value {
+	done := false
+	defer b.pos.unwind(&done)
+	arg := b.arg.Eval(context).Inner()
+	if bound, ok := arg.(*value.BoundOp); ok {
+		value.Errorf("%s is an op, not a value", bound.ProgString())
 	}
-	rhs := b.right.Eval(context).Inner()
-	lhs := b.left.Eval(context)
-	return context.EvalBinary(lhs, b.op, rhs)
+	v := value.NewBoundOp(arg, context.(*exec.Context).BindOp(b.op), b.left)
+	done = true
+	return v
 }
 
 type index struct {
 	op    string
 	left  value.Expr
 	right []value.Expr
+	pos   position
 }
 
 func (x *index) ProgString() string {
@@ -262,7 +488,119 @@ func (x *index) ProgString() string {
 }
 
 func (x *index) Eval(context value.Context) value.Value {
-	return value.Index(context, x, x.left, x.right)
+	done := false
+	defer x.pos.unwind(&done)
+	left := x.left
+	if v, ok := left.(*variableExpr); ok {
+		// Indexing copies what it selects, so it does not share the value.
+		left = indexedVar{v, false}
+	}
+	v := value.Index(context, x, left, x.right)
+	done = true
+	return v
+}
+
+// position records where an expression appears in the source,
+// so an error during its evaluation can be reported there.
+type position struct {
+	parser *Parser
+	line   int
+	column int
+}
+
+// pos returns the position of the token.
+func (p *Parser) pos(tok scan.Token) position {
+	return position{p, tok.Line, tok.Column}
+}
+
+// mark records pos as the location of the error being raised, unless
+// it is not on the line being executed or a more deeply nested
+// expression has already claimed the error.
+func (pos position) mark() {
+	p := pos.parser
+	if p != nil && p.evalCol == 0 && pos.line == p.lineNum {
+		p.evalCol = pos.column
+	}
+}
+
+// unwind is deferred by Eval methods, which set done once evaluation
+// completes. If it did not, an error is unwinding the stack, so unwind
+// marks the position.
+func (pos position) unwind(done *bool) {
+	if !*done {
+		pos.mark()
+	}
+}
+
+// shortCircuit is a logical operator, && or ||, that evaluates its right
+// operand only if the left one does not determine the result. Unlike the
+// operators in the value package, it evaluates its left operand first.
+// Both operands must be the scalar 0 or 1.
+type shortCircuit struct {
+	op    string
+	left  value.Expr
+	right value.Expr
+	pos   position
+}
+
+func (s *shortCircuit) ProgString() string {
+	var left string
+	if isCompound(s.left) {
+		left = fmt.Sprintf("(%s)", s.left.ProgString())
+	} else {
+		left = s.left.ProgString()
+	}
+	return fmt.Sprintf("%s %s %s", left, s.op, s.right.ProgString())
+}
+
+func (s *shortCircuit) Eval(context value.Context) value.Value {
+	done := false
+	defer s.pos.unwind(&done)
+	v := s.truth(context, s.left.Eval(context), "left")
+	isTrue := v == value.Int(1) || v == value.Bool(true)
+	if s.op == "&&" && isTrue || s.op == "||" && !isTrue {
+		v = s.truth(context, s.right.Eval(context), "right")
+	}
+	done = true
+	return v
+}
+
+// truth returns v, which must be the scalar 0 or 1 or a Bool.
+func (s *shortCircuit) truth(context value.Context, v value.Value, side string) value.Value {
+	switch v := v.Inner().(type) {
+	case value.Bool:
+		return v
+	case value.Int:
+		if v == 0 || v == 1 {
+			return v
+		}
+	case value.Vector, *value.Matrix:
+		elementwise := "and"
+		if s.op == "||" {
+			elementwise = "or"
+		}
+		value.Errorf("%s operand of %s must be a scalar; use %s for vectors", side, s.op, elementwise)
+	}
+	value.Errorf("%s operand of %s must be 0 or 1, not %s", side, s.op, v.Sprint(context.Config()))
+	panic("not reached")
+}
+
+// quietExpr is an expression on a line ending in a semicolon. Its value
+// is marked as an assignment so the interpreter does not print it.
+type quietExpr struct {
+	value.Expr
+}
+
+func (q quietExpr) Eval(context value.Context) value.Value {
+	v := q.Expr.Eval(context)
+	switch a := v.(type) {
+	case nil:
+		return nil
+	case Assignment:
+		// Even an assignment that would be printed is quiet.
+		return Assignment{Value: a.Value}
+	}
+	return Assignment{Value: v}
 }
 
 // conditional is a conditional executor: expression ":" expression
@@ -287,7 +625,13 @@ type Parser struct {
 	tokenBuf [100]scan.Token // Reusable.
 	fileName string
 	lineNum  int
+	errCol   int        // Column of the token at a parse error, or 0.
+	evalCol  int        // Column of the expression at an evaluation error, or 0.
+	lastTok  scan.Token // Most recent token read.
+	eol      scan.Token // Token that ended the current line.
+	prevTok  scan.Token // Most recent token returned by the scanner.
 	context  *exec.Context
+	saved    bool // Reading a saved session, as by )get.
 }
 
 // NewParser returns a new parser that will read from the scanner.
@@ -300,6 +644,19 @@ func NewParser(fileName string, scanner *scan.Scanner, context value.Context) *P
 	}
 }
 
+// Reset makes the parser read from the scanner, whose input is named
+// fileName, as a new parser would, so one parser may read several inputs
+// in turn. It discards any rest of the current line and all position
+// information from the previous input, but keeps the context, with its
+// variables and ops.
+func (p *Parser) Reset(fileName string, scanner *scan.Scanner) {
+	*p = Parser{
+		scanner:  scanner,
+		fileName: fileName,
+		context:  p.context,
+	}
+}
+
 // Printf formats the args and writes them to the configured output writer.
 func (p *Parser) Printf(format string, args ...interface{}) {
 	fmt.Fprintf(p.context.Config().Output(), format, args...)
@@ -322,27 +679,77 @@ func (p *Parser) next() scan.Token {
 	return tok
 }
 
+// peek returns the next token without consuming it. At the end of
+// the line it returns an EOF token positioned where the line ends.
 func (p *Parser) peek() scan.Token {
 	if len(p.tokens) == 0 {
-		return scan.Token{Type: scan.EOF}
+		p.lastTok = scan.Token{Type: scan.EOF, Line: p.eol.Line, Column: p.eol.Column, Offset: p.eol.Offset}
+	} else {
+		p.lastTok = p.tokens[0]
 	}
-	return p.tokens[0]
+	return p.lastTok
 }
 
 var eof = scan.Token{
 	Type: scan.EOF,
 }
 
-// Loc returns the current input location in the form "name:line: ".
+// Loc returns the current input location in the form "name:line: ",
+// or "name:line:column: " after an error whose column is known.
 // If the name is <stdin>, it returns the empty string.
 func (p *Parser) Loc() string {
 	if p.fileName == "<stdin>" {
 		return ""
 	}
+	if col := p.errColumn(); col > 0 {
+		return fmt.Sprintf("%s:%d:%d: ", p.fileName, p.lineNum, col)
+	}
 	return fmt.Sprintf("%s:%d: ", p.fileName, p.lineNum)
 }
 
+// errColumn returns the column of the most recent error on
+// the current line, or 0 if it is not known.
+func (p *Parser) errColumn() int {
+	if p.errCol > 0 {
+		return p.errCol
+	}
+	return p.evalCol
+}
+
+// Caret returns, after an error whose column is known, the text of
+// the current line followed by a line with a caret under that column,
+// each terminated by a newline. Otherwise it returns the empty string,
+// as it does when Loc does.
+func (p *Parser) Caret() string {
+	col := p.errColumn()
+	if p.fileName == "<stdin>" || col == 0 {
+		return ""
+	}
+	text, ok := p.scanner.Source(p.lineNum)
+	if !ok {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(text)
+	b.WriteByte('\n')
+	// Copy tabs so the caret lines up however tabs are displayed.
+	runes := []rune(text)
+	for i := 0; i < col-1; i++ {
+		if i < len(runes) && runes[i] == '\t' {
+			b.WriteByte('\t')
+		} else {
+			b.WriteByte(' ')
+		}
+	}
+	b.WriteString("^\n")
+	return b.String()
+}
+
 func (p *Parser) errorf(format string, args ...interface{}) {
+	if p.lastTok.Line > 0 {
+		p.lineNum = p.lastTok.Line
+		p.errCol = p.lastTok.Column
+	}
 	p.tokens = p.tokenBuf[:0]
 	value.Errorf(format, args...)
 }
@@ -350,6 +757,8 @@ func (p *Parser) errorf(format string, args ...interface{}) {
 // Line reads a line of input and returns the values it evaluates.
 // A nil returned slice means there were no values.
 // The boolean reports whether the line is valid.
+// If the line ends with a semicolon, its values are evaluated but,
+// like assignments, not printed.
 //
 // Line
 //	) special command '\n'
@@ -357,6 +766,7 @@ func (p *Parser) errorf(format string, args ...interface{}) {
 //	expressionList '\n'
 func (p *Parser) Line() ([]value.Expr, bool) {
 	var ok bool
+	p.context.ForgetAll() // In case a bad op definition left names declared.
 	if !p.readTokensToNewline() {
 		return nil, false
 	}
@@ -372,10 +782,26 @@ func (p *Parser) Line() ([]value.Expr, bool) {
 		p.functionDefn()
 		return nil, true
 	}
+	if p.saved && len(p.tokens) > 1 && p.isOperator(p.tokens[0]) && p.tokens[1].Type == scan.Assign {
+		// A session saved before operator names were reserved may
+		// have such a variable. Skip it so the rest still loads.
+		p.lineNum = tok.Line
+		fmt.Fprintf(p.context.Config().ErrOutput(), "%swarning: skipping variable %s: cannot assign to operator %[2]s; rename it\n", p.Loc(), tok.Text)
+		return nil, true
+	}
+	quiet := p.tokens[len(p.tokens)-1].Type == scan.Semicolon
+	if quiet {
+		p.tokens = p.tokens[:len(p.tokens)-1]
+	}
 	exprs, ok := p.expressionList()
 	if !ok {
 		return nil, false
 	}
+	if quiet {
+		for i, expr := range exprs {
+			exprs[i] = quietExpr{expr}
+		}
+	}
 	return exprs, true
 }
 
@@ -386,20 +812,68 @@ func (p *Parser) Line() ([]value.Expr, bool) {
 // for parsing, which we may use one day.
 func (p *Parser) readTokensToNewline() bool {
 	p.tokens = p.tokenBuf[:0]
+	p.errCol = 0
+	p.evalCol = 0
+	p.lastTok = scan.Token{}
 	for {
 		tok := p.scanner.Next()
+		if tok.Type != scan.EOF && tok == p.prevTok {
+			// The scanner returned the same token again, so it is
+			// stuck. Treat that as the end of the input rather than
+			// reading, or reporting an error, forever.
+			return false
+		}
+		p.prevTok = tok
 		switch tok.Type {
 		case scan.Error:
+			p.echo(tok.Line)
+			p.lineNum = tok.Line
+			p.lastTok = tok
 			p.errorf("%s", tok)
+		case scan.Newline, scan.EOF:
+			p.eol = tok
+		}
+		switch tok.Type {
 		case scan.Newline:
+			p.echo(tok.Line)
 			return true
 		case scan.EOF:
-			return len(p.tokens) > 0
+			if len(p.tokens) > 0 {
+				p.echo(p.tokens[len(p.tokens)-1].Line)
+				return true
+			}
+			return false
 		}
 		p.tokens = append(p.tokens, tok)
+		p.declareOpParam()
+	}
+}
+
+// declareOpParam declares the operator parameter of an op definition
+// as soon as its header, op (f), has been read, so the rest of the
+// line is scanned with f as an operator, as in f/ and o.f.
+func (p *Parser) declareOpParam() {
+	t := p.tokens
+	if len(t) == 4 && t[0].Type == scan.Op && t[1].Type == scan.LeftParen &&
+		t[2].Type == scan.Identifier && t[3].Type == scan.RightParen {
+		p.context.DeclareOp(t[2].Text)
 	}
 }
 
+// echo prints the numbered line of input after the marker set by
+// ) echo, unless echoing is off or the line is blank.
+func (p *Parser) echo(line int) {
+	marker := p.context.Config().Echo()
+	if marker == "" {
+		return
+	}
+	text, ok := p.scanner.Source(line)
+	if !ok || strings.TrimSpace(text) == "" {
+		return
+	}
+	p.Println(marker + text)
+}
+
 // expressionList:
 //	statementList <eol>
 func (p *Parser) expressionList() ([]value.Expr, bool) {
@@ -411,7 +885,11 @@ func (p *Parser) expressionList() ([]value.Expr, bool) {
 	switch tok.Type {
 	case scan.EOF: // Expect to be at end of line.
 	default:
-		p.errorf("unexpected %s", tok)
+		var last value.Expr
+		if len(exprs) > 0 {
+			last = exprs[len(exprs)-1]
+		}
+		p.errorf("%s", p.unexpected(tok, last))
 	}
 	if len(exprs) > 0 && p.context.Config().Debug("parse") {
 		p.Println(tree(exprs))
@@ -450,45 +928,177 @@ func (p *Parser) statementList() ([]value.Expr, bool) {
 // expr
 //	operand
 //	operand binop expr
+//	'(' binop ')' binop expr
 func (p *Parser) expr() value.Expr {
 	tok := p.next()
+	if tok.Type == scan.LeftParen {
+		if arg := p.opArg(tok); arg != nil {
+			return p.opArgCall(arg)
+		}
+	}
 	expr := p.operand(tok, true)
 	tok = p.peek()
 	switch tok.Type {
 	case scan.EOF, scan.RightParen, scan.RightBrack, scan.Semicolon, scan.Colon:
 		return expr
 	case scan.Identifier:
+		if tok.Text == "bind" {
+			p.next()
+			return p.bindLeft(expr, tok)
+		}
 		if p.context.DefinedBinary(tok.Text) {
 			p.next()
+			if p.eachFollows() {
+				return &each{
+					op:    tok.Text,
+					left:  expr,
+					right: p.expr(),
+					pos:   p.pos(tok),
+				}
+			}
 			return &binary{
 				left:  expr,
 				op:    tok.Text,
 				right: p.expr(),
+				pos:   p.pos(tok),
 			}
 		}
 	case scan.Assign:
 		p.next()
+		if v, ok := expr.(*variableExpr); ok && v.local == 0 && (v.name == "pi" || v.name == "e") {
+			p.errorf("cannot reassign %q", v.name)
+		}
 		switch lhs := expr.(type) {
 		case *variableExpr, *index:
 			return &binary{
 				left:  lhs,
 				op:    tok.Text,
 				right: p.expr(),
+				pos:   p.pos(tok),
 			}
 		}
 		p.errorf("cannot assign to %s", expr.ProgString())
 	case scan.Operator:
 		p.next()
+		if tok.Text == "&&" || tok.Text == "||" {
+			return &shortCircuit{
+				left:  expr,
+				op:    tok.Text,
+				right: p.expr(),
+				pos:   p.pos(tok),
+			}
+		}
+		if p.eachFollows() {
+			return &each{
+				op:    value.OperatorName(tok.Text, false),
+				text:  symbolText(tok.Text),
+				left:  expr,
+				right: p.expr(),
+				pos:   p.pos(tok),
+			}
+		}
 		return &binary{
 			left:  expr,
-			op:    tok.Text,
+			op:    value.OperatorName(tok.Text, false),
+			text:  symbolText(tok.Text),
 			right: p.expr(),
+			pos:   p.pos(tok),
 		}
 	}
-	p.errorf("after expression: unexpected %s", p.peek())
+	p.errorf("%s", p.unexpected(tok, expr))
 	return nil
 }
 
+// unexpected returns the message for the error of finding tok after the
+// complete expression expr, or nil if there is none, when the line or
+// expression should have ended. It shows what was parsed and, for a
+// closing bracket, suggests that the opening one is missing. The
+// location printed with an error includes the column, except for
+// standard input, so the message gives it then.
+func (p *Parser) unexpected(tok scan.Token, expr value.Expr) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "unexpected %s", tok)
+	if p.fileName == "<stdin>" {
+		fmt.Fprintf(&b, " at column %d", tok.Column)
+	}
+	if expr != nil {
+		fmt.Fprintf(&b, " after %s", expr.ProgString())
+	}
+	switch tok.Type {
+	case scan.RightParen:
+		b.WriteString(`; unmatched ")": is a "(" missing before it?`)
+	case scan.RightBrack:
+		b.WriteString(`; unmatched "]": is a "[" missing before it?`)
+	}
+	return b.String()
+}
+
+// opArg returns the operator in parentheses, as in (+), that starts
+// with the left paren tok, or nil if what follows is not one.
+func (p *Parser) opArg(tok scan.Token) *opArg {
+	if len(p.tokens) < 2 || p.tokens[1].Type != scan.RightParen {
+		return nil
+	}
+	name := p.tokens[0].Text
+	switch p.tokens[0].Type {
+	case scan.Operator:
+		name = value.OperatorName(name, false)
+	case scan.Identifier:
+	default:
+		return nil
+	}
+	if !p.context.DefinedBinary(name) {
+		return nil
+	}
+	p.next()
+	p.next()
+	return &opArg{name: name, pos: p.pos(tok)}
+}
+
+// opArgCall parses the call of an op with an operator parameter,
+// whose left operand, arg, has been read.
+func (p *Parser) opArgCall(arg *opArg) value.Expr {
+	tok := p.next()
+	switch tok.Type {
+	case scan.Identifier, scan.Operator:
+		if tok.Text == "bind" {
+			return &bind{
+				op:  arg.name,
+				arg: p.expr(),
+				pos: p.pos(tok),
+			}
+		}
+		if fn := p.context.BinaryFn[tok.Text]; fn != nil && fn.LeftOp {
+			return &binary{
+				left:  arg,
+				op:    tok.Text,
+				right: p.expr(),
+				pos:   p.pos(tok),
+			}
+		}
+	case scan.EOF:
+		p.errorf("operator %s must be the left operand of an op", arg.ProgString())
+	}
+	p.errorf("%s does not take an operator as its left operand", tok.Text)
+	panic("not reached")
+}
+
+// bindLeft parses the rest of A bind (op), whose operand and the bind
+// token, tok, have been read.
+func (p *Parser) bindLeft(expr value.Expr, tok scan.Token) value.Expr {
+	paren := p.next()
+	arg := p.opArg(paren)
+	if paren.Type != scan.LeftParen || arg == nil {
+		p.errorf("bind: expected binary operator in parentheses, such as (+); found %s", paren)
+	}
+	return &bind{
+		op:   arg.name,
+		arg:  expr,
+		left: true,
+		pos:  p.pos(tok),
+	}
+}
+
 // operand
 //	number
 //	char constant
@@ -496,19 +1106,63 @@ func (p *Parser) expr() value.Expr {
 //	vector
 //	operand [ Expr ]...
 //	unop Expr
+//	unop each Expr
+//	boundop Expr
+//	boundop each Expr
 func (p *Parser) operand(tok scan.Token, indexOK bool) value.Expr {
 	var expr value.Expr
+	if p.isOperator(tok) && p.peek().Type == scan.Assign {
+		p.lastTok = tok
+		p.errorf("cannot assign to operator %s", tok.Text)
+	}
 	switch tok.Type {
 	case scan.Operator:
+		if p.eachFollows() {
+			expr = &each{
+				op:    value.OperatorName(tok.Text, true),
+				text:  symbolText(tok.Text),
+				right: p.expr(),
+				pos:   p.pos(tok),
+			}
+			break
+		}
 		expr = &unary{
-			op:    tok.Text,
+			op:    value.OperatorName(tok.Text, true),
+			text:  symbolText(tok.Text),
 			right: p.expr(),
+			pos:   p.pos(tok),
 		}
 	case scan.Identifier:
 		if p.context.DefinedUnary(tok.Text) {
+			if p.eachFollows() {
+				expr = &each{
+					op:    tok.Text,
+					right: p.expr(),
+					pos:   p.pos(tok),
+				}
+				break
+			}
 			expr = &unary{
 				op:    tok.Text,
 				right: p.expr(),
+				pos:   p.pos(tok),
+			}
+			break
+		}
+		if p.context.DefinedBound(tok.Text) && p.operandFollows() {
+			// A variable holding a bound op, applied as a unary op.
+			if p.eachFollows() {
+				expr = &each{
+					op:    tok.Text,
+					right: p.expr(),
+					pos:   p.pos(tok),
+				}
+				break
+			}
+			expr = &unary{
+				op:    tok.Text,
+				right: p.expr(),
+				pos:   p.pos(tok),
 			}
 			break
 		}
@@ -524,13 +1178,60 @@ func (p *Parser) operand(tok scan.Token, indexOK bool) value.Expr {
 	return expr
 }
 
+// eachFollows reports whether the next token is the each adverb,
+// which modifies the operator before it, and if so consumes it.
+// The word is the adverb only if an operand follows it, so a
+// variable named each, as in rot each, still works as an operand.
+func (p *Parser) eachFollows() bool {
+	tok := p.peek()
+	if tok.Type != scan.Identifier || tok.Text != "each" {
+		return false
+	}
+	if len(p.tokens) < 2 || !startsOperand(p.tokens[1]) {
+		return false
+	}
+	p.next()
+	return true
+}
+
+// isOperator reports whether the token is the name of an operator,
+// built in or defined by op, to which a value may not be assigned.
+func (p *Parser) isOperator(tok scan.Token) bool {
+	return tok.Type == scan.Operator || tok.Type == scan.Identifier && p.context.DefinedOp(tok.Text)
+}
+
+// operandFollows reports whether the next token may start the operand
+// of a unary op, or is the each adverb, rather than end an expression
+// or continue it as an assignment or index.
+func (p *Parser) operandFollows() bool {
+	return startsOperand(p.peek())
+}
+
+// startsOperand reports whether tok may start an operand; see operandFollows.
+func startsOperand(tok scan.Token) bool {
+	switch tok.Type {
+	case scan.EOF, scan.RightParen, scan.RightBrack, scan.Semicolon, scan.Colon, scan.Assign, scan.LeftBrack:
+		return false
+	}
+	return true
+}
+
+// symbolText returns the text of an operator if it is spelled with
+// APL symbols, so it can be printed as written, or otherwise "".
+func symbolText(op string) string {
+	if strings.IndexFunc(op, value.IsSymbol) < 0 {
+		return ""
+	}
+	return op
+}
+
 // index
 //	expr
 //	expr [ expr ]
 //	expr [ expr ] [ expr ] ....
 func (p *Parser) index(expr value.Expr) value.Expr {
 	for p.peek().Type == scan.LeftBrack {
-		p.next()
+		bracket := p.next()
 		list := p.indexList()
 		tok := p.next()
 		if tok.Type != scan.RightBrack {
@@ -539,13 +1240,14 @@ func (p *Parser) index(expr value.Expr) value.Expr {
 		expr = &index{
 			left:  expr,
 			right: list,
+			pos:   p.pos(bracket),
 		}
 	}
 	return expr
 }
 
 // indexList
-//	[[expr] [';' [expr]] ...]
+//	[[element] [';' [element]] ...]
 func (p *Parser) indexList() []value.Expr {
 	list := []value.Expr{}
 	exprSeen := false // Previous element contained an expression.
@@ -564,12 +1266,41 @@ func (p *Parser) indexList() []value.Expr {
 			}
 			exprSeen = false
 		default:
-			list = append(list, p.expr())
+			list = append(list, p.indexElement())
 			exprSeen = true
 		}
 	}
 }
 
+// indexElement
+//	expr
+//	[expr] ':' [expr] [':' [expr]]
+func (p *Parser) indexElement() value.Expr {
+	var expr value.Expr
+	if p.peek().Type != scan.Colon {
+		expr = p.expr()
+		if p.peek().Type != scan.Colon {
+			return expr
+		}
+	}
+	p.next()
+	slice := &value.Slice{Start: expr, Stop: p.sliceBound()}
+	if p.peek().Type == scan.Colon {
+		p.next()
+		slice.Step = p.sliceBound()
+	}
+	return slice
+}
+
+// sliceBound returns the next part of a slice, or nil if it is absent.
+func (p *Parser) sliceBound() value.Expr {
+	switch p.peek().Type {
+	case scan.Colon, scan.Semicolon, scan.RightBrack:
+		return nil
+	}
+	return p.expr()
+}
+
 // number
 //	integer
 //	rational
@@ -582,7 +1313,7 @@ func (p *Parser) number(tok scan.Token) (expr value.Expr, str string) {
 	text := tok.Text
 	switch tok.Type {
 	case scan.Identifier:
-		expr = p.variable(text)
+		expr = p.variable(tok)
 	case scan.String:
 		str = value.ParseString(text)
 	case scan.Number, scan.Rational, scan.Complex:
@@ -595,6 +1326,7 @@ func (p *Parser) number(tok scan.Token) (expr value.Expr, str string) {
 		}
 	}
 	if err != nil {
+		p.lastTok = tok
 		p.errorf("%s: %s", text, err)
 	}
 	return expr, str
@@ -628,7 +1360,7 @@ func (p *Parser) numberOrVector(tok scan.Token) value.Expr {
 			case scan.LeftParen:
 				fallthrough
 			case scan.Identifier:
-				if p.context.DefinedOp(tok.Text) {
+				if p.context.DefinedOp(tok.Text) || tok.Text == "bind" {
 					break Loop
 				}
 				fallthrough
@@ -655,9 +1387,10 @@ func isScalar(v value.Value) bool {
 	return v.Rank() == 0
 }
 
-func (p *Parser) variable(name string) *variableExpr {
+func (p *Parser) variable(tok scan.Token) *variableExpr {
 	return &variableExpr{
-		name: name,
+		name: tok.Text,
+		pos:  p.pos(tok),
 	}
 }
 
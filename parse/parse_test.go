@@ -0,0 +1,547 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parse
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"robpike.io/ivy/config"
+	"robpike.io/ivy/exec"
+	"robpike.io/ivy/scan"
+	"robpike.io/ivy/value"
+)
+
+// parseAll parses the input and returns the location and text of
+// the first error.
+func parseAll(input string) (loc, msg string) {
+	context := exec.NewContext(new(config.Config))
+	scanner := scan.New(context, "input", bufio.NewReader(strings.NewReader(input)))
+	parser := NewParser("input", scanner, context)
+	defer func() {
+		if err, ok := recover().(value.Error); ok {
+			loc, msg = parser.Loc(), err.Error()
+		}
+	}()
+	for {
+		if _, ok := parser.Line(); !ok {
+			return "", ""
+		}
+	}
+}
+
+// evalLine parses and evaluates the single line s, returning
+// its program text and the printed value.
+func evalLine(context value.Context, s string) (prog, result string) {
+	scanner := scan.New(context, "input", bufio.NewReader(strings.NewReader(s+"\n")))
+	exprs, _ := NewParser("input", scanner, context).Line()
+	values := context.Eval(exprs)
+	return exprs[0].ProgString(), values[0].Sprint(context.Config())
+}
+
+func TestErrorLocation(t *testing.T) {
+	tests := []struct {
+		input string
+		loc   string
+		msg   string
+	}{
+		{"x = 1\ny = 2\nz = x + y * (3 4 ] 5\n", "input:3:18: ", "found RightBrack"},
+		{"x = 1\n\n  'αβ' + ) 3\n", "input:3:10: ", "unexpected RightParen"},
+		{"x = 1\ny = 2 3 'abc\n", "input:2:9: ", "unterminated"},
+	}
+	for _, test := range tests {
+		loc, msg := parseAll(test.input)
+		if loc != test.loc || !strings.Contains(msg, test.msg) {
+			t.Errorf("%q: got %q%s; want %q...%s...", test.input, loc, msg, test.loc, test.msg)
+		}
+	}
+}
+
+// TestSymbols checks that operators spelled with APL symbols evaluate
+// like their ASCII names and print as written.
+func TestSymbols(t *testing.T) {
+	tests := []struct {
+		symbols string
+		ascii   string
+	}{
+		{"2 × 3 + 4", "2 * 3 + 4"},
+		{"×/ ⍳ 5", "*/ iota 5"},
+		{"⌈ 5/2", "ceil 5/2"},
+		{"3 ⌈ 1 ⌊ 2", "3 max 1 min 2"},
+		{"⌈/ 3 1 4 1 5", "max/ 3 1 4 1 5"},
+		{"1 ⌽ ⍳ 4", "1 rot iota 4"},
+		{"⌽ 1 2 3", "rot 1 2 3"},
+		{"1 2 3 ≤ 2", "1 2 3 <= 2"},
+		{"1 2 3 ≥ 2", "1 2 3 >= 2"},
+		{"1 2 3 ≠ 2", "1 2 3 != 2"},
+		{"1 2 +.× 3 4", "1 2 +.* 3 4"},
+		{"12 ÷ 2 × 3", "12 / 2 * 3"},
+		{"1 2 3 + 4 × 5", "1 2 3 + 4 * 5"},
+		{"2 × 3", "2 * 3"},
+		{"6 ÷ 2", "6 / 2"},
+		{"÷ 4", "/ 4"},
+		{"5 − 7", "5 - 7"},
+		{"− 7", "- 7"},
+		{"2 ⋆ 10", "2 ** 10"},
+		{"2 3 ⍴ ⍳ 6", "2 3 rho iota 6"},
+		{"⍴ 1 2 3", "rho 1 2 3"},
+		{"∣ -3", "abs -3"},
+		{"2 ↑ 5 6 7", "2 take 5 6 7"},
+		{"↑ 5 6 7", "head 5 6 7"},
+		{"1 ↓ 5 6 7", "1 drop 5 6 7"},
+		{"⍋ 3 1 2", "up 3 1 2"},
+		{"3 ∈ 1 2 3", "3 in 1 2 3"},
+		{"1 0 ∧ 1 1", "1 0 and 1 1"},
+		{"∨/ 0 0 1", "or/ 0 0 1"},
+		{"∼ 1 0", "not 1 0"},
+		{"2 ⊥ 1 0 1", "2 decode 1 0 1"},
+	}
+	for _, test := range tests {
+		context := exec.NewContext(new(config.Config))
+		eval := func(s string) (string, string) {
+			return evalLine(context, s)
+		}
+		prog, got := eval(test.symbols)
+		if prog != test.symbols {
+			t.Errorf("%q prints as %q", test.symbols, prog)
+		}
+		_, want := eval(test.ascii)
+		if got != want {
+			t.Errorf("%q = %s; %q = %s", test.symbols, got, test.ascii, want)
+		}
+	}
+}
+
+// TestGlyphs checks that APL symbols need no spaces around them,
+// and that after )glyphs 0 they are not operators.
+func TestGlyphs(t *testing.T) {
+	tests := []struct {
+		glyphs string
+		ascii  string
+	}{
+		{"⍳5", "iota 5"},
+		{"2 3⍴⍳6", "2 3 rho iota 6"},
+		{"⌽⍳3", "rot iota 3"},
+		{"⍉2 3⍴⍳6", "transp 2 3 rho iota 6"},
+		{"+/⍳4", "+/ iota 4"},
+	}
+	for _, test := range tests {
+		context := exec.NewContext(new(config.Config))
+		_, got := evalLine(context, test.glyphs)
+		_, want := evalLine(context, test.ascii)
+		if got != want {
+			t.Errorf("%q = %s; %q = %s", test.glyphs, got, test.ascii, want)
+		}
+	}
+
+	if loc, msg := parseAll(")glyphs 0\niota 5\n⍳5\n"); loc != "input:3:1: " || !strings.Contains(msg, "unrecognized character: U+2373 '⍳'") {
+		t.Errorf("⍳ with )glyphs 0: %s%s", loc, msg)
+	}
+	if loc, msg := parseAll(")glyphs 0\n)glyphs 1\n⍳5\n"); msg != "" {
+		t.Errorf("⍳ with )glyphs 1: %s%s", loc, msg)
+	}
+	if loc, msg := parseAll(")glyphs off\niota 5\n⍳5\n"); loc != "input:3:1: " || !strings.Contains(msg, "unrecognized character") {
+		t.Errorf("⍳ with )glyphs off: %s%s", loc, msg)
+	}
+	if loc, msg := parseAll(")glyphs off\n)glyphs on\n⍳5\n"); msg != "" {
+		t.Errorf("⍳ with )glyphs on: %s%s", loc, msg)
+	}
+	if _, msg := parseAll(")glyphs maybe\n"); !strings.Contains(msg, ")glyphs: expected on or off, not maybe") {
+		t.Errorf(")glyphs maybe: %s", msg)
+	}
+}
+
+// TestOverload checks that an operator registered in both the unary
+// and the binary tables is applied in the form its position calls for,
+// as with the built-in -.
+func TestOverload(t *testing.T) {
+	value.RegisterUnary("both", func(c value.Context, v value.Value) value.Value {
+		return c.EvalBinary(value.Int(10), "*", v)
+	})
+	defer delete(value.UnaryOps, "both")
+	value.RegisterBinary("both", func(c value.Context, u, v value.Value) value.Value {
+		return c.EvalBinary(c.EvalBinary(value.Int(100), "*", u), "+", v)
+	})
+	defer delete(value.BinaryOps, "both")
+	tests := []struct {
+		input  string
+		prog   string
+		result string
+	}{
+		{"both 3", "both 3", "30"},
+		{"2 both 3", "2 both 3", "203"},
+		{"both 2 both 3", "both 2 both 3", "2030"},
+		{"(both 1) both 2", "(both 1) both 2", "1002"},
+		{"1 2 both 3", "1 2 both 3", "103 203"},
+		{"both/ 1 2 3", "both/ 1 2 3", "303"},
+		{"- 2 both - 3", "- 2 both - 3", "-197"},
+	}
+	context := exec.NewContext(new(config.Config))
+	for _, test := range tests {
+		prog, result := evalLine(context, test.input)
+		if prog != test.prog || result != test.result {
+			t.Errorf("%q: got %q = %s; want %q = %s", test.input, prog, result, test.prog, test.result)
+		}
+	}
+}
+
+// TestRegisterSymbolic checks that a symbolic operator added by a
+// program is scanned, parsed and evaluated like a built-in one.
+func TestRegisterSymbolic(t *testing.T) {
+	value.RegisterBinary("<=>", func(c value.Context, u, v value.Value) value.Value {
+		return c.EvalUnary("sgn", c.EvalBinary(u, "-", v))
+	})
+	defer delete(value.BinaryOps, "<=>")
+	tests := []struct {
+		input  string
+		result string
+	}{
+		{"3 <=> 5", "-1"},
+		{"1 5 3<=>3", "-1 1 0"},
+		{"<=>/ 7 2", "1"},
+		{"1 2 +.<=> 2 1", "0"},
+		{"3 <= 5", "1"},
+	}
+	context := exec.NewContext(new(config.Config))
+	for _, test := range tests {
+		_, result := evalLine(context, test.input)
+		if result != test.result {
+			t.Errorf("%q = %s; want %s", test.input, result, test.result)
+		}
+	}
+}
+
+// TestSlice checks the parsing and evaluation of every combination
+// of present and absent parts of a slice.
+func TestSlice(t *testing.T) {
+	tests := []struct {
+		input  string
+		prog   string
+		result string
+	}{
+		{"x[:]", "x[:]", "10 20 30 40 50 60"},
+		{"x[2:]", "x[2:]", "20 30 40 50 60"},
+		{"x[:5]", "x[:5]", "10 20 30 40 50"},
+		{"x[2:5]", "x[2:5]", "20 30 40 50"},
+		{"x[::]", "x[:]", "10 20 30 40 50 60"},
+		{"x[::2]", "x[::2]", "10 30 50"},
+		{"x[2::2]", "x[2::2]", "20 40 60"},
+		{"x[:5:2]", "x[:5:2]", "10 30 50"},
+		{"x[2:5:2]", "x[2:5:2]", "20 40"},
+		{"x[2:5:]", "x[2:5]", "20 30 40 50"},
+		{"x[5:2:-1]", "x[5:2:-1]", "50 40 30 20"},
+		{"x[::-1]", "x[::-1]", "60 50 40 30 20 10"},
+		{"x[1+1:2*2]", "x[1 + 1:2 * 2]", "20 30 40"},
+		{"m[2:; :2]", "m[2:; :2]", " 5  6\n 9 10"},
+		{"m[; 3:]", "m[; 3:]", " 3  4\n 7  8\n11 12"},
+	}
+	context := exec.NewContext(new(config.Config))
+	evalLine(context, "x = 10 20 30 40 50 60")
+	evalLine(context, "m = 3 4 rho iota 12")
+	for _, test := range tests {
+		prog, result := evalLine(context, test.input)
+		if prog != test.prog || result != test.result {
+			t.Errorf("%q: got %q = %q; want %q = %q", test.input, prog, result, test.prog, test.result)
+		}
+	}
+}
+
+// TestTime checks that )time prints the values of the expressions
+// and then the time taken, which )cpu then reports too.
+func TestTime(t *testing.T) {
+	var out bytes.Buffer
+	conf := new(config.Config)
+	conf.SetOutput(&out)
+	context := exec.NewContext(conf)
+	src := ")time x = 3; +/iota 10; x*2\n)cpu\nx\n"
+	scanner := scan.New(context, "input", bufio.NewReader(strings.NewReader(src)))
+	parser := NewParser("input", scanner, context)
+	for {
+		exprs, ok := parser.Line()
+		for _, v := range context.Eval(exprs) {
+			out.WriteString(v.Sprint(conf) + "\n")
+		}
+		if !ok {
+			break
+		}
+	}
+	lines := strings.Split(out.String(), "\n")
+	if len(lines) != 5 || lines[0] != "55 6" || lines[3] != "3" {
+		t.Fatalf("output %q", out.String())
+	}
+	elapsed := regexp.MustCompile(`^\(\d+(\.\d+)?(ns|µs|ms|s)\)$`)
+	if !elapsed.MatchString(lines[1]) {
+		t.Errorf("bad elapsed time %q", lines[1])
+	}
+	if "("+lines[2]+")" != lines[1] {
+		t.Errorf(")cpu printed %q after )time printed %q", lines[2], lines[1])
+	}
+
+	if loc, msg := parseAll(")time\n"); msg != ")time: expected expression" {
+		t.Errorf(")time with no expression: %s%s", loc, msg)
+	}
+}
+
+// TestTrace checks that the trace debugging flag prints the
+// operations of a nested expression from the inside out.
+func TestTrace(t *testing.T) {
+	var out bytes.Buffer
+	conf := new(config.Config)
+	conf.SetOutput(&out)
+	conf.SetDebug("trace", true)
+	context := exec.NewContext(conf)
+	src := "2 + 3 * 4\n-(iota 3) + 1\n"
+	scanner := scan.New(context, "input", bufio.NewReader(strings.NewReader(src)))
+	parser := NewParser("input", scanner, context)
+	for {
+		exprs, ok := parser.Line()
+		for _, v := range context.Eval(exprs) {
+			out.WriteString(v.Sprint(conf) + "\n")
+		}
+		if !ok {
+			break
+		}
+	}
+	want := "3 * 4 -> 12\n" +
+		"2 + 12 -> 14\n" +
+		"14\n" +
+		"iota 3 -> 1 2 3\n" +
+		"(1 2 3) + 1 -> 2 3 4\n" +
+		"- (2 3 4) -> -2 -3 -4\n" +
+		"-2 -3 -4\n"
+	if got := out.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestDebugParse checks that the parse tree printed by ) debug parse
+// goes to the configured output.
+func TestDebugParse(t *testing.T) {
+	var out bytes.Buffer
+	conf := new(config.Config)
+	conf.SetOutput(&out)
+	conf.SetDebug("parse", true)
+	context := exec.NewContext(conf)
+	src := "x = 2 + 3 * 4\nop f n = -n\n"
+	scanner := scan.New(context, "input", bufio.NewReader(strings.NewReader(src)))
+	parser := NewParser("input", scanner, context)
+	for {
+		if _, ok := parser.Line(); !ok {
+			break
+		}
+	}
+	want := "(<var x> = (<int (2)> + (<int (3)> * <int (4)>)))\n" +
+		"(- <var n>)\n" +
+		"op  f n = (- <var n>)\n"
+	if got := out.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// parseLine parses the single line s in the context and returns its
+// expressions, or nil if it does not parse.
+func parseLine(context value.Context, s string) (exprs []value.Expr) {
+	defer func() {
+		if _, ok := recover().(value.Error); ok {
+			exprs = nil
+		}
+	}()
+	scanner := scan.New(context, "input", bufio.NewReader(strings.NewReader(s+"\n")))
+	exprs, _ = NewParser("input", scanner, context).Line()
+	return exprs
+}
+
+// TestProgStringRoundTrip checks that the program text of an
+// expression parses back to the same tree, and that it has only the
+// parentheses needed to make it do so.
+func TestProgStringRoundTrip(t *testing.T) {
+	tests := []struct {
+		in, out string
+	}{
+		{"2 + 3 * 4", "2 + 3 * 4"},
+		{"(2 + 3) * 4", "(2 + 3) * 4"},
+		{"((2 + 3)) * (4)", "(2 + 3) * 4"},
+		{"2 + (3 * 4)", "2 + 3 * 4"},
+		{"(-x) + 1", "(- x) + 1"},
+		{"-(x + 1)", "- x + 1"},
+		{"(x = 3) + x", "(x = 3) + x"},
+		{"x[1] + 2", "x[1] + 2"},
+		{"(x + 1)[2]", "(x + 1)[2]"},
+		{"(iota 3)[2]", "(iota 3)[2]"},
+		{"1 2 3[2]", "1 2 3[2]"},
+		{"1 (x[2]) 3", "1 (x[2]) 3"},
+		{"1 (2 3) 4", "1 (2 3) 4"},
+		{"1 (2 + 3) 4", "1 (2 + 3) 4"},
+		{"1 ((2 3) 4)", "1 ((2 3) 4)"},
+		{"x[1][2]", "x[1][2]"},
+		{"1 (-2) 4", "1 -2 4"},
+		{"1 (- x) 4", "1 (- x) 4"},
+		{"'a' 1 'bc'", "'a' 1 'b' 'c'"},
+		{"(1 2) + 3", "1 2 + 3"},
+		{"(rho x) == 1", "(rho x) == 1"},
+		{"(x > 1) && x < 3", "(x > 1) && x < 3"},
+		{"(2 * x) , each 1 2", "(2 * x) , each 1 2"},
+		{"x[1; 2:3]", "x[1; 2:3]"},
+		{"x[(1 + 1):]", "x[1 + 1:]"},
+		{"2 - -3", "2 - -3"},
+		{"2 - - 3", "2 - - 3"},
+		{"+/ x", "+/ x"},
+		{"(+/ x) + 1", "(+/ x) + 1"},
+		{"1/3 + 1", "1/3 + 1"},
+		{"1j2 * 2", "1j2 * 2"},
+		{"1.5j-2 + 1", "3/2j-2 + 1"},
+		{"-1/3 - 1", "-1/3 - 1"},
+	}
+	context := exec.NewContext(new(config.Config))
+	context.AssignGlobal("x", value.Int(1))
+	for _, test := range tests {
+		exprs := parseLine(context, test.in)
+		if len(exprs) != 1 {
+			t.Errorf("%q: parsed as %d expressions", test.in, len(exprs))
+			continue
+		}
+		prog := exprs[0].ProgString()
+		if prog != test.out {
+			t.Errorf("%q: program text %q, want %q", test.in, prog, test.out)
+		}
+		again := parseLine(context, prog)
+		if len(again) != 1 || tree(again[0]) != tree(exprs[0]) {
+			t.Errorf("%q: %q parses as %s, want %s", test.in, prog, tree(again), tree(exprs[0]))
+		}
+	}
+}
+
+// TestReset checks that one parser may read two inputs in turn,
+// keeping the variables but nothing of the position in the other input,
+// even after an error in the middle of a line.
+func TestReset(t *testing.T) {
+	context := exec.NewContext(new(config.Config))
+	newScanner := func(name, src string) *scan.Scanner {
+		return scan.New(context, name, bufio.NewReader(strings.NewReader(src)))
+	}
+	a := newScanner("a", "x = 1\ny = x + ) 2 3\nx + 10\n")
+	b := newScanner("b", "x = 5\n\nx * 2\n")
+	parser := NewParser("a", a, context)
+	// line parses and evaluates the next line, returning the
+	// printed value or error and the location.
+	line := func() (result, loc string) {
+		defer func() {
+			if err, ok := recover().(value.Error); ok {
+				result, loc = err.Error(), parser.Loc()
+			}
+		}()
+		exprs, ok := parser.Line()
+		if !ok {
+			return "EOF", parser.Loc()
+		}
+		for _, v := range context.Eval(exprs) {
+			if _, ok := v.(Assignment); !ok {
+				result = v.Sprint(context.Config())
+			}
+		}
+		return result, parser.Loc()
+	}
+	check := func(wantResult, wantLoc string) {
+		t.Helper()
+		if result, loc := line(); result != wantResult || loc != wantLoc {
+			t.Errorf("got %q at %q; want %q at %q", result, loc, wantResult, wantLoc)
+		}
+	}
+	check("", "a:1: ")
+	check(`unexpected RightParen: ")"`, "a:2:9: ")
+	parser.Reset("b", b)
+	if loc := parser.Loc(); loc != "b:0: " {
+		t.Errorf("after Reset, location is %q", loc)
+	}
+	check("", "b:1: ")
+	check("", "b:1: ") // The blank line has no tokens to locate.
+	check("10", "b:3: ")
+	check("EOF", "b:3: ")
+	parser.Reset("a", a)
+	check("15", "a:3: ")
+	check("EOF", "a:3: ")
+}
+
+// TestPeekAtEOF checks that peeking at the end of a line repeatedly
+// returns the same end-of-line token, which next does not consume,
+// and that after Reset it is not positioned in the previous input.
+func TestPeekAtEOF(t *testing.T) {
+	context := exec.NewContext(new(config.Config))
+	scanner := scan.New(context, "input", bufio.NewReader(strings.NewReader("x = 1\n   1 + 2\n")))
+	parser := NewParser("input", scanner, context)
+	if !parser.readTokensToNewline() || !parser.readTokensToNewline() {
+		t.Fatal("no line")
+	}
+	for range parser.tokens {
+		parser.next()
+	}
+	eol := parser.peek()
+	if eol.Type != scan.EOF || eol.Line != 2 || eol.Column != 9 {
+		t.Fatalf("peek at end of line: got %v at %d:%d", eol, eol.Line, eol.Column)
+	}
+	if tok := parser.next(); tok != eol {
+		t.Errorf("next after peek at end of line: got %v, want %v", tok, eol)
+	}
+	if tok := parser.peek(); tok != eol {
+		t.Errorf("peek again: got %v, want %v", tok, eol)
+	}
+	parser.Reset("other", scan.New(context, "other", bufio.NewReader(strings.NewReader("4\n"))))
+	if tok := parser.peek(); tok.Type != scan.EOF || tok.Line != 0 {
+		t.Errorf("peek after Reset: got %v at line %d, want EOF at line 0", tok, tok.Line)
+	}
+	exprs, ok := parser.Line()
+	if !ok || len(exprs) != 1 || tree(exprs[0]) != "<int (4)>" {
+		t.Errorf("line after Reset: got %s, %t", tree(exprs), ok)
+	}
+}
+
+// TestScalarResults checks the rule for single-item results: those
+// that are single by nature are scalars, while selections from a
+// vector are vectors, even of one element.
+func TestScalarResults(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string // The Go type of the result.
+	}{
+		{"v[2]", "value.Int"},
+		{"m[1; 2]", "value.Int"},
+		{"v[,2]", "value.Vector"},
+		{"v[2:2]", "value.Vector"},
+		{"+/v", "value.Int"},
+		{"+/,5", "value.Int"},
+		{"max v", "value.Int"},
+		{"v iota 6", "value.Int"},
+		{"6 in v", "value.Int"},
+		{"v match v", "value.Int"},
+		{"1 take v", "value.Vector"},
+		{"2 drop v", "value.Vector"},
+		{"0 1 0 sel v", "value.Vector"},
+		{"0 1 0 / v", "value.Vector"},
+		{"1 rho 5", "value.Vector"},
+		{",5", "value.Vector"},
+		{"first 1 take v", "value.Int"},
+		{"first m", "value.Int"},
+		{"first 5", "value.Int"},
+		{"(1 take v) + 2", "value.Vector"},
+		{"(first 1 take v) + 2", "value.Int"},
+	}
+	context := exec.NewContext(new(config.Config))
+	context.AssignGlobal("v", value.NewIntVector([]int{5, 6, 7}))
+	context.AssignGlobal("m", value.NewMatrix([]int{2, 2}, value.NewIntVector([]int{1, 2, 3, 4})))
+	for _, test := range tests {
+		exprs := parseLine(context, test.in)
+		if len(exprs) != 1 {
+			t.Errorf("%q: parsed as %d expressions", test.in, len(exprs))
+			continue
+		}
+		if got := fmt.Sprintf("%T", context.Eval(exprs)[0]); got != test.want {
+			t.Errorf("%q: result is %s, want %s", test.in, got, test.want)
+		}
+	}
+}
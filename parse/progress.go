@@ -0,0 +1,74 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parse
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"robpike.io/ivy/config"
+)
+
+const (
+	// progressDelay is how long an operation runs before its
+	// progress is reported, so quick ones print nothing.
+	progressDelay = time.Second
+	// progressInterval is the least time between reports.
+	progressInterval = 250 * time.Millisecond
+)
+
+// now returns the current time. It is a variable so tests can replace it.
+var now = time.Now
+
+// isTerminal reports whether w is a terminal, as the error output must be
+// for progress reports, which overwrite each other, to be shown. It is a
+// variable so tests can replace it.
+var isTerminal = func(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// progressReporter returns the progress function installed by
+// ) progress on. Once an operation has run for progressDelay, it prints
+// the percentage done to the error output, at most every progressInterval,
+// each report overwriting the last, and erases the report when the
+// operation finishes. The value package calls it only every few thousand
+// elements, so it may look at the clock every time.
+func progressReporter(conf *config.Config) func(done, total int) {
+	var start, last time.Time
+	prev := 0
+	shown := false
+	return func(done, total int) {
+		t := now()
+		if done < prev {
+			// A new operation; the last one must have failed.
+			start = time.Time{}
+		}
+		prev = done
+		w := conf.ErrOutput()
+		if done >= total {
+			if shown {
+				fmt.Fprint(w, "\r    \r")
+			}
+			start, prev, shown = time.Time{}, 0, false
+			return
+		}
+		if start.IsZero() {
+			start = t
+			return
+		}
+		if t.Sub(start) < progressDelay || shown && t.Sub(last) < progressInterval {
+			return
+		}
+		fmt.Fprintf(w, "\r%3d%%", int(100*int64(done)/int64(total)))
+		last, shown = t, true
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parse
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"robpike.io/ivy/config"
+	"robpike.io/ivy/exec"
+	"robpike.io/ivy/scan"
+)
+
+// TestProgressReporter drives the reporter with a fake clock.
+func TestProgressReporter(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	var clock time.Time
+	now = func() time.Time { return clock }
+	var errs bytes.Buffer
+	conf := new(config.Config)
+	conf.SetErrOutput(&errs)
+	report := progressReporter(conf)
+	step := func(d time.Duration, done, total int) string {
+		clock = clock.Add(d)
+		errs.Reset()
+		report(done, total)
+		return errs.String()
+	}
+	tests := []struct {
+		d           time.Duration
+		done, total int
+		want        string
+	}{
+		// A quick operation prints nothing.
+		{0, 1024, 4096, ""},
+		{100 * time.Millisecond, 2048, 4096, ""},
+		{0, 4096, 4096, ""},
+		// A slow one prints after a second, at most four times a second,
+		// and erases the report when done.
+		{0, 1000, 10000, ""},
+		{900 * time.Millisecond, 2000, 10000, ""},
+		{200 * time.Millisecond, 3000, 10000, "\r 30%"},
+		{100 * time.Millisecond, 4000, 10000, ""},
+		{200 * time.Millisecond, 5000, 10000, "\r 50%"},
+		{time.Second, 10000, 10000, "\r    \r"},
+		// An operation that stopped early is forgotten.
+		{0, 1000, 10000, ""},
+		{2 * time.Second, 9000, 10000, "\r 90%"},
+		{0, 500, 20000, ""},
+		{500 * time.Millisecond, 1000, 20000, ""},
+		{time.Second, 2000, 20000, "\r 10%"},
+	}
+	for i, test := range tests {
+		if got := step(test.d, test.done, test.total); got != test.want {
+			t.Errorf("%d: report(%d, %d) printed %q, want %q", i, test.done, test.total, got, test.want)
+		}
+	}
+}
+
+// TestProgressCommand checks that )progress installs the reporter,
+// but only if the error output is a terminal.
+func TestProgressCommand(t *testing.T) {
+	defer func(f func(io.Writer) bool) { isTerminal = f }(isTerminal)
+	isTerminal = func(io.Writer) bool { return true }
+	var out bytes.Buffer
+	conf := new(config.Config)
+	conf.SetOutput(&out)
+	context := exec.NewContext(conf)
+	run := func(line string) {
+		scanner := scan.New(context, "<test>", bufio.NewReader(strings.NewReader(line+"\n")))
+		p := NewParser("<test>", scanner, context)
+		p.Line()
+	}
+	run(")progress on")
+	if conf.ProgressFunc() == nil {
+		t.Errorf(")progress on did not install a progress function")
+	}
+	run(")progress")
+	run(")progress 0")
+	if conf.ProgressFunc() != nil {
+		t.Errorf(")progress 0 did not remove the progress function")
+	}
+	run(")progress")
+	if got := out.String(); got != "1\n0\n" {
+		t.Errorf("output %q, want %q", got, "1\n0\n")
+	}
+	isTerminal = func(io.Writer) bool { return false }
+	run(")progress on")
+	if conf.ProgressFunc() != nil {
+		t.Errorf(")progress on installed a progress function without a terminal")
+	}
+}
@@ -54,8 +54,10 @@ Mutually recursive functions are an extra wrinkle but easy to resolve.
 // here to save.
 
 // save writes the state of the workspace to the named file.
-// The format of the output is ivy source text.
-func save(c *exec.Context, file string) {
+// The format of the output is ivy source text. Unless all is set,
+// variables and ops defined by the startup prelude, and unchanged
+// since, are left out, as the prelude will define them again.
+func save(c *exec.Context, file string, all bool) {
 	// "<conf.out>" is a special case for testing.
 	conf := c.Config()
 	out := conf.Output()
@@ -76,38 +78,38 @@ func save(c *exec.Context, file string) {
 	ibase, obase := conf.Base()
 	fmt.Fprintf(out, ")maxbits %d\n", conf.MaxBits())
 	fmt.Fprintf(out, ")maxdigits %d\n", conf.MaxDigits())
+	fmt.Fprintf(out, ")maxshift %d\n", conf.MaxShift())
 	fmt.Fprintf(out, ")origin %d\n", conf.Origin())
 	fmt.Fprintf(out, ")prompt %q\n", conf.Prompt())
 	fmt.Fprintf(out, ")format %q\n", conf.Format())
+	if empty := conf.EmptyVector(); empty != "" {
+		fmt.Fprintf(out, ")empty %q\n", empty)
+	}
+	if sep := conf.Separator(); sep != " " {
+		fmt.Fprintf(out, ")separator %q\n", sep)
+	}
+	if mode := conf.RoundingMode(); mode != config.RoundHalfAway {
+		fmt.Fprintf(out, ")rounding %s\n", mode)
+	}
+	if sep := conf.DecimalSeparator(); sep != '.' {
+		fmt.Fprintf(out, ")decimal %q\n", string(sep))
+	}
+	if width := conf.LogicalShift(); width > 0 {
+		fmt.Fprintf(out, ")logicalshift %d\n", width)
+	}
+	if conf.StrictBool() {
+		fmt.Fprintf(out, ")strictbool 1\n")
+	}
+	if conf.BoolWords() {
+		fmt.Fprintf(out, ")boolwords 1\n")
+	}
+	if scale := conf.DecimalScale(); scale >= 0 {
+		fmt.Fprintf(out, ")scale %d\n", scale)
+	}
 	conf.SetBase(10, 10)
 
 	// Ops.
-	printed := make(map[exec.OpDef]bool)
-	for _, def := range c.Defs {
-		var fn *exec.Function
-		if def.IsBinary {
-			fn = c.BinaryFn[def.Name]
-		} else {
-			fn = c.UnaryFn[def.Name]
-		}
-		for _, ref := range references(c, fn.Body) {
-			if !printed[ref] {
-				if ref.IsBinary {
-					fmt.Fprintf(out, "op _ %s _\n", ref.Name)
-				} else {
-					fmt.Fprintf(out, "op %s _\n", ref.Name)
-				}
-				printed[ref] = true
-			}
-		}
-		printed[def] = true
-		s := fn.String()
-		if strings.Contains(s, "\n") {
-			// Multiline def must end in blank line.
-			s += "\n"
-		}
-		fmt.Fprintln(out, s)
-	}
+	saveOps(c, out, !all)
 
 	// Global variables.
 	syms := c.Globals
@@ -121,15 +123,21 @@ func save(c *exec.Context, file string) {
 			if sym.name == "pi" || sym.name == "e" {
 				continue
 			}
+			if !all && c.FromPrelude(sym.name) {
+				continue
+			}
 			fmt.Fprintf(out, "%s = ", sym.name)
 			put(conf, out, sym.val)
 			fmt.Fprint(out, "\n")
 		}
 	}
 
-	// Now we can set the base.
+	// Now we can set the base and the kind of number literals.
 	fmt.Fprintf(out, ")ibase %d\n", ibase)
 	fmt.Fprintf(out, ")obase %d\n", obase)
+	if conf.DecimalLiterals() {
+		fmt.Fprintf(out, ")numbers decimal\n")
+	}
 
 	// Restore the configuration's own base.
 	conf.SetBase(ibase, obase)
@@ -158,15 +166,58 @@ func sortSyms(syms map[string]value.Value) []saveSym {
 	return s
 }
 
+// saveOps writes the definitions of the user-defined ops of the context
+// in an order that recreates them when read back. The numbers in
+// their bodies are written in the output base, which should be 10.
+// If skipPrelude is set, ops from the startup prelude are omitted,
+// as are forward declarations of them, which would replace the
+// prelude's definitions when the output is read back.
+func saveOps(c *exec.Context, out io.Writer, skipPrelude bool) {
+	printed := make(map[exec.OpDef]bool)
+	for _, def := range c.Defs {
+		if skipPrelude && c.OpFromPrelude(def) {
+			printed[def] = true
+			continue
+		}
+		fn := c.Op(def)
+		for _, ref := range references(c, fn.Body) {
+			if skipPrelude && c.OpFromPrelude(ref) {
+				continue
+			}
+			if !printed[ref] {
+				if ref.IsBinary && c.Op(ref).LeftOp {
+					fmt.Fprintf(out, "op (_) %s _\n", ref.Name)
+				} else if ref.IsBinary {
+					fmt.Fprintf(out, "op _ %s _\n", ref.Name)
+				} else {
+					fmt.Fprintf(out, "op %s _\n", ref.Name)
+				}
+				printed[ref] = true
+			}
+		}
+		printed[def] = true
+		s := fn.String()
+		if strings.Contains(s, "\n") {
+			// Multiline def must end in blank line.
+			s += "\n"
+		}
+		fmt.Fprintln(out, s)
+	}
+}
+
 // put writes to out a version of the value that will recreate it when parsed.
 func put(conf *config.Config, out io.Writer, val value.Value) {
 	switch val := val.(type) {
+	case value.Bool:
+		fmt.Fprint(out, val.ProgString())
 	case value.Char:
 		fmt.Fprintf(out, "%q", rune(val))
 	case value.Int:
 		fmt.Fprintf(out, "%d", int(val))
 	case value.BigInt:
 		fmt.Fprintf(out, "%d", val.Int)
+	case value.Decimal:
+		fmt.Fprint(out, val.ProgString())
 	case value.BigRat:
 		fmt.Fprintf(out, "%d/%d", val.Num(), val.Denom())
 	case value.BigFloat:
@@ -201,6 +252,8 @@ func put(conf *config.Config, out io.Writer, val value.Value) {
 		put(conf, out, value.NewIntVector(val.Shape()))
 		fmt.Fprint(out, " rho ")
 		put(conf, out, val.Data())
+	case *value.BoundOp:
+		fmt.Fprint(out, val.Repr())
 	default:
 		value.Errorf("internal error: can't save type %T", val)
 	}
@@ -0,0 +1,64 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parse
+
+import (
+	"strings"
+
+	"robpike.io/ivy/exec"
+	"robpike.io/ivy/scan"
+	"robpike.io/ivy/value"
+)
+
+// Support for exec.Context.Snapshot and exec.RestoreContext, which
+// record ops as source text and need a parser to recreate them.
+
+func init() {
+	exec.OpText = opText
+	exec.DefineOps = defineOps
+}
+
+// opText returns the source text of the ops of the context,
+// as written by save, with numbers in base 10.
+func opText(c *exec.Context) string {
+	conf := c.Config()
+	ibase, obase := conf.Base()
+	conf.SetBase(ibase, 10)
+	defer conf.SetBase(ibase, obase)
+	var b strings.Builder
+	saveOps(c, &b, false)
+	return b.String()
+}
+
+// defineOps parses the op definitions in src, as written by opText,
+// and installs them in the context. Anything but an op definition
+// is an error; nothing in src is evaluated.
+func defineOps(c *exec.Context, src string) (err error) {
+	conf := c.Config()
+	ibase, obase := conf.Base()
+	conf.SetBase(10, obase)
+	defer func() {
+		conf.SetBase(ibase, obase)
+		if e := recover(); e != nil {
+			ivyErr, ok := e.(value.Error)
+			if !ok {
+				panic(e)
+			}
+			err = ivyErr
+		}
+	}()
+	p := NewParser("<snapshot>", scan.New(c, "<snapshot>", strings.NewReader(src)), c)
+	for p.readTokensToNewline() {
+		switch p.peek().Type {
+		case scan.EOF:
+			continue
+		case scan.Op:
+			p.functionDefn()
+		default:
+			p.errorf("expected op definition, found %s", p.peek())
+		}
+	}
+	return nil
+}
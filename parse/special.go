@@ -7,12 +7,13 @@
 package parse // import "robpike.io/ivy/parse"
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"os"
 	"sort"
 	"strings"
+	"time"
+	"unicode"
 
 	"robpike.io/ivy/config"
 	"robpike.io/ivy/demo"
@@ -48,6 +49,24 @@ func (p *Parser) nextDecimalNumber() int {
 	return int(n64)
 }
 
+// nextOnOff returns the next setting of a boolean special command,
+// which may be on, off, or a number that turns it on if not zero.
+// Cmd names the command in the error message.
+func (p *Parser) nextOnOff(cmd string) bool {
+	if p.peek().Type != scan.Identifier {
+		return p.nextDecimalNumber() != 0
+	}
+	switch word := p.next().Text; word {
+	case "on":
+		return true
+	case "off":
+		return false
+	default:
+		p.errorf("%s: expected on or off, not %s", cmd, word)
+	}
+	panic("not reached")
+}
+
 // nextDecimalNumber64 returns the next number, which
 // must fit in a non-negative int64.
 func (p *Parser) nextDecimalNumber64() int64 {
@@ -77,6 +96,14 @@ func (p *Parser) nextDecimalNumber64() int64 {
 	return n
 }
 
+// angleUnit returns the name of the units of angles, as set by ) angle.
+func angleUnit(conf *config.Config) string {
+	if conf.Degrees() {
+		return "degrees"
+	}
+	return "radians"
+}
+
 func truth(x bool) int {
 	if x {
 		return 1
@@ -84,6 +111,24 @@ func truth(x bool) int {
 	return 0
 }
 
+// builtin returns a description of what the name refers to, as printed
+// by )builtin: an operator, built in or defined by op, a variable, or
+// nothing, in which case it is free to use for either.
+func (p *Parser) builtin(name string) string {
+	c := p.context
+	switch {
+	case value.IsOperator(value.OperatorName(name, true)) || value.IsOperator(value.OperatorName(name, false)):
+		return name + " is a built-in operator"
+	case c.UnaryFn[name] != nil || c.BinaryFn[name] != nil:
+		return name + " is an operator defined by op"
+	case name == "pi" || name == "e":
+		return name + " is a built-in variable"
+	case c.Globals[name] != nil:
+		return name + " is a variable"
+	}
+	return name + " is free"
+}
+
 func (p *Parser) special() {
 	p.need(scan.RightParen)
 	conf := p.context.Config()
@@ -152,6 +197,19 @@ Switch:
 			p.help(str)
 		}
 		p.next()
+	case "angle":
+		if p.peek().Type == scan.EOF {
+			p.Println(angleUnit(conf))
+			break Switch
+		}
+		switch word := p.need(scan.Identifier).Text; word {
+		case "degrees":
+			conf.SetDegrees(true)
+		case "radians":
+			conf.SetDegrees(false)
+		default:
+			p.errorf(")angle: expected degrees or radians, not %s", word)
+		}
 	case "base", "ibase", "obase":
 		if p.peek().Type == scan.EOF {
 			p.Printf("ibase\t%d\n", ibase)
@@ -170,8 +228,30 @@ Switch:
 		case "obase":
 			obase = base
 		}
+	case "boolwords":
+		if p.peek().Type == scan.EOF {
+			p.Println(truth(conf.BoolWords()))
+			break Switch
+		}
+		conf.SetBoolWords(p.nextDecimalNumber() != 0)
+	case "builtin":
+		tok := p.next()
+		if tok.Type != scan.Identifier && tok.Type != scan.Operator {
+			p.errorf(")builtin: expected name, found %s", tok)
+		}
+		p.Println(p.builtin(tok.Text))
 	case "cpu":
 		p.Printf("%s\n", conf.PrintCPUTime())
+	case "decimal":
+		if p.peek().Type == scan.EOF {
+			p.Printf("%q\n", string(conf.DecimalSeparator()))
+			break Switch
+		}
+		sep := []rune(p.getString())
+		if len(sep) != 1 || unicode.IsDigit(sep[0]) {
+			p.errorf("decimal separator must be a single non-digit character")
+		}
+		conf.SetDecimalSeparator(sep[0])
 	case "debug":
 		if p.peek().Type == scan.EOF {
 			for _, f := range config.DebugFlags {
@@ -209,6 +289,35 @@ Switch:
 			p.errorf("%v", err)
 		}
 		p.Println("Demo finished")
+	case "echo":
+		switch p.peek().Type {
+		case scan.EOF:
+			p.Printf("%q\n", conf.Echo())
+			break Switch
+		case scan.String:
+			conf.SetEcho(p.getString())
+		case scan.Identifier:
+			if word := p.next().Text; word != "assign" {
+				p.errorf(")echo: expected assign, not %s", word)
+			}
+			if p.peek().Type == scan.EOF {
+				p.Println(truth(conf.EchoAssign()))
+				break Switch
+			}
+			conf.SetEchoAssign(p.nextOnOff(")echo assign"))
+		default:
+			if p.nextDecimalNumber() == 0 {
+				conf.SetEcho("")
+			} else {
+				conf.SetEcho(config.DefaultEcho)
+			}
+		}
+	case "empty":
+		if p.peek().Type == scan.EOF {
+			p.Printf("%q\n", conf.EmptyVector())
+			break Switch
+		}
+		conf.SetEmptyVector(p.getString())
 	case "format":
 		if p.peek().Type == scan.EOF {
 			p.Printf("%q\n", conf.Format())
@@ -221,6 +330,25 @@ Switch:
 		} else {
 			p.runFromFile(p.context, p.getString())
 		}
+	case "glyphs":
+		if p.peek().Type == scan.EOF {
+			p.Println(truth(conf.Glyphs()))
+			break Switch
+		}
+		conf.SetGlyphs(p.nextOnOff(")glyphs"))
+	case "import":
+		name := p.need(scan.String, scan.Identifier)
+		file := name.Text + ".ivy"
+		if name.Type == scan.String {
+			file = value.ParseString(name.Text)
+		}
+		p.importFile(p.context, file)
+	case "logicalshift":
+		if p.peek().Type == scan.EOF {
+			p.Printf("%d\n", conf.LogicalShift())
+			break Switch
+		}
+		conf.SetLogicalShift(uint(p.nextDecimalNumber()))
 	case "maxbits":
 		if p.peek().Type == scan.EOF {
 			p.Printf("%d\n", conf.MaxBits())
@@ -235,6 +363,20 @@ Switch:
 		}
 		max := p.nextDecimalNumber()
 		conf.SetMaxDigits(uint(max))
+	case "maxread":
+		if p.peek().Type == scan.EOF {
+			p.Printf("%d\n", conf.MaxRead())
+			break Switch
+		}
+		max := p.nextDecimalNumber()
+		conf.SetMaxRead(uint(max))
+	case "maxshift":
+		if p.peek().Type == scan.EOF {
+			p.Printf("%d\n", conf.MaxShift())
+			break Switch
+		}
+		max := p.nextDecimalNumber()
+		conf.SetMaxShift(uint(max))
 	case "maxstack":
 		if p.peek().Type == scan.EOF {
 			p.Printf("%d\n", conf.MaxStack())
@@ -242,6 +384,23 @@ Switch:
 		}
 		max := p.nextDecimalNumber()
 		conf.SetMaxStack(uint(max))
+	case "numbers":
+		if p.peek().Type == scan.EOF {
+			if conf.DecimalLiterals() {
+				p.Println("decimal")
+			} else {
+				p.Println("rational")
+			}
+			break Switch
+		}
+		switch kind := p.need(scan.Identifier).Text; kind {
+		case "decimal":
+			conf.SetDecimalLiterals(true)
+		case "rational":
+			conf.SetDecimalLiterals(false)
+		default:
+			p.errorf(")numbers: unknown kind %s; must be decimal or rational", kind)
+		}
 	case "op", "ops": // We keep forgetting whether it's a plural or not.
 		if p.peek().Type == scan.EOF {
 			var unary, binary []string
@@ -304,26 +463,154 @@ Switch:
 			p.errorf("illegal prec %d", prec) // TODO: make 0 be disable?
 		}
 		conf.SetFloatPrec(uint(prec))
+	case "progress":
+		if p.peek().Type == scan.EOF {
+			p.Println(truth(conf.ProgressFunc() != nil))
+			break Switch
+		}
+		// Reports to a file or pipe would be clutter, so they are only
+		// made to a terminal.
+		if p.nextOnOff(")progress") && isTerminal(conf.ErrOutput()) {
+			conf.SetProgressFunc(progressReporter(conf))
+		} else {
+			conf.SetProgressFunc(nil)
+		}
 	case "prompt":
 		if p.peek().Type == scan.EOF {
 			p.Printf("%q\n", conf.Format())
 			break Switch
 		}
 		conf.SetPrompt(p.getString())
+	case "rounding":
+		if p.peek().Type == scan.EOF {
+			p.Println(conf.RoundingMode())
+			break Switch
+		}
+		name := p.need(scan.Identifier).Text
+		mode, ok := config.ParseRoundingMode(name)
+		if !ok {
+			p.errorf(")rounding: unknown mode %s; must be away, even, zero, up, or down", name)
+		}
+		conf.SetRoundingMode(mode)
+	case "scale":
+		switch p.peek().Type {
+		case scan.EOF:
+			if scale := conf.DecimalScale(); scale < 0 {
+				p.Println("off")
+			} else {
+				p.Println(scale)
+			}
+			break Switch
+		case scan.Identifier:
+			if off := p.next().Text; off != "off" {
+				p.errorf(")scale: must be a number or off, not %s", off)
+			}
+			conf.SetDecimalScale(-1)
+		default:
+			scale := p.nextDecimalNumber()
+			if scale > 1e6 {
+				p.errorf("illegal scale %d", scale)
+			}
+			conf.SetDecimalScale(scale)
+		}
 	case "save":
 		// Must restore ibase, obase for save.
 		conf.SetBase(ibase, obase)
+		file := defaultFile
+		if p.peek().Type == scan.String {
+			file = p.getString()
+		}
+		all := false
+		if p.peek().Type != scan.EOF {
+			if word := p.need(scan.Identifier).Text; word != "all" {
+				p.errorf(")save: expected all, not %s", word)
+			}
+			all = true
+		}
+		save(p.context, file, all)
+	case "separator":
 		if p.peek().Type == scan.EOF {
-			save(p.context, defaultFile)
-		} else {
-			save(p.context, p.getString())
+			p.Printf("%q\n", conf.Separator())
+			break Switch
 		}
+		conf.SetSeparator(p.getString())
 	case "seed":
+		switch p.peek().Type {
+		case scan.EOF:
+			if conf.RandomSource() == config.RandomCrypto {
+				p.Println("crypto")
+			} else {
+				p.Println(conf.RandomSeed())
+			}
+			break Switch
+		case scan.Identifier:
+			switch mode := p.next().Text; mode {
+			case "time":
+				conf.SetRandomSource(config.RandomTime)
+			case "crypto":
+				conf.SetRandomSource(config.RandomCrypto)
+			default:
+				p.errorf(")seed: unknown source %s; must be a number, time, or crypto", mode)
+			}
+		default:
+			conf.SetRandomSeed(p.nextDecimalNumber64())
+		}
+	case "state":
+		switch p.peek().Type {
+		case scan.EOF:
+			p.printState(false)
+		case scan.Identifier:
+			if format := p.next().Text; format != "json" {
+				p.errorf(")state: unknown format %s; must be json", format)
+			}
+			p.printState(true)
+		default:
+			p.errorf(")state: expected json")
+		}
+	case "strictbool":
+		if p.peek().Type == scan.EOF {
+			p.Println(truth(conf.StrictBool()))
+			break Switch
+		}
+		conf.SetStrictBool(p.nextDecimalNumber() != 0)
+	case "time":
+		// Must restore ibase, obase to parse and print the expressions.
+		conf.SetBase(ibase, obase)
+		p.timeLine()
+	case "type":
+		switch p.peek().Type {
+		case scan.EOF:
+			p.Println(truth(conf.ShowTypes()))
+			break Switch
+		case scan.Identifier:
+			switch word := p.next().Text; word {
+			case "on":
+				conf.SetShowTypes(true)
+			case "off":
+				conf.SetShowTypes(false)
+			default:
+				p.errorf(")type: expected on or off, not %s", word)
+			}
+		default:
+			conf.SetShowTypes(p.nextDecimalNumber() != 0)
+		}
+	case "width":
 		if p.peek().Type == scan.EOF {
-			p.Println(conf.RandomSeed())
+			p.Println(conf.Width())
 			break Switch
 		}
-		conf.SetRandomSeed(p.nextDecimalNumber64())
+		width := p.nextDecimalNumber()
+		if width < 0 {
+			p.errorf("illegal width %d", width)
+		}
+		conf.SetWidth(width)
+	case "write":
+		file := p.getString()
+		last := p.context.Global("_")
+		if last == nil {
+			p.errorf(")write: no value to write")
+		}
+		value.WriteBytes(p.context, ")write", file, last)
 	default:
 		p.errorf(")%s: not recognized", text)
 	}
@@ -351,6 +638,17 @@ func (p *Parser) runFromFile(context value.Context, name string) {
 	p.runFromReader(context, name, fd, true)
 }
 
+// importFile executes the contents of the named file, typically a set
+// of definitions, reporting errors line by line and continuing after them.
+func (p *Parser) importFile(context value.Context, name string) {
+	fd, err := os.Open(name)
+	if err != nil {
+		p.errorf("%s", err)
+	}
+	defer fd.Close()
+	p.runFromReader(context, name, fd, false)
+}
+
 // runFromReader executes the contents of the io.Reader, identified by name.
 func (p *Parser) runFromReader(context value.Context, name string, reader io.Reader, stopOnError bool) {
 	runDepth++
@@ -364,13 +662,14 @@ func (p *Parser) runFromReader(context value.Context, name string, reader io.Rea
 			return
 		}
 		if err, ok := err.(value.Error); ok {
-			fmt.Fprintf(p.context.Config().ErrOutput(), "%s%s\n", p.Loc(), err)
+			fmt.Fprintf(p.context.Config().ErrOutput(), "%s%s\n%s", p.Loc(), err, p.Caret())
 			return
 		}
 		panic(err)
 	}()
-	scanner := scan.New(context, name, bufio.NewReader(reader))
+	scanner := scan.NewReader(context, name, reader)
 	parser := NewParser(name, scanner, p.context)
+	parser.saved = stopOnError // Only )get, which reads saved sessions, stops.
 	for parser.runUntilError(name) != io.EOF {
 		if stopOnError {
 			break
@@ -390,7 +689,7 @@ func (p *Parser) runUntilError(name string) error {
 			return
 		}
 		if err, ok := err.(value.Error); ok {
-			fmt.Fprintf(p.context.Config().ErrOutput(), "%s%s\n", p.Loc(), err)
+			fmt.Fprintf(p.context.Config().ErrOutput(), "%s%s\n%s", p.Loc(), err, p.Caret())
 			return
 		}
 		panic(err)
@@ -402,11 +701,12 @@ func (p *Parser) runUntilError(name string) error {
 			if val == nil {
 				continue
 			}
-			if _, ok := val.(Assignment); ok {
+			str, ok := printed(p.context.Config(), val)
+			if !ok {
 				continue
 			}
-			p.context.AssignGlobal("_", val)
-			fmt.Fprintf(p.context.Config().Output(), "%v\n", val.Sprint(p.context.Config()))
+			p.context.AssignGlobal("_", val.Inner())
+			fmt.Fprintf(p.context.Config().Output(), "%v\n", str)
 		}
 		if !ok {
 			return io.EOF
@@ -414,6 +714,38 @@ func (p *Parser) runUntilError(name string) error {
 	}
 }
 
+// timeLine parses and evaluates the rest of the line, printing the values
+// as for an ordinary line and then the elapsed time, which is also
+// what )cpu reports afterwards.
+func (p *Parser) timeLine() {
+	conf := p.context.Config()
+	if p.peek().Type == scan.EOF {
+		p.errorf(")time: expected expression")
+	}
+	start := time.Now()
+	exprs, _ := p.expressionList()
+	var values []value.Value
+	for _, expr := range exprs {
+		if val := expr.Eval(p.context); val != nil {
+			values = append(values, val)
+		}
+	}
+	conf.SetCPUTime(time.Since(start), 0, 0)
+	var strs []string
+	for _, val := range values {
+		str, ok := printed(conf, val)
+		if !ok {
+			continue
+		}
+		p.context.AssignGlobal("_", val.Inner())
+		strs = append(strs, str)
+	}
+	if len(strs) > 0 {
+		p.Println(strings.Join(strs, " "))
+	}
+	p.Printf("(%s)\n", conf.PrintCPUTime())
+}
+
 // A simple way to connect the user's input to the interpreter.
 // Sending one byte at a time is slow but very easy, and
 // it's just for a demo.
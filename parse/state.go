@@ -0,0 +1,200 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"robpike.io/ivy/config"
+	"robpike.io/ivy/exec"
+	"robpike.io/ivy/value"
+)
+
+// version returns a description of the ivy build and the Go release
+// and platform it runs on. It is a variable so tests can replace it.
+var version = func() string {
+	v := "(devel)"
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		v = info.Main.Version
+	}
+	return fmt.Sprintf("ivy %s %s %s/%s", v, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
+
+// state is the report printed by )state, for pasting into bug reports.
+// It describes the variables but does not print their values, which
+// may be large.
+type state struct {
+	Version   string     `json:"version"`
+	Config    settings   `json:"config"`
+	Variables []stateVar `json:"variables"`
+	Ops       []stateOp  `json:"ops"`
+}
+
+type stateVar struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Shape []int  `json:"shape,omitempty"`
+}
+
+type stateOp struct {
+	Name   string `json:"name"`
+	Binary bool   `json:"binary"`
+	Source string `json:"source"`
+}
+
+// A setting is the name, as used in special commands, and the value
+// of a configuration setting.
+type setting struct {
+	name  string
+	value interface{}
+}
+
+// A word is a setting that is printed without quotes, like the
+// argument of )rounding, unlike a string setting such as )format.
+type word string
+
+// settings is a list of settings, kept in order. As JSON it is
+// an object, with the settings in the same order.
+type settings []setting
+
+func (s settings) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, set := range s {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(set.name)
+		val, err := json.Marshal(set.value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(val)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// getState returns the state of the configuration and context.
+func getState(conf *config.Config, c *exec.Context) *state {
+	ibase, obase := conf.Base()
+	var seed interface{} = conf.RandomSeed()
+	switch conf.RandomSource() {
+	case config.RandomTime:
+		seed = word("time")
+	case config.RandomCrypto:
+		seed = word("crypto")
+	}
+	numbers := word("rational")
+	if conf.DecimalLiterals() {
+		numbers = "decimal"
+	}
+	var scale interface{} = conf.DecimalScale()
+	if conf.DecimalScale() < 0 {
+		scale = word("off")
+	}
+	debugFlags := []string{}
+	for _, flag := range config.DebugFlags {
+		if conf.Debug(flag) {
+			debugFlags = append(debugFlags, flag)
+		}
+	}
+	s := &state{
+		Version: version(),
+		Config: settings{
+			{"format", conf.Format()},
+			{"origin", conf.Origin()},
+			{"ibase", ibase},
+			{"obase", obase},
+			{"prec", conf.FloatPrec()},
+			{"progress", truth(conf.ProgressFunc() != nil)},
+			{"seed", seed},
+			{"maxbits", conf.MaxBits()},
+			{"maxdigits", conf.MaxDigits()},
+			{"maxshift", conf.MaxShift()},
+			{"maxread", conf.MaxRead()},
+			{"logicalshift", conf.LogicalShift()},
+			{"maxstack", conf.MaxStack()},
+			{"rounding", word(conf.RoundingMode().String())},
+			{"angle", word(angleUnit(conf))},
+			{"numbers", numbers},
+			{"scale", scale},
+			{"strictbool", truth(conf.StrictBool())},
+			{"boolwords", truth(conf.BoolWords())},
+			{"glyphs", truth(conf.Glyphs())},
+			{"type", truth(conf.ShowTypes())},
+			{"decimal", string(conf.DecimalSeparator())},
+			{"separator", conf.Separator()},
+			{"empty", conf.EmptyVector()},
+			{"width", conf.Width()},
+			{"prompt", conf.Prompt()},
+			{"echo", conf.Echo()},
+			{"echoassign", truth(conf.EchoAssign())},
+			{"debug", debugFlags},
+		},
+		Variables: []stateVar{},
+		Ops:       []stateOp{},
+	}
+	for _, name := range c.GlobalNames() {
+		v := c.Globals[name]
+		sv := stateVar{Name: name, Type: value.TypeName(v)}
+		switch v := v.(type) {
+		case value.Vector:
+			sv.Shape = []int{len(v)}
+		case *value.Matrix:
+			sv.Shape = v.Shape()
+		}
+		s.Variables = append(s.Variables, sv)
+	}
+	for _, def := range c.Defs {
+		s.Ops = append(s.Ops, stateOp{def.Name, def.IsBinary, c.Op(def).String()})
+	}
+	return s
+}
+
+// printState prints the state, as text or as JSON.
+func (p *Parser) printState(asJSON bool) {
+	s := getState(p.context.Config(), p.context)
+	if asJSON {
+		data, err := json.MarshalIndent(s, "", "\t")
+		if err != nil {
+			p.errorf("%s", err)
+		}
+		p.Println(string(data))
+		return
+	}
+	p.Println("Version:")
+	p.Println("\t" + s.Version)
+	p.Println("Configuration:")
+	for _, set := range s.Config {
+		switch val := set.value.(type) {
+		case string:
+			p.Printf("\t%s %q\n", set.name, val)
+		case []string:
+			p.Println("\t" + strings.Join(append([]string{set.name}, val...), " "))
+		default:
+			p.Printf("\t%s %v\n", set.name, val)
+		}
+	}
+	p.Println("Variables:")
+	for _, v := range s.Variables {
+		p.Printf("\t%s %s", v.Name, v.Type)
+		for _, n := range v.Shape {
+			p.Printf(" %d", n)
+		}
+		p.Println("")
+	}
+	p.Println("Ops:")
+	for _, op := range s.Ops {
+		p.Println("\t" + strings.ReplaceAll(op.Source, "\n", "\n\t"))
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parse
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"robpike.io/ivy/config"
+	"robpike.io/ivy/exec"
+	"robpike.io/ivy/scan"
+)
+
+const stateSession = `
+)origin 0
+)seed 7
+)debug types 1
+)format "%.3f"
+)numbers decimal
+)scale 2
+x = 1 2 3
+m = 2 3 4 rho 1
+s = 'hello'
+n = 1.50
+r = 1/3
+op f x = x + 1
+op a g b =
+	a + b
+	a * b
+
+`
+
+const stateText = `Version:
+	ivy test
+Configuration:
+	format "%.3f"
+	origin 0
+	ibase 0
+	obase 0
+	prec 256
+	progress 0
+	seed 7
+	maxbits 1000000
+	maxdigits 10000
+	maxshift 1000000
+	maxread 1000000
+	logicalshift 0
+	maxstack 100000
+	rounding away
+	angle radians
+	numbers decimal
+	scale 2
+	strictbool 0
+	boolwords 0
+	glyphs 1
+	type 0
+	decimal "."
+	separator " "
+	empty ""
+	width 0
+	prompt ""
+	echo ""
+	echoassign 0
+	debug types
+Variables:
+	m matrix 2 3 4
+	n decimal
+	r rational
+	s vector 5
+	x vector 3
+Ops:
+	op f x = x + 1
+	op a g b =
+		a + b
+		a * b
+`
+
+const stateJSON = `{
+	"version": "ivy test",
+	"config": {
+		"format": "%.3f",
+		"origin": 0,
+		"ibase": 0,
+		"obase": 0,
+		"prec": 256,
+		"progress": 0,
+		"seed": 7,
+		"maxbits": 1000000,
+		"maxdigits": 10000,
+		"maxshift": 1000000,
+		"maxread": 1000000,
+		"logicalshift": 0,
+		"maxstack": 100000,
+		"rounding": "away",
+		"angle": "radians",
+		"numbers": "decimal",
+		"scale": 2,
+		"strictbool": 0,
+		"boolwords": 0,
+		"glyphs": 1,
+		"type": 0,
+		"decimal": ".",
+		"separator": " ",
+		"empty": "",
+		"width": 0,
+		"prompt": "",
+		"echo": "",
+		"echoassign": 0,
+		"debug": [
+			"types"
+		]
+	},
+	"variables": [
+		{
+			"name": "m",
+			"type": "matrix",
+			"shape": [
+				2,
+				3,
+				4
+			]
+		},
+		{
+			"name": "n",
+			"type": "decimal"
+		},
+		{
+			"name": "r",
+			"type": "rational"
+		},
+		{
+			"name": "s",
+			"type": "vector",
+			"shape": [
+				5
+			]
+		},
+		{
+			"name": "x",
+			"type": "vector",
+			"shape": [
+				3
+			]
+		}
+	],
+	"ops": [
+		{
+			"name": "f",
+			"binary": false,
+			"source": "op f x = x + 1"
+		},
+		{
+			"name": "g",
+			"binary": true,
+			"source": "op a g b =\n\ta + b\n\ta * b"
+		}
+	]
+}
+`
+
+// runState runs the session followed by the )state command
+// and returns what the command prints.
+func runState(t *testing.T, command string) string {
+	t.Helper()
+	var out bytes.Buffer
+	conf := new(config.Config)
+	conf.SetOutput(&out)
+	context := exec.NewContext(conf)
+	src := stateSession + command + "\n"
+	scanner := scan.New(context, "state", bufio.NewReader(strings.NewReader(src)))
+	parser := NewParser("state", scanner, context)
+	for {
+		exprs, ok := parser.Line()
+		if !ok {
+			break
+		}
+		context.Eval(exprs)
+	}
+	return out.String()
+}
+
+func TestState(t *testing.T) {
+	defer func(v func() string) { version = v }(version)
+	version = func() string { return "ivy test" }
+	if got := runState(t, ")state"); got != stateText {
+		t.Errorf(")state:\n%s\nwant:\n%s", got, stateText)
+	}
+	if got := runState(t, ")state json"); got != stateJSON {
+		t.Errorf(")state json:\n%s\nwant:\n%s", got, stateJSON)
+	}
+}
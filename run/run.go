@@ -14,6 +14,7 @@ import (
 	"math/big"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"robpike.io/ivy/config"
 	"robpike.io/ivy/parse"
@@ -47,29 +48,25 @@ var cpuTime = func() (user, sys time.Duration) { return 0, 0 }
 // Typical execution is therefore to loop calling Run until it succeeds.
 // Error details are reported to the configured error output stream.
 func Run(p *parse.Parser, context value.Context, interactive bool) (success bool) {
+	return Exec(p, context, interactive) == nil
+}
+
+// Exec is like Run but returns the error that stopped it, which has
+// also been reported, or nil if it reached EOF. The error's text is the
+// location and the message, without the source line and caret.
+func Exec(p *parse.Parser, context value.Context, interactive bool) (err error) {
 	conf := context.Config()
 	writer := conf.Output()
 	defer func() {
 		if conf.Debug("panic") {
 			return
 		}
-		err := recover()
-		if err == nil {
-			return
-		}
-		_, ok := err.(value.Error)
-		if !ok {
-			_, ok = err.(big.ErrNaN) // Floating point error from math/big.
-		}
-		if ok {
-			fmt.Fprintf(conf.ErrOutput(), "%s%s\n", p.Loc(), err)
+		if e := recover(); e != nil {
+			err = report(p, conf, e)
 			if interactive {
 				fmt.Fprintln(writer)
 			}
-			success = false
-			return
 		}
-		panic(err)
 	}()
 	for {
 		if interactive {
@@ -89,15 +86,15 @@ func Run(p *parse.Parser, context value.Context, interactive bool) (success bool
 			}
 		}
 		if printValues(conf, writer, values) {
-			context.AssignGlobal("_", values[len(values)-1])
+			context.AssignGlobal("_", values[len(values)-1].Inner())
 		}
 		if !ok {
-			return true
+			return nil
 		}
 		if interactive {
 			if exprs != nil && conf.Debug("cpu") {
 				if real, _, _ := conf.CPUTime(); real != 0 {
-					fmt.Printf("(%s)\n", conf.PrintCPUTime())
+					fmt.Fprintf(writer, "(%s)\n", conf.PrintCPUTime())
 				}
 			}
 			fmt.Fprintln(writer)
@@ -105,6 +102,70 @@ func Run(p *parse.Parser, context value.Context, interactive bool) (success bool
 	}
 }
 
+// RunLast is like Run for non-interactive input, but it prints only the
+// values of the last line of input that has any to print, so a script
+// run in a pipeline produces only its final result. Errors are reported
+// as they occur, after which RunLast returns false; as with Run, it can be
+// called again to continue.
+func RunLast(p *parse.Parser, context value.Context) (success bool) {
+	return ExecLast(p, context) == nil
+}
+
+// ExecLast is like RunLast but returns the error, as Exec does.
+func ExecLast(p *parse.Parser, context value.Context) (err error) {
+	conf := context.Config()
+	var last []value.Value
+	defer func() {
+		if conf.Debug("panic") {
+			return
+		}
+		if e := recover(); e != nil {
+			err = report(p, conf, e)
+		}
+	}()
+	for {
+		exprs, ok := p.Line()
+		if exprs != nil {
+			values := context.Eval(exprs)
+			if printable(values) {
+				last = values
+				context.AssignGlobal("_", values[len(values)-1].Inner())
+			}
+		}
+		if !ok {
+			printValues(conf, conf.Output(), last)
+			return nil
+		}
+	}
+}
+
+// report prints the error recovered from a panic during execution to
+// the error output and returns it with its location, or panics again
+// if it is not an error in the program.
+func report(p *parse.Parser, conf *config.Config, e interface{}) error {
+	var err error
+	switch e := e.(type) {
+	case value.Error:
+		err = e
+	case big.ErrNaN: // Floating point error from math/big.
+		err = e
+	default:
+		panic(e)
+	}
+	fmt.Fprintf(conf.ErrOutput(), "%s%s\n%s", p.Loc(), err, p.Caret())
+	return fmt.Errorf("%s%s", p.Loc(), err)
+}
+
+// printable reports whether printValues would print any of the values.
+func printable(values []value.Value) bool {
+	for _, v := range values {
+		if a, ok := v.(parse.Assignment); !ok || a.Name != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // eval runs until EOF or error. It prints every value but the last, and returns the last.
 // By last we mean the last expression of the last evaluation.
 // (Expressions are separated by ; in the input.)
@@ -150,10 +211,25 @@ func printValues(conf *config.Config, writer io.Writer, values []value.Value) bo
 	}
 	printed := false
 	for _, v := range values {
-		if _, ok := v.(parse.Assignment); ok {
-			continue
+		name := ""
+		if a, ok := v.(parse.Assignment); ok {
+			if a.Name == "" {
+				continue
+			}
+			name, v = a.Name, a.Value
 		}
 		s := v.Sprint(conf)
+		if isEmpty(v) {
+			s = conf.EmptyVector()
+		} else if width := conf.Width(); width > 0 {
+			s = wrap(conf, v, s, width)
+		}
+		if conf.ShowTypes() {
+			s += " (" + value.TypeWord(v) + ")"
+		}
+		if name != "" {
+			s = name + " = " + s
+		}
 		if printed && len(s) > 0 && s[len(s)-1] != '\n' {
 			fmt.Fprint(writer, " ")
 		}
@@ -166,6 +242,46 @@ func printValues(conf *config.Config, writer io.Writer, values []value.Value) bo
 	return printed
 }
 
+// wrap breaks s, the printed form of v, into lines no wider than width,
+// if v is a vector of numbers that does not fit. Lines are broken between
+// elements, so an element wider than the line is printed intact.
+func wrap(conf *config.Config, v value.Value, s string, width int) string {
+	vec, ok := v.(value.Vector)
+	if !ok || utf8.RuneCountInString(s) <= width || vec.AllChars() || strings.Contains(s, "\n") {
+		return s
+	}
+	sep := conf.Separator()
+	var b strings.Builder
+	col := 0
+	for i, elem := range vec {
+		str := elem.Sprint(conf)
+		n := utf8.RuneCountInString(str)
+		if i > 0 {
+			if col+len(sep)+n > width {
+				b.WriteByte('\n')
+				col = 0
+			} else {
+				b.WriteString(sep)
+				col += len(sep)
+			}
+		}
+		b.WriteString(str)
+		col += n
+	}
+	return b.String()
+}
+
+// isEmpty reports whether v is a vector or matrix with no elements.
+func isEmpty(v value.Value) bool {
+	switch v := v.(type) {
+	case value.Vector:
+		return len(v) == 0
+	case *value.Matrix:
+		return len(v.Data()) == 0
+	}
+	return false
+}
+
 // Ivy evaluates the input string, appending standard output
 // and error output to the provided buffers, which it does by
 // calling context.Config.SetOutput and SetError.
@@ -0,0 +1,265 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"robpike.io/ivy/config"
+	"robpike.io/ivy/exec"
+	"robpike.io/ivy/value"
+)
+
+// Each error report is the location and message, then the source
+// line and a caret under the column at which the error occurred.
+var caretTests = []struct {
+	input  string
+	errors string
+}{
+	// Evaluation error, with a tab in the source line.
+	{
+		"x = 1 2 3\n\ty = x[5]\n",
+		" :2:7: index x[(5)] out of range for shape (3)\n" +
+			"\ty = x[5]\n" +
+			"\t     ^\n",
+	},
+	// Parse error at the end of the line.
+	{
+		"a = 1 +\n",
+		" :1:8: unexpected EOF\n" +
+			"a = 1 +\n" +
+			"       ^\n",
+	},
+	// Scanner error. The following lines are numbered correctly.
+	{
+		"z = 'abc\nc = 1 2 3 4\t+ 1 2\n",
+		" :1:5: error: unterminated character constant\n" +
+			"z = 'abc\n" +
+			"    ^\n" +
+			" :2:13: +: length mismatch: 4 2\n" +
+			"c = 1 2 3 4\t+ 1 2\n" +
+			"           \t^\n",
+	},
+	// An error in a function is reported at the call.
+	{
+		"op f n = n / 0\nb =\t3 + f 2\n",
+		" :2:9: division by zero\n" +
+			"b =\t3 + f 2\n" +
+			"   \t    ^\n",
+	},
+	// Only the first error on a line is reported.
+	{
+		"y = 1 2 + 1 2 3; undefined\n",
+		" :1:9: +: length mismatch: 2 3\n" +
+			"y = 1 2 + 1 2 3; undefined\n" +
+			"        ^\n",
+	},
+	{
+		"undefined + 2\n",
+		" :1:1: undefined global variable \"undefined\"\n" +
+			"undefined + 2\n" +
+			"^\n",
+	},
+}
+
+func TestErrorCaret(t *testing.T) {
+	for _, test := range caretTests {
+		var stdout, stderr bytes.Buffer
+		Ivy(exec.NewContext(new(config.Config)), test.input, &stdout, &stderr)
+		if got := stderr.String(); got != test.errors {
+			t.Errorf("%q:\ngot:\n%s\nwant:\n%s", test.input, got, test.errors)
+		}
+	}
+}
+
+// TestTrailingGarbage checks the errors for tokens left over after a
+// complete expression, and that the next line still runs.
+func TestTrailingGarbage(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"2 3 4)", `:1:6: unexpected RightParen: ")" after 2 3 4; unmatched ")": is a "(" missing before it?`},
+		{"x = 1 2]", `:1:8: unexpected RightBrack: "]" after x = 1 2; unmatched "]": is a "[" missing before it?`},
+		{"1; (2 + 3))", `:1:11: unexpected RightParen: ")" after 2 + 3; unmatched ")"`},
+		{"v = 1 2 3\nv[1] 2", `:2:6: unexpected Number: "2" after v[1]` + "\n"},
+	}
+	for _, test := range tests {
+		var stdout, stderr bytes.Buffer
+		Ivy(exec.NewContext(new(config.Config)), test.input+"\n7\n", &stdout, &stderr)
+		if got := stderr.String(); !strings.Contains(got, test.err) {
+			t.Errorf("%q: error %q; want %q", test.input, got, test.err)
+		}
+		if got := stdout.String(); !strings.HasSuffix(got, "7\n") {
+			t.Errorf("%q: next line not run; output %q", test.input, got)
+		}
+	}
+}
+
+// TestEchoErrors checks that when output and errors go to the same
+// place, an error follows the echo of the line that caused it.
+func TestEchoErrors(t *testing.T) {
+	conf := new(config.Config)
+	conf.SetEcho("> ")
+	var out bytes.Buffer
+	Ivy(exec.NewContext(conf), "1\n2 / 0\n3\n", &out, &out)
+	want := "> 1\n1\n> 2 / 0\n :2:3: division by zero\n2 / 0\n  ^\n> 3\n3\n"
+	if got := out.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestWrite checks that )write writes the last value printed
+// and that readbytes reads it back.
+func TestWrite(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "out")
+	out := runExpr(t, fmt.Sprintf("1 2 3\ncode 'ivy'\n)write %q\nreadbytes %[1]q\n", name))
+	if want := "1 2 3\n105 118 121\n105 118 121\n"; out != want {
+		t.Errorf("output %q, want %q", out, want)
+	}
+	if data, err := os.ReadFile(name); err != nil || string(data) != "ivy" {
+		t.Errorf("file holds %q, %v; want %q", data, err, "ivy")
+	}
+}
+
+// TestImport checks that )import defines the ops in a file and
+// reports an error in it without stopping.
+func TestImport(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "defs.ivy")
+	defs := "op sq n = n*n\nop bad n = n +\nop cube n = n * sq n\n"
+	if err := os.WriteFile(name, []byte(defs), 0666); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	Ivy(exec.NewContext(new(config.Config)), fmt.Sprintf(")import %q\nsq 5\ncube 3\n", name), &stdout, &stderr)
+	if want := "25\n27\n"; stdout.String() != want {
+		t.Errorf("output %q, want %q", stdout.String(), want)
+	}
+	if errs := stderr.String(); !strings.Contains(errs, "defs.ivy:2:") {
+		t.Errorf("error for line 2 not reported: %q", errs)
+	}
+}
+
+// TestOperatorNames checks that defining an op replaces a variable of
+// the same name, with a warning, and that a saved session that assigns
+// a variable with the name of an operator still loads, with a warning
+// for that line.
+func TestOperatorNames(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	Ivy(exec.NewContext(new(config.Config)), "x = 3\nop x n = n * 2\nx 5\n", &stdout, &stderr)
+	if want := "10\n"; stdout.String() != want {
+		t.Errorf("output %q, want %q", stdout.String(), want)
+	}
+	if want := "warning: op x replaces variable x\n"; stderr.String() != want {
+		t.Errorf("errors %q, want %q", stderr.String(), want)
+	}
+
+	name := filepath.Join(t.TempDir(), "old.ivy")
+	saved := "v = 1 2 3\nmin = 3\nop sq n = n*n\n"
+	if err := os.WriteFile(name, []byte(saved), 0666); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	stderr.Reset()
+	Ivy(exec.NewContext(new(config.Config)), fmt.Sprintf(")get %q\nsq v\n", name), &stdout, &stderr)
+	if want := "1 4 9\n"; stdout.String() != want {
+		t.Errorf("output %q, want %q", stdout.String(), want)
+	}
+	if want := name + ":2: warning: skipping variable min: cannot assign to operator min; rename it\n"; stderr.String() != want {
+		t.Errorf("errors %q, want %q", stderr.String(), want)
+	}
+}
+
+// TestConcurrentConfig evaluates in one goroutine while another
+// changes the configuration. Run it with -race.
+func TestConcurrentConfig(t *testing.T) {
+	conf := new(config.Config)
+	context := exec.NewContext(conf)
+	done := make(chan bool)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for on := true; ; on = !on {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			conf.SetDebug("cpu", on)
+			conf.SetDebug("types", on)
+			if on {
+				conf.SetFormat("%.2f")
+			} else {
+				conf.SetFormat("")
+			}
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		var stdout, stderr bytes.Buffer
+		Ivy(context, "3 4 rho iota 12; +/iota 100; 1/3 2/3", &stdout, &stderr)
+		if stderr.Len() > 0 {
+			t.Fatalf("error: %s", stderr.String())
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+// TestParallelEval evaluates expressions in many goroutines at once, with
+// the built-in operators splitting their work across goroutines too, and
+// checks that each gets the result it gets alone. Run it with -race.
+func TestParallelEval(t *testing.T) {
+	value.MaxParallelismForTesting()
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	exprs := []string{
+		"+/ (iota 100) o.* iota 100",
+		"(3 4 rho iota 12) +.* 4 3 rho iota 12",
+		"sin 0.1 * iota 50",
+		"phase (iota 20) j -1",
+		"2 ** 1 + iota 200",
+		"x = 100 rho 1/3; +/ x * x",
+		"op a max b = a + b\n+/ +/ (iota 100) o.max iota 100",
+		"op a min b = a - b\nmin/ 50 20 rho iota 1000",
+		"op double n = 2 * n\ndouble 1 3 5",
+		"sqrt iota 40",
+		"'rat' promote 1 2/3 4",
+		"(32 0x80000001) rotl iota 32",
+	}
+	want := make([]string, len(exprs))
+	for i, expr := range exprs {
+		want[i] = runExpr(t, expr)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i, expr := range exprs {
+				if got := runExpr(t, expr); got != want[i] {
+					t.Errorf("%s: got %q, want %q", expr, got, want[i])
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// runExpr runs expr in a new context and returns what it prints.
+func runExpr(t *testing.T, expr string) string {
+	var stdout, stderr bytes.Buffer
+	Ivy(exec.NewContext(new(config.Config)), expr, &stdout, &stderr)
+	if stderr.Len() > 0 {
+		t.Errorf("%s: %s", expr, stderr.String())
+	}
+	return stdout.String()
+}
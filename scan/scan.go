@@ -7,6 +7,8 @@
 package scan // import "robpike.io/ivy/scan"
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"strings"
@@ -19,9 +21,11 @@ import (
 
 // Token represents a token or text string returned from the scanner.
 type Token struct {
-	Type Type   // The type of this item.
-	Line int    // The line number on which this token appears
-	Text string // The text of this item.
+	Type   Type   // The type of this item.
+	Line   int    // The line number on which this token appears, starting at 1.
+	Column int    // The column, in characters, at which the token starts, starting at 1.
+	Offset int    // The byte offset of the start of the token in the input, starting at 0.
+	Text   string // The text of this item.
 }
 
 // Type identifies the type of lex items.
@@ -32,7 +36,7 @@ const (
 	Error      // error occurred; value is text of error
 	Newline
 	// Interesting things
-	Assign     // '='
+	Assign     // '=' or '::='
 	Char       // printable ASCII character; grab bag for comma etc.
 	Identifier // alphanumeric identifier
 	LeftBrack  // '['
@@ -74,6 +78,9 @@ type Scanner struct {
 	name      string // the name of the input; used only for error reports
 	buf       []byte // I/O buffer, re-used.
 	input     string // the line of text being scanned.
+	offset    int    // byte offset in the whole input of the beginning of input
+	src       string // the most recent lines of input, retained for error reports
+	srcLine   int    // the line number of the beginning of src
 	lastRune  rune   // most recent return from next()
 	lastWidth int    // size of that rune
 	readOK    bool   // allow reading of a new line of input
@@ -83,9 +90,15 @@ type Scanner struct {
 	token     Token
 }
 
+// continuation is a backslash at the end of a line, which joins the
+// line to the next. The scanner reads the lines together and steps
+// over the continuation wherever it appears, even inside a token.
+const continuation = "\\\n"
+
 // loadLine reads the next line of input and stores it in (appends it to) the input.
 // (l.input may have data left over when we are called.)
 // It strips carriage returns to make subsequent processing simpler.
+// A line ending in a continuation is read together with the next.
 func (l *Scanner) loadLine() {
 	l.buf = l.buf[:0]
 	for {
@@ -97,21 +110,46 @@ func (l *Scanner) loadLine() {
 		if c != '\r' { // There will never be a \r in l.input.
 			l.buf = append(l.buf, c)
 		}
-		if c == '\n' {
+		if c == '\n' && !bytes.HasSuffix(l.buf, []byte(continuation)) {
 			break
 		}
 	}
 	// Reset to beginning of input buffer if there is nothing pending.
 	if l.start == l.pos {
+		l.offset += len(l.input)
 		l.input = string(l.buf)
 		l.start = 0
 		l.pos = 0
+		l.srcLine = l.line
 	} else {
 		l.input += string(l.buf)
 	}
+	l.src = l.input
 }
 
-// readRune reads the next rune from the input.
+// Source returns the text, without the newline, of the numbered line
+// of input. Only the line holding the most recent token is retained.
+// The boolean reports whether the line is available.
+func (l *Scanner) Source(line int) (string, bool) {
+	if line < l.srcLine || l.src == "" {
+		return "", false
+	}
+	text := l.src
+	for i := l.srcLine; i < line; i++ {
+		nl := strings.IndexByte(text, '\n')
+		if nl < 0 {
+			return "", false
+		}
+		text = text[nl+1:]
+	}
+	if nl := strings.IndexByte(text, '\n'); nl >= 0 {
+		text = text[:nl]
+	}
+	return text, true
+}
+
+// readRune reads the next rune from the input. The width it returns
+// includes any continuations before the rune, which are thus skipped.
 func (l *Scanner) readRune() (rune, int) {
 	if !l.done && l.pos == len(l.input) {
 		if !l.readOK { // Token did not end before newline.
@@ -120,10 +158,15 @@ func (l *Scanner) readRune() (rune, int) {
 		}
 		l.loadLine()
 	}
-	if len(l.input) == l.pos {
+	skip := 0
+	for strings.HasPrefix(l.input[l.pos+skip:], continuation) {
+		skip += len(continuation)
+	}
+	if len(l.input) == l.pos+skip {
 		return eof, 0
 	}
-	return utf8.DecodeRuneInString(l.input[l.pos:])
+	r, w := utf8.DecodeRuneInString(l.input[l.pos+skip:])
+	return r, skip + w
 }
 
 // next returns the next rune in the input.
@@ -141,10 +184,11 @@ func (l *Scanner) peek() rune {
 
 // peek2 returns the next two runes ahead, but does not consume anything.
 func (l *Scanner) peek2() (rune, rune) {
-	pos := l.pos
+	pos, lastRune, lastWidth := l.pos, l.lastRune, l.lastWidth
 	r1 := l.next()
 	r2 := l.next()
-	l.pos = pos
+	// Restore lastRune too, or a following backup could misbehave.
+	l.pos, l.lastRune, l.lastWidth = pos, lastRune, lastWidth
 	return r1, r2
 }
 
@@ -163,19 +207,38 @@ func (l *Scanner) backup() {
 
 // emit passes an item back to the client.
 func (l *Scanner) emit(t Type) stateFn {
-	if t == Newline {
-		l.line++
-	}
-	text := l.input[l.start:l.pos]
+	l.token = l.tokenAt(t, l.start, l.input[l.start:l.pos])
+	// Count the newline, or those in a raw string or continuations.
+	l.line += strings.Count(l.input[l.start:l.pos], "\n")
 	config := l.context.Config()
 	if config.Debug("tokens") {
-		fmt.Fprintf(config.Output(), "%s:%d: emit %s\n", l.name, l.line, Token{t, l.line, text})
+		fmt.Fprintf(config.Output(), "%s:%d: emit %s\n", l.name, l.line, l.token)
 	}
-	l.token = Token{t, l.line, text}
 	l.start = l.pos
 	return nil
 }
 
+// tokenAt returns a token of the given type and text that starts at
+// position pos in the input. Continuations are removed from the text.
+func (l *Scanner) tokenAt(t Type, pos int, text string) Token {
+	// The input always starts at the beginning of a line, but may
+	// hold more than one if a token spans lines.
+	lineStart := strings.LastIndexByte(l.input[:pos], '\n') + 1
+	return Token{
+		Type:   t,
+		Line:   l.line,
+		Column: utf8.RuneCountInString(l.input[lineStart:pos]) + 1,
+		Offset: l.offset + pos,
+		Text:   strings.ReplaceAll(text, continuation, ""),
+	}
+}
+
+// skip discards the pending input, counting the lines it spans.
+func (l *Scanner) skip() {
+	l.line += strings.Count(l.input[l.start:l.pos], "\n")
+	l.start = l.pos
+}
+
 // accept consumes the next rune if it's from the valid set.
 func (l *Scanner) accept(valid string) bool {
 	if strings.ContainsRune(valid, l.next()) {
@@ -192,9 +255,19 @@ func (l *Scanner) acceptRun(valid string) {
 	l.backup()
 }
 
+// acceptDigits consumes a run of digits from the valid set, which may
+// be separated by underscores. The parser checks their placement.
+func (l *Scanner) acceptDigits(valid string) {
+	for r := l.next(); r == '_' || strings.ContainsRune(valid, r); r = l.next() {
+	}
+	l.backup()
+}
+
 // errorf returns an error token and empties the input.
 func (l *Scanner) errorf(format string, args ...interface{}) stateFn {
-	l.token = Token{Error, l.start, fmt.Sprintf(format, args...)}
+	l.token = l.tokenAt(Error, l.start, fmt.Sprintf(format, args...))
+	l.line += strings.Count(l.input[l.start:], "\n") // The rest of the input is discarded.
+	l.offset += len(l.input)
 	l.start = 0
 	l.pos = 0
 	l.input = l.input[:0]
@@ -212,12 +285,26 @@ func New(context value.Context, name string, r io.ByteReader) *Scanner {
 	return l
 }
 
+// NewReader creates and returns a new scanner that reads from r,
+// buffering it if r is not an io.ByteReader. The input is consumed
+// a line at a time as tokens are requested, so it need not be held
+// in memory all at once, and r may be a pipe or network connection
+// that delivers its data in pieces. Input that ends without a final
+// newline ends the last statement.
+func NewReader(context value.Context, name string, r io.Reader) *Scanner {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return New(context, name, br)
+}
+
 // Next returns the next token.
 func (l *Scanner) Next() Token {
 	l.readOK = true
 	l.lastRune = eof
 	l.lastWidth = 0
-	l.token = Token{EOF, l.pos, "EOF"}
+	l.token = l.tokenAt(EOF, l.pos, "EOF")
 	state := lexAny
 	for {
 		state = state(l)
@@ -230,7 +317,15 @@ func (l *Scanner) Next() Token {
 // state functions
 
 // lexComment scans a comment. The comment marker has been consumed.
+// The comment ends with its line even if the line ends in a continuation,
+// which was read with the next line but does not join it to the comment.
 func lexComment(l *Scanner) stateFn {
+	if nl := strings.IndexByte(l.input[l.pos:], '\n'); nl >= 0 {
+		l.pos += nl
+		l.skip()
+		l.pos++
+		return l.emit(Newline)
+	}
 	for {
 		r := l.next()
 		if r == eof || r == '\n' {
@@ -238,8 +333,9 @@ func lexComment(l *Scanner) stateFn {
 		}
 	}
 	if len(l.input) > 0 {
-		l.pos = len(l.input)
-		l.start = l.pos - 1
+		l.pos = len(l.input) - 1
+		l.skip()
+		l.pos++
 		// Emitting newline also advances l.line.
 		return l.emit(Newline)
 	}
@@ -248,6 +344,12 @@ func lexComment(l *Scanner) stateFn {
 
 // lexAny scans non-space items.
 func lexAny(l *Scanner) stateFn {
+	// A token begins after any continuations.
+	l.peek() // Load the input.
+	for strings.HasPrefix(l.input[l.pos:], continuation) {
+		l.pos += len(continuation)
+	}
+	l.skip()
 	switch r := l.next(); {
 	case r == eof:
 		return nil
@@ -270,7 +372,7 @@ func lexAny(l *Scanner) stateFn {
 		// Otherwise it could be a signed number.
 		if l.start > 0 {
 			rr, _ := utf8.DecodeLastRuneInString(l.input[:l.start])
-			if isAlphaNumeric(rr) || rr == ')' || rr == ']' {
+			if (isAlphaNumeric(rr) || rr == ')' || rr == ']') && l.isOperator(r) {
 				return lexOperator
 			}
 			// Ugly corner case: inner product starting with '-' or '+'.
@@ -280,6 +382,11 @@ func lexAny(l *Scanner) stateFn {
 		}
 		fallthrough
 	case r == '.' || '0' <= r && r <= '9':
+		if r1, r2 := l.peek2(); r == '0' && r1 == 'd' && r2 == '{' {
+			l.next()
+			l.next()
+			return lexBracedNumber
+		}
 		l.backup()
 		return lexComplex
 	case r == '=':
@@ -292,12 +399,18 @@ func lexAny(l *Scanner) stateFn {
 		// Must be after after = so == is an operator,
 		// and after numbers, so '-' can be a sign.
 		return lexOperator
-	case isAlphaNumeric(r):
+	case isAlphaNumeric(r) && !isMark(r):
 		l.backup()
 		return lexIdentifier
 	case r == '[':
 		return l.emit(LeftBrack)
 	case r == ':':
+		if r1, r2 := l.peek2(); r1 == ':' && r2 == '=' {
+			// ::= is an assignment that is always printed.
+			l.next()
+			l.next()
+			return l.emit(Assign)
+		}
 		return l.emit(Colon)
 	case r == ']':
 		return l.emit(RightBrack)
@@ -307,6 +420,8 @@ func lexAny(l *Scanner) stateFn {
 		return l.emit(RightParen)
 	case r <= unicode.MaxASCII && unicode.IsPrint(r):
 		return l.emit(Char)
+	case r == utf8.RuneError && l.lastWidth == 1:
+		return l.errorf("invalid UTF-8 encoding")
 	default:
 		return l.errorf("unrecognized character: %#U", r)
 	}
@@ -319,7 +434,7 @@ func lexSpace(l *Scanner) stateFn {
 		l.next()
 	}
 	// Skips over the pending input.
-	l.start = l.pos
+	l.skip()
 	return lexAny
 }
 
@@ -355,14 +470,15 @@ func lexIdentifier(l *Scanner) stateFn {
 func lexOperator(l *Scanner) stateFn {
 	// It might be an inner product or reduction, but only if it is a binary operator.
 	word := l.input[l.start:l.pos]
-	if word == "o" || value.BinaryOps[word] != nil || l.context.UserDefined(word, true) {
+	if word == "o" || value.BinaryOps[value.OperatorName(word, false)] != nil || l.context.UserDefined(word, true) {
 		switch l.peek() {
 		case '/':
 			// Reduction.
 			l.next()
 		case '\\':
-			// Scan.
+			// Scan, or exclusive scan if doubled.
 			l.next()
+			l.accept("\\")
 		case '.':
 			// Inner or outer product?
 			l.next()               // Accept the '.'.
@@ -427,6 +543,33 @@ func lexComplex(l *Scanner) stateFn {
 	return l.emit(Number)
 }
 
+// lexBracedNumber scans an integer written as 0d{digits}, in which
+// the digits, in the input base, may be separated by spaces and
+// newlines, as when a long number is pasted. The 0d{ has been consumed.
+func lexBracedNumber(l *Scanner) stateFn {
+	digits := digitsForBase(l.context.Config().InputBase())
+	var b strings.Builder
+	for {
+		l.readOK = true // Here we do accept a newline mid-token.
+		switch r := l.next(); {
+		case r == '}':
+			if b.Len() == 0 {
+				return l.errorf("no digits in %s", l.input[l.start:l.pos])
+			}
+			l.emit(Number)
+			l.token.Text = b.String()
+			return nil
+		case isSpace(r) || r == '\n':
+		case strings.ContainsRune(digits, r):
+			b.WriteRune(r)
+		case r == eof:
+			return l.errorf("unterminated 0d{ number")
+		default:
+			return l.errorf("bad digit %#U in 0d{ number", r)
+		}
+	}
+}
+
 // acceptNumber scans a number: decimal, octal, hex, float. This
 // isn't a perfect number scanner - for instance it accepts "." and "0x0.2"
 // and "089" - but when it's wrong the input is invalid and the parser (via
@@ -442,6 +585,9 @@ func acceptNumber(l *Scanner, realPart bool) (bool, stateFn) {
 		// Might be a scan or reduction.
 		if r == '/' || r == '\\' {
 			l.next()
+			if r == '\\' {
+				l.accept("\\")
+			}
 			return false, l.emit(Operator)
 		}
 		if r != '.' && !l.isNumeral(r) {
@@ -479,21 +625,30 @@ func acceptNumber(l *Scanner, realPart bool) (bool, stateFn) {
 func (l *Scanner) scanNumber(followingSlashOK, followingJOK bool) bool {
 	base := l.context.Config().InputBase()
 	digits := digitsForBase(base)
-	// If base 0, acccept octal for 0 or hex for 0x or 0X.
-	if base == 0 {
-		if l.accept("0") && l.accept("xX") {
+	// If base 0, accept hex for 0x or 0X, octal for 0o or 0O, and binary for 0b or 0B.
+	if base == 0 && l.accept("0") {
+		switch {
+		case l.accept("xX"):
 			digits = digitsForBase(16)
+		case l.accept("oO"):
+			if !strings.ContainsRune("01234567_", l.peek()) {
+				// Not octal; perhaps an outer product, as in 0o.+2.
+				l.backup()
+				break
+			}
+			digits = digitsForBase(8)
+		case l.accept("bB"):
+			digits = digitsForBase(2)
 		}
-		// Otherwise leave it decimal (0); strconv.ParseInt will take care of it.
-		// We can't set it to 8 in case it's a leading-0 float like 0.69 or 09e4.
+		// Otherwise leave it decimal; leading zeros are ignored.
 	}
-	l.acceptRun(digits)
+	l.acceptDigits(digits)
 	if l.accept(".") {
-		l.acceptRun(digits)
+		l.acceptDigits(digits)
 	}
 	if l.accept("eE") {
 		l.accept("+-")
-		l.acceptRun("0123456789")
+		l.acceptDigits("0123456789")
 	}
 	r := l.peek()
 	if followingSlashOK && r == '/' {
@@ -555,6 +710,9 @@ func lexQuote(l *Scanner) stateFn {
 			}
 			fallthrough
 		case eof, '\n':
+			if quote == '\'' {
+				return l.errorf("unterminated character constant")
+			}
 			return l.errorf("unterminated quoted string")
 		case quote:
 			return l.emit(String)
@@ -586,6 +744,11 @@ func isEndOfLine(r rune) bool {
 	return r == '\n' || r == ';'
 }
 
+// IsIdentifier reports whether s is a valid name for a variable or op.
+func IsIdentifier(s string) bool {
+	return s != "" && isIdentifier(s)
+}
+
 // isIdentifier reports whether the slice is a valid identifier.
 func isIdentifier(s string) bool {
 	if len(s) == 1 && s[0] == '_' {
@@ -593,7 +756,7 @@ func isIdentifier(s string) bool {
 	}
 	first := true
 	for _, r := range s {
-		if unicode.IsDigit(r) {
+		if unicode.IsDigit(r) || isMark(r) {
 			if first {
 				return false
 			}
@@ -605,9 +768,17 @@ func isIdentifier(s string) bool {
 	return true
 }
 
-// isAlphaNumeric reports whether r is an alphabetic, digit, or underscore.
+// isAlphaNumeric reports whether r is an alphabetic, digit, or underscore,
+// or a combining mark, such as the accent in a decomposed é, which may
+// follow a letter in an identifier but not begin one.
 func isAlphaNumeric(r rune) bool {
-	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || isMark(r)
+}
+
+// isMark reports whether r is a combining mark, which modifies the
+// character before it.
+func isMark(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Mc)
 }
 
 // isDigit reports whether r is an ASCII digit.
@@ -662,42 +833,60 @@ func isAllDigits(s string, base int) bool {
 	return true
 }
 
-// isOperator reports whether r is an operator. It may advance the lexer one character
-// if it is a two-character operator.
+// isOperator reports whether r, which has just been read, begins the
+// name of a symbolic operator, one such as + or ** or ≤ that is not spelled
+// with letters. If so, it advances the lexer past the longest such name.
+// The names are those of the operators in the value package, including
+// any added by value.RegisterUnary and value.RegisterBinary, so new
+// operators need no change to the scanner.
 func (l *Scanner) isOperator(r rune) bool {
-	switch r {
-	case '?', '+', '-', '/', '%', '&', '|', '^', ',':
-		// No follow-on possible.
-	case '!':
-		if l.peek() == '=' {
-			l.next()
-		}
-	case '>':
-		switch l.peek() {
-		case '>', '=':
-			l.next()
-		}
-	case '<':
-		switch l.peek() {
-		case '<', '=':
-			l.next()
-		}
-	case '*':
-		switch l.peek() {
-		case '*':
-			l.next()
+	start := l.pos - l.lastWidth
+	end := -1
+	for pos := start; pos < len(l.input); {
+		r, w := utf8.DecodeRuneInString(l.input[pos:])
+		if !isSymbolic(r) {
+			break
 		}
-	case '=':
-		if l.peek() != '=' {
-			return false
+		pos += w
+		if isOperatorName(l.input[start:pos], l.context.Config().Glyphs()) {
+			end = pos
 		}
-		l.next()
-	default:
+	}
+	if end < 0 {
 		return false
 	}
+	for l.pos < end {
+		l.next()
+	}
 	return true
 }
 
+// isOperatorName reports whether s is the name of a built-in operator,
+// possibly spelled with APL symbols if glyphs is set, or of an operator
+// implemented by the parser.
+func isOperatorName(s string, glyphs bool) bool {
+	if parserOperators[s] || value.IsOperator(s) {
+		return true
+	}
+	return glyphs && (value.IsOperator(value.OperatorName(s, false)) || value.IsOperator(value.OperatorName(s, true)))
+}
+
+// parserOperators holds the operators that the parser implements itself,
+// because they do not always evaluate both operands.
+var parserOperators = map[string]bool{
+	"&&": true,
+	"||": true,
+}
+
+// isSymbolic reports whether r may appear in the name of a symbolic operator.
+func isSymbolic(r rune) bool {
+	switch r {
+	case eof, '(', ')', '[', ']', ';', ':', '#', '\'', '"', '`':
+		return false
+	}
+	return !isSpace(r) && !isEndOfLine(r) && !isAlphaNumeric(r)
+}
+
 // defined reports whether the argument has been defined as a variable or operator.
 func (l *Scanner) defined(word string) bool {
 	return exec.Predefined(word) || l.context.UserDefined(word, true)
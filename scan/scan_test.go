@@ -0,0 +1,244 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan_test
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"robpike.io/ivy/config"
+	"robpike.io/ivy/exec"
+	"robpike.io/ivy/scan"
+	"robpike.io/ivy/value"
+)
+
+func TestTokenPositions(t *testing.T) {
+	const input = "x = 3 + 4\n\n  'αβ' iota 10 # comment\ny\n"
+	type pos struct {
+		typ       scan.Type
+		text      string
+		line, col int
+		offset    int
+	}
+	want := []pos{
+		{scan.Identifier, "x", 1, 1, 0},
+		{scan.Assign, "=", 1, 3, 2},
+		{scan.Number, "3", 1, 5, 4},
+		{scan.Operator, "+", 1, 7, 6},
+		{scan.Number, "4", 1, 9, 8},
+		{scan.Newline, "\n", 1, 10, 9},
+		{scan.Newline, "\n", 2, 1, 10},
+		{scan.String, "'αβ'", 3, 3, 13},
+		{scan.Identifier, "iota", 3, 8, 20},
+		{scan.Number, "10", 3, 13, 25},
+		{scan.Newline, "\n", 3, 25, 37},
+		{scan.Identifier, "y", 4, 1, 38},
+		{scan.Newline, "\n", 4, 2, 39},
+		{scan.EOF, "EOF", 5, 1, 40},
+	}
+	context := exec.NewContext(new(config.Config))
+	scanner := scan.New(context, "input", bufio.NewReader(strings.NewReader(input)))
+	for i, w := range want {
+		tok := scanner.Next()
+		got := pos{tok.Type, tok.Text, tok.Line, tok.Column, tok.Offset}
+		if got != w {
+			t.Fatalf("token %d: got %+v, want %+v", i, got, w)
+		}
+		if tok.Type != scan.EOF && !strings.HasPrefix(input[tok.Offset:], tok.Text) {
+			t.Errorf("token %d: %q is not at offset %d", i, tok.Text, tok.Offset)
+		}
+	}
+}
+
+// TestContinuation checks that a backslash ending a line joins it to
+// the next, even inside a token, and that the tokens keep the positions
+// at which they appear in the input, as do those in a 0d{} number.
+func TestContinuation(t *testing.T) {
+	const input = "x = 12\\\n34 + \\\n y\n0d{1 2\n 3} z\n"
+	type pos struct {
+		typ       scan.Type
+		text      string
+		line, col int
+		offset    int
+	}
+	want := []pos{
+		{scan.Identifier, "x", 1, 1, 0},
+		{scan.Assign, "=", 1, 3, 2},
+		{scan.Number, "1234", 1, 5, 4},
+		{scan.Operator, "+", 2, 4, 11},
+		{scan.Identifier, "y", 3, 2, 16},
+		{scan.Newline, "\n", 3, 3, 17},
+		{scan.Number, "123", 4, 1, 18},
+		{scan.Identifier, "z", 5, 5, 29},
+		{scan.Newline, "\n", 5, 6, 30},
+		{scan.EOF, "EOF", 6, 1, 31},
+	}
+	context := exec.NewContext(new(config.Config))
+	scanner := scan.New(context, "input", bufio.NewReader(strings.NewReader(input)))
+	for i, w := range want {
+		tok := scanner.Next()
+		got := pos{tok.Type, tok.Text, tok.Line, tok.Column, tok.Offset}
+		if got != w {
+			t.Fatalf("token %d: got %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+// TestCommentContinuation checks that a backslash ending a comment
+// does not join the next line to the comment.
+func TestCommentContinuation(t *testing.T) {
+	const input = "x # C:\\\ny\n"
+	type pos struct {
+		typ       scan.Type
+		text      string
+		line, col int
+	}
+	want := []pos{
+		{scan.Identifier, "x", 1, 1},
+		{scan.Newline, "\n", 1, 8},
+		{scan.Identifier, "y", 2, 1},
+		{scan.Newline, "\n", 2, 2},
+		{scan.EOF, "EOF", 3, 1},
+	}
+	context := exec.NewContext(new(config.Config))
+	scanner := scan.New(context, "input", bufio.NewReader(strings.NewReader(input)))
+	for i, w := range want {
+		tok := scanner.Next()
+		got := pos{tok.Type, tok.Text, tok.Line, tok.Column}
+		if got != w {
+			t.Fatalf("token %d: got %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+// tokens returns the types and texts of the tokens of the single line s.
+func tokens(s string) string {
+	context := exec.NewContext(new(config.Config))
+	scanner := scan.New(context, "input", bufio.NewReader(strings.NewReader(s+"\n")))
+	var toks []string
+	for {
+		tok := scanner.Next()
+		if tok.Type == scan.Newline || tok.Type == scan.EOF {
+			return strings.Join(toks, " ")
+		}
+		toks = append(toks, tok.Type.String()+":"+tok.Text)
+	}
+}
+
+// TestOperatorMunch checks that symbolic operators are scanned as the
+// longest known operator, including operators registered by programs.
+func TestOperatorMunch(t *testing.T) {
+	value.RegisterBinary("<=>", func(c value.Context, u, v value.Value) value.Value {
+		return c.EvalUnary("sgn", c.EvalBinary(u, "-", v))
+	})
+	defer delete(value.BinaryOps, "<=>")
+	value.RegisterUnary("@", func(c value.Context, v value.Value) value.Value {
+		return c.EvalBinary(v, "*", v)
+	})
+	defer delete(value.UnaryOps, "@")
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"2**3", "Number:2 Operator:** Number:3"},
+		{"2*-3", "Number:2 Operator:* Number:-3"},
+		{"1<=2", "Number:1 Operator:<= Number:2"},
+		{"1<<2", "Number:1 Operator:<< Number:2"},
+		{"1<=>2", "Number:1 Operator:<=> Number:2"},
+		{"x<=>y", "Identifier:x Operator:<=> Identifier:y"},
+		{"<=>/ 1 2", "Operator:<=>/ Number:1 Number:2"},
+		{"+\\1 2", "Operator:+\\ Number:1 Number:2"},
+		{"*\\\\1 2", "Operator:*\\\\ Number:1 Number:2"},
+		{"max\\\\ 1 2", "Operator:max\\\\ Number:1 Number:2"},
+		{"1 2 +.<=> 3", "Number:1 Number:2 Operator:+.<=> Number:3"},
+		{"@3", "Operator:@ Number:3"},
+		{"@@3", "Operator:@ Operator:@ Number:3"},
+		{"1≤2", "Number:1 Operator:≤ Number:2"},
+		{"1&&0||1", "Number:1 Operator:&& Number:0 Operator:|| Number:1"},
+		{"1&0|1", "Number:1 Operator:& Number:0 Operator:| Number:1"},
+	}
+	for _, test := range tests {
+		if got := tokens(test.in); got != test.out {
+			t.Errorf("%q: got %s; want %s", test.in, got, test.out)
+		}
+	}
+}
+
+// TestUnicode checks that identifiers may hold letters, digits and
+// combining marks from any script, that multi-byte operators are
+// scanned whole, and that invalid UTF-8 is reported as such.
+func TestUnicode(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"π2 = 3", "Identifier:π2 Assign:= Number:3"},
+		{"naïve×δ", "Identifier:naïve Operator:× Identifier:δ"},
+		{"e\u0301x+1", "Identifier:e\u0301x Operator:+ Number:1"},
+		{"x١٢", "Identifier:x١٢"}, // Arabic-Indic digits.
+		{"2 3⍴⍳6", "Number:2 Number:3 Operator:⍴ Operator:⍳ Number:6"},
+		{"1 ∨.∧ 0", "Number:1 Operator:∨.∧ Number:0"},
+		{"×/⍳5", "Operator:×/ Operator:⍳ Number:5"},
+		{"\u0301x", "Error:unrecognized character: U+0301 '\u0301'"},
+		{"1 + \xff", "Number:1 Operator:+ Error:invalid UTF-8 encoding"},
+	}
+	for _, test := range tests {
+		if got := tokens(test.in); got != test.out {
+			t.Errorf("%q: got %s; want %s", test.in, got, test.out)
+		}
+	}
+}
+
+// allTokens returns every token from the scanner, through EOF.
+func allTokens(scanner *scan.Scanner) []scan.Token {
+	var toks []scan.Token
+	for {
+		tok := scanner.Next()
+		toks = append(toks, tok)
+		if tok.Type == scan.EOF {
+			return toks
+		}
+	}
+}
+
+// TestNewReader checks that a scanner reading a byte at a time, so
+// that every token is split across reads, sees the same tokens as
+// one reading from a string.
+func TestNewReader(t *testing.T) {
+	inputs := []string{
+		"x = 3 + 4\n\n  'αβ' iota 10 # comment\ny\n",
+		"op f x = x ** 2\nf 1e10 2j3 1/3\n",
+		"\"a raw\nstring\" , 'x'\n",
+		"x = 1 2 3\n+/ x",    // No final newline.
+		"'unterminated\n1\n", // Error mid-stream.
+		"",
+	}
+	for _, input := range inputs {
+		context := exec.NewContext(new(config.Config))
+		want := allTokens(scan.New(context, "input", strings.NewReader(input)))
+		got := allTokens(scan.NewReader(context, "input", iotest.OneByteReader(strings.NewReader(input))))
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%q:\ngot  %v\nwant %v", input, got, want)
+		}
+	}
+}
+
+// TestNewReaderEOF checks that input ending without a newline
+// ends the last statement.
+func TestNewReaderEOF(t *testing.T) {
+	context := exec.NewContext(new(config.Config))
+	scanner := scan.NewReader(context, "input", iotest.HalfReader(strings.NewReader("1 + 2\n3 + 4")))
+	var got []string
+	for _, tok := range allTokens(scanner) {
+		got = append(got, tok.Type.String())
+	}
+	want := "Number Operator Number Newline Number Operator Number EOF"
+	if strings.Join(got, " ") != want {
+		t.Errorf("got %s; want %s", strings.Join(got, " "), want)
+	}
+}
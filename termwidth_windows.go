@@ -0,0 +1,40 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func init() {
+	terminalWidth = consoleWidth
+}
+
+var getConsoleScreenBufferInfo = syscall.NewLazyDLL("kernel32.dll").NewProc("GetConsoleScreenBufferInfo")
+
+type coord struct {
+	x, y int16
+}
+
+type consoleScreenBufferInfo struct {
+	size              coord
+	cursorPosition    coord
+	attributes        uint16
+	left, top         int16 // The visible window.
+	right, bottom     int16
+	maximumWindowSize coord
+}
+
+// consoleWidth asks the console for the width of the window
+// showing standard output.
+func consoleWidth() int {
+	var info consoleScreenBufferInfo
+	ok, _, _ := getConsoleScreenBufferInfo.Call(uintptr(syscall.Stdout), uintptr(unsafe.Pointer(&info)))
+	if ok == 0 {
+		return 0
+	}
+	return int(info.right-info.left) + 1
+}
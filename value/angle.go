@@ -0,0 +1,63 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+// angleIn returns v, the argument of a trigonometric function in the
+// units set by ) angle, in radians.
+func angleIn(c Context, v Value) Value {
+	if !c.Config().Degrees() {
+		return v
+	}
+	switch v.(type) {
+	case BigFloat, Complex:
+	default:
+		// Reduce an exact real angle modulo 360, which is also exact,
+		// so even a huge angle is converted to the full precision.
+		v = c.EvalBinary(v, "mod", Int(360))
+	}
+	return toRadians(c, v)
+}
+
+// angleOut returns v, the result in radians of an inverse trigonometric
+// function, in the units set by ) angle.
+func angleOut(c Context, v Value) Value {
+	if !c.Config().Degrees() {
+		return v
+	}
+	return toDegrees(c, v)
+}
+
+// toRadians converts the angle v from degrees to radians.
+func toRadians(c Context, v Value) Value {
+	if u, ok := v.(Complex); ok {
+		return Complex{scaleAngle(c, u.real, true), scaleAngle(c, u.imag, true)}.shrink()
+	}
+	return scaleAngle(c, v, true)
+}
+
+// toDegrees converts the angle v from radians to degrees.
+func toDegrees(c Context, v Value) Value {
+	if u, ok := v.(Complex); ok {
+		return Complex{scaleAngle(c, u.real, false), scaleAngle(c, u.imag, false)}.shrink()
+	}
+	return scaleAngle(c, v, false)
+}
+
+// scaleAngle returns v, a real number, multiplied by pi/180 if toRad is
+// set, or otherwise by 180/pi. The result has the floating-point precision,
+// but an exact integer result, as of 0, is an integer.
+func scaleAngle(c Context, v Value, toRad bool) Value {
+	pi := consts(c.Config()).pi
+	x := newFloat(c).Set(floatSelf(c, v).Float)
+	d := newFloat(c).SetInt64(180)
+	if toRad {
+		x.Mul(x, pi)
+		x.Quo(x, d)
+	} else {
+		x.Mul(x, d)
+		x.Quo(x, pi)
+	}
+	return BigFloat{x}.shrink()
+}
@@ -54,11 +54,11 @@ func floatAsin(c Context, x *big.Float) *big.Float {
 	// atan implementation converges well for all values, so we use
 	// the formula above to compute asin. But be careful when |x|=1.
 	if x.Cmp(floatOne) == 0 {
-		z := newFloat(c).Set(floatPi)
+		z := newFloat(c).Set(consts(c.Config()).pi)
 		return z.Quo(z, floatTwo)
 	}
 	if x.Cmp(floatMinusOne) == 0 {
-		z := newFloat(c).Set(floatPi)
+		z := newFloat(c).Set(consts(c.Config()).pi)
 		z.Quo(z, floatTwo)
 		return z.Neg(z)
 	}
@@ -73,7 +73,7 @@ func floatAsin(c Context, x *big.Float) *big.Float {
 // floatAcos computes acos(x) as π/2 - asin(x).
 func floatAcos(c Context, x *big.Float) *big.Float {
 	// acos(x) = π/2 - asin(x)
-	z := newFloat(c).Set(floatPi)
+	z := newFloat(c).Set(consts(c.Config()).pi)
 	z.Quo(z, newFloat(c).SetInt64(2))
 	return z.Sub(z, floatAsin(c, x))
 }
@@ -107,7 +107,7 @@ func floatAtan(c Context, x *big.Float) *big.Float {
 	tmp.Sub(tmp, x)
 	tmp.Abs(tmp)
 	if tmp.Cmp(newFloat(c).SetFloat64(0.5)) < 0 {
-		z := newFloat(c).Set(floatPi)
+		z := newFloat(c).Set(consts(c.Config()).pi)
 		z.Quo(z, newFloat(c).SetInt64(8))
 		y := floatSqrt(c, floatTwo)
 		y.Sub(y, floatOne)
@@ -164,7 +164,7 @@ func floatAtanLarge(c Context, x *big.Float) *big.Float {
 	xN := newFloat(c).Set(x)
 	xSquared := newFloat(c).Set(x)
 	xSquared.Mul(x, x)
-	z := newFloat(c).Set(floatPi)
+	z := newFloat(c).Set(consts(c.Config()).pi)
 	z.Quo(z, floatTwo)
 
 	// n goes up by two each loop.
@@ -197,7 +197,7 @@ func complexAsin(c Context, v Complex) Complex {
 
 func complexAcos(c Context, v Complex) Value {
 	// Use the formula: acos(v) = π/2 - asin(v)
-	piBy2 := newComplex(BigFloat{newFloat(c).Set(floatPiBy2)}, BigFloat{floatZero})
+	piBy2 := newComplex(BigFloat{newFloat(c).Set(consts(c.Config()).piBy2)}, BigFloat{floatZero})
 	return piBy2.sub(c, complexAsin(c, v))
 }
 
@@ -34,6 +34,12 @@ func (f BigFloat) String() string {
 }
 
 func (f BigFloat) Sprint(conf *config.Config) string {
+	return decimalText(conf, f.text(conf))
+}
+
+// text returns the representation of f in the floating-point format
+// of the configuration, with a '.' decimal point.
+func (f BigFloat) text(conf *config.Config) string {
 	var mant big.Float
 	exp := f.Float.MantExp(&mant)
 	positive := 1
@@ -66,8 +72,8 @@ func (f BigFloat) Sprint(conf *config.Config) string {
 			eChar = 'E'
 		}
 		fexp := newF(conf).SetInt64(int64(exp))
-		fexp.Mul(fexp, floatLog2)
-		fexp.Quo(fexp, floatLog10)
+		fexp.Mul(fexp, consts(conf).log2)
+		fexp.Quo(fexp, consts(conf).log10)
 		// We now have a floating-point base 10 exponent.
 		// Break into the integer part and the fractional part.
 		// The integer part is what we will show.
@@ -76,7 +82,7 @@ func (f BigFloat) Sprint(conf *config.Config) string {
 		fraction := fexp.Sub(fexp, newF(conf).SetInt(iexp))
 		// Now compute 10**(fractional part).
 		// Fraction is in base 10. Move it to base e.
-		fraction.Mul(fraction, floatLog10)
+		fraction.Mul(fraction, consts(conf).log10)
 		scale := exponential(conf, fraction)
 		if positive > 0 {
 			mant.Mul(&mant, scale)
@@ -127,6 +133,12 @@ func (f BigFloat) ProgString() string {
 	panic("float.ProgString - cannot happen")
 }
 
+// Repr returns a conversion of the exact rational value of f.
+func (f BigFloat) Repr() string {
+	r, _ := f.Rat(nil)
+	return "(float " + BigRat{r}.ProgString() + ")"
+}
+
 func (f BigFloat) Eval(Context) Value {
 	return f
 }
@@ -52,7 +52,7 @@ func (i BigInt) Sprint(conf *config.Config) string {
 	if format != "" {
 		verb, prec, ok := conf.FloatFormat()
 		if ok {
-			return i.floatString(verb, prec)
+			return decimalText(conf, i.floatString(verb, prec))
 		}
 		return fmt.Sprintf(format, i.Int)
 	}
@@ -60,24 +60,21 @@ func (i BigInt) Sprint(conf *config.Config) string {
 	if i.BitLen() < intBits {
 		return Int(i.Int64()).Sprint(conf)
 	}
-	switch conf.OutputBase() {
-	case 0, 10:
-		return fmt.Sprintf("%d", i.Int)
-	case 2:
-		return fmt.Sprintf("%b", i.Int)
-	case 8:
-		return fmt.Sprintf("%o", i.Int)
-	case 16:
-		return fmt.Sprintf("%x", i.Int)
+	base := conf.OutputBase()
+	if base == 0 {
+		base = 10
 	}
-	Errorf("can't print number in base %d (yet)", conf.OutputBase())
-	return ""
+	return i.Text(base)
 }
 
 func (i BigInt) ProgString() string {
 	return fmt.Sprintf("%d", i.Int)
 }
 
+func (i BigInt) Repr() string {
+	return i.ProgString()
+}
+
 func (i BigInt) floatString(verb byte, prec int) string {
 	switch verb {
 	case 'f', 'F':
@@ -142,6 +139,8 @@ func (i BigInt) toType(op string, conf *config.Config, which valueType) Value {
 	switch which {
 	case bigIntType:
 		return i
+	case decimalType:
+		return Decimal{i.Int, 0}
 	case bigRatType:
 		r := big.NewRat(0, 1).SetInt(i.Int)
 		return BigRat{r}
@@ -47,7 +47,7 @@ func (r BigRat) Sprint(conf *config.Config) string {
 	if format != "" {
 		verb, prec, ok := conf.FloatFormat()
 		if ok {
-			return r.floatString(verb, prec)
+			return decimalText(conf, r.floatString(verb, prec, conf.RoundingMode()))
 		}
 		return fmt.Sprintf(conf.RatFormat(), r.Num(), r.Denom())
 	}
@@ -57,13 +57,22 @@ func (r BigRat) Sprint(conf *config.Config) string {
 }
 
 func (r BigRat) ProgString() string {
+	if r.IsInt() { // Possible in an unshrunk part of a complex number.
+		return r.Num().String()
+	}
 	return fmt.Sprintf("%s/%s", r.Num(), r.Denom())
 }
 
-func (r BigRat) floatString(verb byte, prec int) string {
+func (r BigRat) Repr() string {
+	return r.ProgString()
+}
+
+// floatString returns r in the floating-point format given by the verb
+// and precision, rounding the last digit as specified by mode.
+func (r BigRat) floatString(verb byte, prec int, mode config.RoundingMode) string {
 	switch verb {
 	case 'f', 'F':
-		return r.Rat.FloatString(prec)
+		return ratFloatString(r.Rat, prec, mode)
 	case 'e', 'E':
 		// The exponent will alway be >= 0.
 		sign := ""
@@ -76,14 +85,27 @@ func (r BigRat) floatString(verb byte, prec int) string {
 		t.Set(&x)
 		exp := ratExponent(&x)
 		ratScale(&t, exp)
-		str := t.FloatString(prec + 1) // +1 because first digit might be zero.
-		// Drop the decimal.
-		if str[0] == '0' {
-			str = str[2:]
+		// Make sure there is one digit before the decimal.
+		for t.Cmp(bigRatOne) < 0 {
+			t.Mul(&t, bigRatTen)
 			exp--
-		} else if len(str) > 1 && str[1] == '.' {
-			str = str[0:1] + str[2:]
 		}
+		for t.Cmp(bigRatTen) >= 0 {
+			t.Quo(&t, bigRatTen)
+			exp++
+		}
+		// Round with the sign restored, as it matters to some modes.
+		if sign != "" {
+			t.Neg(&t)
+		}
+		str := strings.TrimPrefix(ratFloatString(&t, prec, mode), "-")
+		if strings.HasPrefix(str, "10") {
+			// Rounding carried into a new digit, as in 9.99 to 10.0.
+			str = "1" + str[2:]
+			exp++
+		}
+		// Drop the decimal.
+		str = strings.Replace(str, ".", "", 1)
 		return eFormat(verb, prec, sign, str, exp)
 	case 'g', 'G':
 		var x big.Rat
@@ -93,13 +115,13 @@ func (r BigRat) floatString(verb byte, prec int) string {
 		if exp < -4 || prec <= exp {
 			// Use e format.
 			verb -= 2 // g becomes e.
-			return trimEZeros(verb, r.floatString(verb, prec-1))
+			return trimEZeros(verb, r.floatString(verb, prec-1, mode))
 		}
 		// Use f format.
 		// If it's got zeros right of the decimal, they count as digits in the precision.
 		// If it's got digits left of the decimal, they count as digits in the precision.
 		// Both are handled by adjusting prec by exp.
-		str := r.floatString(verb-1, prec-exp-1) // -1 for the one digit left of the decimal.
+		str := r.floatString(verb-1, prec-exp-1, mode) // -1 for the one digit left of the decimal.
 		// Trim trailing decimals.
 		point := strings.IndexByte(str, '.')
 		if point > 0 {
@@ -119,6 +141,70 @@ func (r BigRat) floatString(verb byte, prec int) string {
 	return ""
 }
 
+// ratFloatString is like x.FloatString(prec), which rounds halves away
+// from zero, but rounds the last digit as specified by mode.
+func ratFloatString(x *big.Rat, prec int, mode config.RoundingMode) string {
+	if prec < 0 {
+		prec = 0
+	}
+	digits := ratRound(x, prec, mode).String()
+	if len(digits) <= prec {
+		digits = zeros(prec-len(digits)+1) + digits
+	}
+	str := digits
+	if prec > 0 {
+		str = digits[:len(digits)-prec] + "." + digits[len(digits)-prec:]
+	}
+	if x.Sign() < 0 {
+		str = "-" + str
+	}
+	return str
+}
+
+// ratRound returns |x|*10**prec rounded to an integer as specified by mode,
+// which takes the sign of x into account. Prec must not be negative.
+func ratRound(x *big.Rat, prec int, mode config.RoundingMode) *big.Int {
+	// Divide |x|*10**prec into quotient and remainder; the quotient
+	// holds the digits before rounding.
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(prec)), nil)
+	num := new(big.Int).Abs(x.Num())
+	num.Mul(num, scale)
+	q, rem := num.QuoRem(num, x.Denom(), new(big.Int))
+	if rem.Sign() != 0 {
+		neg := x.Sign() < 0
+		half := rem.Lsh(rem, 1).Cmp(x.Denom()) // Compare the remainder to half a unit.
+		var up bool                            // Whether to round away from zero.
+		switch mode {
+		case config.RoundHalfAway:
+			up = half >= 0
+		case config.RoundHalfEven:
+			up = half > 0 || half == 0 && q.Bit(0) == 1
+		case config.RoundTowardZero:
+			up = false
+		case config.RoundUp:
+			up = !neg
+		case config.RoundDown:
+			up = neg
+		}
+		if up {
+			q.Add(q, bigIntOne.Int)
+		}
+	}
+	return q
+}
+
+// ratDivMod returns the Euclidean quotient and remainder of x and y:
+// q is an integer and r = x - q*y satisfies 0 <= r < |y|.
+// The caller must check that y is not zero.
+func ratDivMod(x, y *big.Rat) (q *big.Int, r *big.Rat) {
+	num := new(big.Int).Mul(x.Num(), y.Denom())
+	den := new(big.Int).Mul(x.Denom(), y.Num())
+	q = num.Div(num, den)
+	r = new(big.Rat).SetInt(q)
+	r.Mul(r, y)
+	return q, r.Sub(x, r)
+}
+
 // ratExponent returns the power of ten that x would display in scientific notation.
 func ratExponent(x *big.Rat) int {
 	if x.Sign() < 0 {
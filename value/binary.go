@@ -5,9 +5,7 @@
 package value
 
 import (
-	"math"
 	"math/big"
-	"sort"
 )
 
 // Binary operators.
@@ -39,7 +37,11 @@ func divType(t1, t2 valueType) (valueType, valueType) {
 }
 
 // rationalType promotes scalars to rationals so we can do rational division.
+// Decimals stay Decimals unless the other operand is a rational or wider.
 func rationalType(t1, t2 valueType) (valueType, valueType) {
+	if t, _ := binaryArithType(t1, t2); t == decimalType {
+		return t, t
+	}
 	if t1 < bigRatType {
 		t1 = bigRatType
 	}
@@ -54,11 +56,30 @@ func atLeastVectorType(t1, t2 valueType) (valueType, valueType) {
 	return matrixType, matrixType
 }
 
+// indexOfType is like atLeastVectorType, but leaves a scalar right operand
+// of a vector alone, so A iota B is a scalar for a scalar B but a vector,
+// even of one element, for a vector B.
+func indexOfType(t1, t2 valueType) (valueType, valueType) {
+	if t1 < matrixType && t2 < vectorType {
+		return vectorType, t2
+	}
+	return atLeastVectorType(t1, t2)
+}
+
 // vectorAndMatrixType promotes the left arg to vector and the right arg to matrix.
 func vectorAndMatrixType(t1, t2 valueType) (valueType, valueType) {
 	return vectorType, matrixType
 }
 
+// matrixAndAtLeastVectorType promotes the left arg to matrix
+// and the right arg to at least vector.
+func matrixAndAtLeastVectorType(t1, t2 valueType) (valueType, valueType) {
+	if t2 < vectorType {
+		t2 = vectorType
+	}
+	return matrixType, t2
+}
+
 // vectorAndAtLeastVectorType promotes the left arg to vector
 // and the right arg to at least vector.
 func vectorAndAtLeastVectorType(t1, t2 valueType) (valueType, valueType) {
@@ -68,26 +89,338 @@ func vectorAndAtLeastVectorType(t1, t2 valueType) (valueType, valueType) {
 	return vectorType, t2
 }
 
-// shiftCount converts x to an unsigned integer.
-func shiftCount(x Value) uint {
-	switch count := x.(type) {
-	case Int:
-		if count < 0 || count >= maxInt {
-			Errorf("illegal shift count %d", count)
-		}
-		return uint(count)
+// shiftInt returns x, the operand of a shift, as a big.Int. It may be of
+// any real type but must have an integral value; what names the operand
+// in the error message if it does not.
+func shiftInt(c Context, op, what string, x Value) *big.Int {
+	switch x := x.(type) {
 	case BigInt:
-		// Must be small enough for an int; that will happen if
-		// the LHS is a BigInt because the RHS will have been lifted.
-		reduced := count.shrink()
-		if _, ok := reduced.(Int); ok {
-			return shiftCount(reduced)
+		return x.Int
+	case Decimal:
+		return shiftInt(c, op, what, x.rat())
+	case BigRat:
+		if x.IsInt() {
+			return x.Num()
+		}
+	case BigFloat:
+		if x.IsInt() {
+			i, _ := x.Int(nil)
+			return i
 		}
 	}
-	Errorf("illegal shift count type")
+	Errorf("%s: %s %s is not an integer", op, what, x.Sprint(c.Config()))
 	panic("not reached")
 }
 
+// shift returns u shifted left by v bits, or right if op is ">>".
+// The operands must be integers, possibly of another type such as 2.0.
+// The count must be non-negative and, for a left shift, the result
+// must fit in )maxbits, which is checked before the result is built.
+func shift(c Context, op string, u, v Value) Value {
+	i := shiftInt(c, op, "value", u)
+	count := shiftInt(c, op, "count", v)
+	if count.Sign() < 0 {
+		Errorf("%s: illegal shift count %s", op, v.Sprint(c.Config()))
+	}
+	z := bigInt64(0)
+	if op == ">>" {
+		if width := c.Config().LogicalShift(); width > 0 {
+			i = wordBits(c, op, i, width)
+		}
+		// Beyond the length of i, every count gives the same result.
+		n := uint(i.BitLen() + 1)
+		if count.Cmp(big.NewInt(int64(n))) < 0 {
+			n = uint(count.Uint64())
+		}
+		z.Rsh(i, n)
+		return z.shrink()
+	}
+	if i.Sign() == 0 {
+		return Int(0)
+	}
+	if !count.IsInt64() || count.Int64() >= maxInt {
+		Errorf("%s: shift count %s too large", op, v.Sprint(c.Config()))
+	}
+	if max := c.Config().MaxShift(); max != 0 && count.Uint64() > uint64(max) {
+		Errorf("%s: shift count %s exceeds )maxshift %d", op, v.Sprint(c.Config()), max)
+	}
+	n := count.Int64()
+	mustFit(c.Config(), int64(i.BitLen())+n)
+	z.Lsh(i, uint(n))
+	return z.shrink()
+}
+
+// wordBits returns the bits of i as an unsigned word of the given width,
+// so a negative i becomes its two's-complement representation. It is an
+// error if i does not fit in the word, whether signed or unsigned.
+func wordBits(c Context, op string, i *big.Int, width uint) *big.Int {
+	max := new(big.Int).Lsh(bigIntOne.Int, width)
+	if i.Sign() >= 0 {
+		if i.Cmp(max) >= 0 {
+			Errorf("%s: %s does not fit in )logicalshift %d bits", op, i, width)
+		}
+		return i
+	}
+	min := new(big.Int).Rsh(max, 1)
+	if i.CmpAbs(min) > 0 {
+		Errorf("%s: %s does not fit in )logicalshift %d bits", op, i, width)
+	}
+	return max.Add(max, i)
+}
+
+// bitIndex returns v, the index of a bit, as a non-negative big.Int.
+// Index )origin is the least significant bit.
+func bitIndex(c Context, op string, v Value) *big.Int {
+	k := new(big.Int).Sub(shiftInt(c, op, "index", v), big.NewInt(int64(c.Config().Origin())))
+	if k.Sign() < 0 {
+		Errorf("%s: illegal bit index %s", op, v.Sprint(c.Config()))
+	}
+	return k
+}
+
+// bit returns bit v of u, 0 or 1. As with math/big, a negative u is
+// in two's complement with infinitely many leading ones, so indexes
+// beyond the length of u yield its sign bit.
+func bit(c Context, u, v Value) Value {
+	i := shiftInt(c, "bit", "value", u)
+	k := bitIndex(c, "bit", v)
+	if k.Cmp(big.NewInt(int64(i.BitLen()))) >= 0 {
+		if i.Sign() < 0 {
+			return Int(1)
+		}
+		return Int(0)
+	}
+	return Int(i.Bit(int(k.Int64())))
+}
+
+// setBit returns u with bit v set to b, which is 0 or 1.
+func setBit(c Context, op string, u, v Value, b uint) Value {
+	i := shiftInt(c, op, "value", u)
+	k := bitIndex(c, op, v)
+	if !k.IsInt64() || k.Int64() >= maxInt {
+		Errorf("%s: bit index %s too large", op, v.Sprint(c.Config()))
+	}
+	n := k.Int64()
+	mustFit(c.Config(), n+1)
+	z := bigInt64(0)
+	z.SetBit(i, int(n), b)
+	return z.shrink()
+}
+
+// rotate implements rotl and rotr. The left operand is a word width
+// followed by one or more values, and the right operand is the count,
+// or one count for each value. Each value is truncated to the width,
+// as a two's-complement word if negative, and its bits are rotated
+// left, for rotl, or right by the count modulo the width.
+func rotate(c Context, op string, u, v Value) Value {
+	words, ok := u.(Vector)
+	if !ok || len(words) < 2 {
+		Errorf("%s: left operand must be a width followed by values", op)
+	}
+	width := wordWidth(c, op, words[0])
+	w := big.NewInt(width)
+	values := words[1:]
+	counts, ok := v.(Vector)
+	if !ok {
+		if _, ok := v.(*Matrix); ok {
+			Errorf("%s: count must be a scalar or vector", op)
+		}
+		counts = Vector{v}
+	}
+	n := len(values)
+	if len(counts) != n && len(counts) != 1 {
+		if n != 1 {
+			Errorf("%s: length mismatch: %d values, %d counts", op, len(values), len(counts))
+		}
+		n = len(counts)
+	}
+	mod := new(big.Int).Lsh(bigIntOne.Int, uint(width))
+	mask := new(big.Int).Sub(mod, bigIntOne.Int)
+	result := make(Vector, n)
+	for i := range result {
+		x := new(big.Int).Mod(wordInt(c, op, "value", values[i%len(values)]), mod)
+		k := new(big.Int).Mod(wordInt(c, op, "count", counts[i%len(counts)]), w).Int64()
+		if op == "rotr" && k != 0 {
+			k = width - k
+		}
+		z := new(big.Int).Lsh(x, uint(k))
+		z.Or(z, x.Rsh(x, uint(width-k)))
+		result[i] = BigInt{z.And(z, mask)}.shrink()
+	}
+	if len(result) == 1 {
+		return result[0]
+	}
+	return NewVector(result)
+}
+
+// wordWidth returns x, the width in bits of a word for op, which must be
+// positive and within )maxbits.
+func wordWidth(c Context, op string, x Value) int64 {
+	w := wordInt(c, op, "width", x)
+	if w.Sign() <= 0 || !w.IsInt64() || w.Int64() >= maxInt {
+		Errorf("%s: illegal width %s", op, x.Sprint(c.Config()))
+	}
+	mustFit(c.Config(), w.Int64())
+	return w.Int64()
+}
+
+// wordInt returns the integer x, an operand of an operator on
+// fixed-width words such as rotl, as a big.Int.
+func wordInt(c Context, op, what string, x Value) *big.Int {
+	switch y := x.(type) {
+	case Bool:
+		return big.NewInt(int64(y.toInt()))
+	case Int:
+		return big.NewInt(int64(y))
+	case Complex:
+		if y.isReal() {
+			return wordInt(c, op, what, y.real)
+		}
+	case Char, Vector, *Matrix:
+		Errorf("%s: %s must be an integer, not %s", op, what, whichType(x))
+	}
+	return shiftInt(c, op, what, x)
+}
+
+// fixedArith implements the fixed-width arithmetic operators: wadd, wsub
+// and wmul, which wrap around, and sadd, ssub and smul, which saturate.
+// The left operand is the width of the word, for signed arithmetic, or
+// the width followed by 1 for signed or 0 for unsigned. The right
+// operand is the pair of integers to operate on. The operation is done
+// exactly and the result then wrapped or clamped to the range of the word.
+func fixedArith(c Context, op string, u, v Value) Value {
+	word := u.(Vector)
+	if len(word) != 1 && len(word) != 2 {
+		Errorf("%s: left operand must be a width, optionally followed by signedness", op)
+	}
+	width := uint(wordWidth(c, op, word[0]))
+	signed := true
+	if len(word) == 2 {
+		switch s := wordInt(c, op, "signedness", word[1]); {
+		case s.Sign() == 0:
+			signed = false
+		case s.Cmp(bigIntOne.Int) != 0:
+			Errorf("%s: signedness must be 0 or 1", op)
+		}
+	}
+	pair := v.(Vector)
+	if len(pair) != 2 {
+		Errorf("%s: right operand must be a pair of integers", op)
+	}
+	x := wordInt(c, op, "operand", pair[0])
+	y := wordInt(c, op, "operand", pair[1])
+	z := new(big.Int)
+	switch op[1:] {
+	case "add":
+		z.Add(x, y)
+	case "sub":
+		z.Sub(x, y)
+	case "mul":
+		z.Mul(x, y)
+	}
+	// The word holds the values from min to max.
+	mod := new(big.Int).Lsh(bigIntOne.Int, width)
+	min := new(big.Int)
+	if signed {
+		min.Neg(new(big.Int).Rsh(mod, 1))
+	}
+	max := new(big.Int).Add(min, mod)
+	max.Sub(max, bigIntOne.Int)
+	if op[0] == 's' {
+		switch {
+		case z.Cmp(min) < 0:
+			z.Set(min)
+		case z.Cmp(max) > 0:
+			z.Set(max)
+		}
+		return BigInt{z}.shrink()
+	}
+	z.Sub(z, min)
+	z.Mod(z, mod)
+	z.Add(z, min)
+	return BigInt{z}.shrink()
+}
+
+// promoteTypes maps the names accepted by promote to types.
+var promoteTypes = map[string]valueType{
+	"int":     bigIntType,
+	"rat":     bigRatType,
+	"decimal": decimalType,
+	"float":   bigFloatType,
+	"complex": complexType,
+}
+
+// promote returns v with every element converted to the type named by u,
+// so a vector that mixes types becomes homogeneous. Conversion to an
+// integer or rational must be exact; conversion to decimal follows the
+// rules of )scale.
+func promote(c Context, u, v Value) Value {
+	var which valueType
+	name, ok := u.(Vector)
+	if ok && name.AllChars() {
+		which, ok = promoteTypes[name.Sprint(debugConf)]
+	}
+	if !ok {
+		Errorf("promote: left operand must be int, rat, decimal, float or complex, not %s", u.Sprint(c.Config()))
+	}
+	switch v := v.(type) {
+	case Vector:
+		result := make(Vector, len(v))
+		for i, x := range v {
+			result[i] = promoteScalar(c, which, x)
+		}
+		return result
+	case *Matrix:
+		data := make(Vector, len(v.data))
+		for i, x := range v.data {
+			data[i] = promoteScalar(c, which, x)
+		}
+		return NewMatrix(v.shape, data)
+	}
+	return promoteScalar(c, which, v)
+}
+
+// promoteScalar converts x to the type which, which is bigIntType
+// for any integer. The result is not shrunk.
+func promoteScalar(c Context, which valueType, x Value) Value {
+	conf := c.Config()
+	switch y := x.(type) {
+	case Bool:
+		x = y.toInt()
+	case Complex:
+		if which != complexType && y.isReal() {
+			x = y.real
+		}
+	case BigFloat:
+		if which < bigFloatType {
+			r, _ := y.Rat(nil) // Exact, as floats are finite.
+			x = BigRat{r}
+		}
+	case Decimal:
+		if which == bigIntType {
+			x = y.rat()
+		}
+	}
+	switch which {
+	case bigIntType:
+		switch y := x.(type) {
+		case Int:
+			return y
+		case BigInt:
+			return y
+		case BigRat:
+			if y.IsInt() {
+				return BigInt{new(big.Int).Set(y.Num())}.shrink()
+			}
+			Errorf("promote: %s is not an integer", y.Sprint(conf))
+		}
+		Errorf("promote: cannot convert %s to int", whichType(x))
+	case decimalType:
+		return toDecimal(conf, x)
+	}
+	return x.toType("promote", conf, which)
+}
+
 func binaryBigIntOp(u Value, op func(*big.Int, *big.Int, *big.Int) *big.Int, v Value) Value {
 	i, j := u.(BigInt), v.(BigInt)
 	z := bigInt64(0)
@@ -160,12 +493,16 @@ func toInt(t bool) Value {
 // toBool turns the Value into a Go bool.
 func toBool(t Value) bool {
 	switch t := t.(type) {
+	case Bool:
+		return bool(t)
 	case Int:
 		return t != 0
 	case Char:
 		return t != 0
 	case BigInt:
 		return t.Sign() != 0
+	case Decimal:
+		return t.Sign() != 0
 	case BigRat:
 		return t.Sign() != 0
 	case BigFloat:
@@ -178,17 +515,17 @@ func toBool(t Value) bool {
 }
 
 // andBool is like toBool but handles vectors by and'ing the values together.
-// The results are known to be Ints, as they come from comparison operations.
+// The results are known to be Ints or Bools, as they come from comparison operations.
 func andBool(t Value) bool {
 	if v, ok := t.(Vector); ok {
 		for _, x := range v {
-			if x == Int(0) {
+			if !toBool(x) {
 				return false
 			}
 		}
 		return true
 	}
-	return t.(Int) == one
+	return toBool(t)
 }
 
 var BinaryOps = make(map[string]BinaryOp)
@@ -217,6 +554,7 @@ func init() {
 
 		{
 			name:        "+",
+			identity:    Int(0),
 			elementwise: true,
 			whichType:   binaryArithType,
 			fn: [numType]binaryFn{
@@ -228,6 +566,9 @@ func init() {
 					mustFit(c.Config(), v.(BigInt).BitLen()+1)
 					return binaryBigIntOp(u, (*big.Int).Add, v)
 				},
+				decimalType: func(c Context, u, v Value) Value {
+					return u.(Decimal).add(v.(Decimal))
+				},
 				bigRatType: func(c Context, u, v Value) Value {
 					return binaryBigRatOp(u, (*big.Rat).Add, v)
 				},
@@ -242,6 +583,7 @@ func init() {
 
 		{
 			name:        "-",
+			identity:    Int(0),
 			elementwise: true,
 			whichType:   binaryArithType,
 			fn: [numType]binaryFn{
@@ -253,6 +595,9 @@ func init() {
 					mustFit(c.Config(), v.(BigInt).BitLen()+1)
 					return binaryBigIntOp(u, (*big.Int).Sub, v)
 				},
+				decimalType: func(c Context, u, v Value) Value {
+					return u.(Decimal).sub(v.(Decimal))
+				},
 				bigRatType: func(c Context, u, v Value) Value {
 					return binaryBigRatOp(u, (*big.Rat).Sub, v)
 				},
@@ -267,6 +612,7 @@ func init() {
 
 		{
 			name:        "*",
+			identity:    Int(1),
 			elementwise: true,
 			whichType:   binaryArithType,
 			fn: [numType]binaryFn{
@@ -277,6 +623,11 @@ func init() {
 					mustFit(c.Config(), u.(BigInt).BitLen()+v.(BigInt).BitLen())
 					return binaryBigIntOp(u, (*big.Int).Mul, v)
 				},
+				decimalType: func(c Context, u, v Value) Value {
+					x, y := u.(Decimal), v.(Decimal)
+					mustFit(c.Config(), int64(x.mant.BitLen()+y.mant.BitLen()))
+					return x.mul(y)
+				},
 				bigRatType: func(c Context, u, v Value) Value {
 					return binaryBigRatOp(u, (*big.Rat).Mul, v)
 				},
@@ -291,9 +642,13 @@ func init() {
 
 		{ // Rational division.
 			name:        "/",
+			identity:    Int(1),
 			elementwise: true,
 			whichType:   rationalType, // Use BigRats to avoid the analysis here.
 			fn: [numType]binaryFn{
+				decimalType: func(c Context, u, v Value) Value {
+					return u.(Decimal).quo(c.Config(), v.(Decimal))
+				},
 				bigRatType: func(c Context, u, v Value) Value {
 					if v.(BigRat).Sign() == 0 {
 						Errorf("division by zero")
@@ -366,7 +721,20 @@ func init() {
 					}
 					return binaryBigIntOp(u, (*big.Int).Div, v) // Euclidean division.
 				},
-				bigRatType:   nil, // Not defined for rationals. Use div.
+				decimalType: func(c Context, u, v Value) Value {
+					if v.(Decimal).Sign() == 0 {
+						Errorf("division by zero")
+					}
+					q, _ := u.(Decimal).divMod(v.(Decimal))
+					return BigInt{q}.shrink()
+				},
+				bigRatType: func(c Context, u, v Value) Value {
+					if v.(BigRat).Sign() == 0 {
+						Errorf("division by zero")
+					}
+					q, _ := ratDivMod(u.(BigRat).Rat, v.(BigRat).Rat)
+					return BigInt{q}.shrink()
+				},
 				bigFloatType: nil,
 				complexType:  nil,
 			},
@@ -383,7 +751,20 @@ func init() {
 					}
 					return binaryBigIntOp(u, (*big.Int).Mod, v) // Euclidan modulo.
 				},
-				bigRatType:   nil, // Not defined for rationals. Use mod.
+				decimalType: func(c Context, u, v Value) Value {
+					if v.(Decimal).Sign() == 0 {
+						Errorf("modulo by zero")
+					}
+					_, r := u.(Decimal).divMod(v.(Decimal))
+					return r
+				},
+				bigRatType: func(c Context, u, v Value) Value {
+					if v.(BigRat).Sign() == 0 {
+						Errorf("modulo by zero")
+					}
+					_, r := ratDivMod(u.(BigRat).Rat, v.(BigRat).Rat)
+					return BigRat{r}.shrink()
+				},
 				bigFloatType: nil,
 				complexType:  nil,
 			},
@@ -464,6 +845,35 @@ func init() {
 			},
 		},
 
+		{
+			name:        "sqrtn",
+			elementwise: true,
+			whichType:   binaryArithType,
+			fn: [numType]binaryFn{
+				intType:      sqrtn,
+				bigIntType:   sqrtn,
+				decimalType:  sqrtn,
+				bigRatType:   sqrtn,
+				bigFloatType: sqrtn,
+				complexType:  sqrtn,
+			},
+		},
+
+		{
+			name:        "roundto",
+			elementwise: true,
+			whichType:   rationalType,
+			fn: [numType]binaryFn{
+				bigRatType: func(c Context, u, v Value) Value {
+					return BigRat{roundTo(u.(BigRat).Rat, v.(BigRat).Rat)}.shrink()
+				},
+				bigFloatType: func(c Context, u, v Value) Value {
+					r := roundTo(floatRat(c, "roundto", u), floatRat(c, "roundto", v))
+					return BigFloat{newFloat(c).SetRat(r)}.shrink()
+				},
+			},
+		},
+
 		{
 			name:        "!",
 			elementwise: true,
@@ -504,6 +914,7 @@ func init() {
 
 		{
 			name:        "|",
+			identity:    Int(0),
 			elementwise: true,
 			whichType:   binaryArithType,
 			fn: [numType]binaryFn{
@@ -518,6 +929,7 @@ func init() {
 
 		{
 			name:        "^",
+			identity:    Int(0),
 			elementwise: true,
 			whichType:   binaryArithType,
 			fn: [numType]binaryFn{
@@ -536,12 +948,17 @@ func init() {
 			whichType:   divType, // Shifts are like exp: let BigInt do the work.
 			fn: [numType]binaryFn{
 				bigIntType: func(c Context, u, v Value) Value {
-					i, j := u.(BigInt), v.(BigInt)
-					z := bigInt64(0)
-					z.Lsh(i.Int, shiftCount(j))
-					return z.shrink()
+					return shift(c, "<<", u, v)
+				},
+				decimalType: func(c Context, u, v Value) Value {
+					return shift(c, "<<", u, v)
+				},
+				bigRatType: func(c Context, u, v Value) Value {
+					return shift(c, "<<", u, v)
+				},
+				bigFloatType: func(c Context, u, v Value) Value {
+					return shift(c, "<<", u, v)
 				},
-				// TODO: lsh for bigfloat
 			},
 		},
 
@@ -551,42 +968,181 @@ func init() {
 			whichType:   divType, // Shifts are like exp: let BigInt do the work.
 			fn: [numType]binaryFn{
 				bigIntType: func(c Context, u, v Value) Value {
-					i, j := u.(BigInt), v.(BigInt)
-					z := bigInt64(0)
-					z.Rsh(i.Int, shiftCount(j))
-					return z.shrink()
+					return shift(c, ">>", u, v)
+				},
+				decimalType: func(c Context, u, v Value) Value {
+					return shift(c, ">>", u, v)
+				},
+				bigRatType: func(c Context, u, v Value) Value {
+					return shift(c, ">>", u, v)
+				},
+				bigFloatType: func(c Context, u, v Value) Value {
+					return shift(c, ">>", u, v)
 				},
-				// TODO: rsh for bigfloat
 			},
 		},
 
 		{
-			name:        "==",
+			name:        "bit",
 			elementwise: true,
-			whichType:   binaryArithType,
+			whichType:   divType,
 			fn: [numType]binaryFn{
-				intType: func(c Context, u, v Value) Value {
-					return toInt(u.(Int) == v.(Int))
+				bigIntType: func(c Context, u, v Value) Value {
+					return bit(c, u, v)
 				},
-				charType: func(c Context, u, v Value) Value {
-					return toInt(u.(Char) == v.(Char))
+				decimalType: func(c Context, u, v Value) Value {
+					return bit(c, u, v)
+				},
+				bigRatType: func(c Context, u, v Value) Value {
+					return bit(c, u, v)
+				},
+				bigFloatType: func(c Context, u, v Value) Value {
+					return bit(c, u, v)
+				},
+			},
+		},
+
+		{
+			name:        "setbit",
+			elementwise: true,
+			whichType:   divType,
+			fn: [numType]binaryFn{
+				bigIntType: func(c Context, u, v Value) Value {
+					return setBit(c, "setbit", u, v, 1)
+				},
+				decimalType: func(c Context, u, v Value) Value {
+					return setBit(c, "setbit", u, v, 1)
 				},
+				bigRatType: func(c Context, u, v Value) Value {
+					return setBit(c, "setbit", u, v, 1)
+				},
+				bigFloatType: func(c Context, u, v Value) Value {
+					return setBit(c, "setbit", u, v, 1)
+				},
+			},
+		},
+
+		{
+			name:        "clearbit",
+			elementwise: true,
+			whichType:   divType,
+			fn: [numType]binaryFn{
 				bigIntType: func(c Context, u, v Value) Value {
-					i, j := u.(BigInt), v.(BigInt)
-					return toInt(i.Cmp(j.Int) == 0)
+					return setBit(c, "clearbit", u, v, 0)
+				},
+				decimalType: func(c Context, u, v Value) Value {
+					return setBit(c, "clearbit", u, v, 0)
 				},
 				bigRatType: func(c Context, u, v Value) Value {
-					i, j := u.(BigRat), v.(BigRat)
-					return toInt(i.Cmp(j.Rat) == 0)
+					return setBit(c, "clearbit", u, v, 0)
 				},
+				bigFloatType: func(c Context, u, v Value) Value {
+					return setBit(c, "clearbit", u, v, 0)
+				},
+			},
+		},
+
+		{
+			name: "rotl",
+			fn: [numType]binaryFn{
+				func(c Context, u, v Value) Value {
+					return rotate(c, "rotl", u, v)
+				},
+			},
+		},
+
+		{
+			name: "rotr",
+			fn: [numType]binaryFn{
+				func(c Context, u, v Value) Value {
+					return rotate(c, "rotr", u, v)
+				},
+			},
+		},
+
+		{
+			name:      "wadd",
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return fixedArith(c, "wadd", u, v)
+				},
+			},
+		},
+
+		{
+			name:      "wsub",
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return fixedArith(c, "wsub", u, v)
+				},
+			},
+		},
+
+		{
+			name:      "wmul",
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return fixedArith(c, "wmul", u, v)
+				},
+			},
+		},
+
+		{
+			name:      "sadd",
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return fixedArith(c, "sadd", u, v)
+				},
+			},
+		},
+
+		{
+			name:      "ssub",
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return fixedArith(c, "ssub", u, v)
+				},
+			},
+		},
+
+		{
+			name:      "smul",
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return fixedArith(c, "smul", u, v)
+				},
+			},
+		},
+
+		{
+			name:        "==",
+			elementwise: true,
+			whichType:   compareType,
+			fn: [numType]binaryFn{
+				boolType: func(c Context, u, v Value) Value {
+					return boolValue(c, u.(Bool) == v.(Bool))
+				},
+				intType: compareFn(func(cmp int) bool { return cmp == 0 }),
+				charType: func(c Context, u, v Value) Value {
+					return boolValue(c, u.(Char) == v.(Char))
+				},
+				bigIntType:  compareFn(func(cmp int) bool { return cmp == 0 }),
+				decimalType: compareFn(func(cmp int) bool { return cmp == 0 }),
+				bigRatType:  compareFn(func(cmp int) bool { return cmp == 0 }),
 				bigFloatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigFloat), v.(BigFloat)
-					return toInt(i.Cmp(j.Float) == 0)
+					return boolValue(c, i.Cmp(j.Float) == 0)
 				},
 				complexType: func(c Context, u, v Value) Value {
 					i, j := u.(Complex), v.(Complex)
-					if c.EvalBinary(i.real, "==", j.real) == Int(0) {
-						return Int(0)
+					if eq := c.EvalBinary(i.real, "==", j.real); !toBool(eq) {
+						return eq
 					}
 					return c.EvalBinary(i.imag, "==", j.imag)
 				},
@@ -596,30 +1152,26 @@ func init() {
 		{
 			name:        "!=",
 			elementwise: true,
-			whichType:   binaryArithType,
+			whichType:   compareType,
 			fn: [numType]binaryFn{
-				intType: func(c Context, u, v Value) Value {
-					return toInt(u.(Int) != v.(Int))
+				boolType: func(c Context, u, v Value) Value {
+					return boolValue(c, u.(Bool) != v.(Bool))
 				},
+				intType: compareFn(func(cmp int) bool { return cmp != 0 }),
 				charType: func(c Context, u, v Value) Value {
-					return toInt(u.(Char) != v.(Char))
-				},
-				bigIntType: func(c Context, u, v Value) Value {
-					i, j := u.(BigInt), v.(BigInt)
-					return toInt(i.Cmp(j.Int) != 0)
-				},
-				bigRatType: func(c Context, u, v Value) Value {
-					i, j := u.(BigRat), v.(BigRat)
-					return toInt(i.Cmp(j.Rat) != 0)
+					return boolValue(c, u.(Char) != v.(Char))
 				},
+				bigIntType:  compareFn(func(cmp int) bool { return cmp != 0 }),
+				decimalType: compareFn(func(cmp int) bool { return cmp != 0 }),
+				bigRatType:  compareFn(func(cmp int) bool { return cmp != 0 }),
 				bigFloatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigFloat), v.(BigFloat)
-					return toInt(i.Cmp(j.Float) != 0)
+					return boolValue(c, i.Cmp(j.Float) != 0)
 				},
 				complexType: func(c Context, u, v Value) Value {
 					i, j := u.(Complex), v.(Complex)
-					if c.EvalBinary(i.real, "!=", j.real) == Int(1) {
-						return Int(1)
+					if ne := c.EvalBinary(i.real, "!=", j.real); toBool(ne) {
+						return ne
 					}
 					return c.EvalBinary(i.imag, "!=", j.imag)
 				},
@@ -629,25 +1181,18 @@ func init() {
 		{
 			name:        "<",
 			elementwise: true,
-			whichType:   binaryArithType,
+			whichType:   compareType,
 			fn: [numType]binaryFn{
-				intType: func(c Context, u, v Value) Value {
-					return toInt(u.(Int) < v.(Int))
-				},
+				intType: compareFn(func(cmp int) bool { return cmp < 0 }),
 				charType: func(c Context, u, v Value) Value {
-					return toInt(u.(Char) < v.(Char))
-				},
-				bigIntType: func(c Context, u, v Value) Value {
-					i, j := u.(BigInt), v.(BigInt)
-					return toInt(i.Cmp(j.Int) < 0)
-				},
-				bigRatType: func(c Context, u, v Value) Value {
-					i, j := u.(BigRat), v.(BigRat)
-					return toInt(i.Cmp(j.Rat) < 0)
+					return boolValue(c, u.(Char) < v.(Char))
 				},
+				bigIntType:  compareFn(func(cmp int) bool { return cmp < 0 }),
+				decimalType: compareFn(func(cmp int) bool { return cmp < 0 }),
+				bigRatType:  compareFn(func(cmp int) bool { return cmp < 0 }),
 				bigFloatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigFloat), v.(BigFloat)
-					return toInt(i.Cmp(j.Float) < 0)
+					return boolValue(c, i.Cmp(j.Float) < 0)
 				},
 			},
 		},
@@ -655,25 +1200,18 @@ func init() {
 		{
 			name:        "<=",
 			elementwise: true,
-			whichType:   binaryArithType,
+			whichType:   compareType,
 			fn: [numType]binaryFn{
-				intType: func(c Context, u, v Value) Value {
-					return toInt(u.(Int) <= v.(Int))
-				},
+				intType: compareFn(func(cmp int) bool { return cmp <= 0 }),
 				charType: func(c Context, u, v Value) Value {
-					return toInt(u.(Char) <= v.(Char))
-				},
-				bigIntType: func(c Context, u, v Value) Value {
-					i, j := u.(BigInt), v.(BigInt)
-					return toInt(i.Cmp(j.Int) <= 0)
-				},
-				bigRatType: func(c Context, u, v Value) Value {
-					i, j := u.(BigRat), v.(BigRat)
-					return toInt(i.Cmp(j.Rat) <= 0)
+					return boolValue(c, u.(Char) <= v.(Char))
 				},
+				bigIntType:  compareFn(func(cmp int) bool { return cmp <= 0 }),
+				decimalType: compareFn(func(cmp int) bool { return cmp <= 0 }),
+				bigRatType:  compareFn(func(cmp int) bool { return cmp <= 0 }),
 				bigFloatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigFloat), v.(BigFloat)
-					return toInt(i.Cmp(j.Float) <= 0)
+					return boolValue(c, i.Cmp(j.Float) <= 0)
 				},
 			},
 		},
@@ -681,25 +1219,18 @@ func init() {
 		{
 			name:        ">",
 			elementwise: true,
-			whichType:   binaryArithType,
+			whichType:   compareType,
 			fn: [numType]binaryFn{
-				intType: func(c Context, u, v Value) Value {
-					return toInt(u.(Int) > v.(Int))
-				},
+				intType: compareFn(func(cmp int) bool { return cmp > 0 }),
 				charType: func(c Context, u, v Value) Value {
-					return toInt(u.(Char) > v.(Char))
-				},
-				bigIntType: func(c Context, u, v Value) Value {
-					i, j := u.(BigInt), v.(BigInt)
-					return toInt(i.Cmp(j.Int) > 0)
-				},
-				bigRatType: func(c Context, u, v Value) Value {
-					i, j := u.(BigRat), v.(BigRat)
-					return toInt(i.Cmp(j.Rat) > 0)
+					return boolValue(c, u.(Char) > v.(Char))
 				},
+				bigIntType:  compareFn(func(cmp int) bool { return cmp > 0 }),
+				decimalType: compareFn(func(cmp int) bool { return cmp > 0 }),
+				bigRatType:  compareFn(func(cmp int) bool { return cmp > 0 }),
 				bigFloatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigFloat), v.(BigFloat)
-					return toInt(i.Cmp(j.Float) > 0)
+					return boolValue(c, i.Cmp(j.Float) > 0)
 				},
 			},
 		},
@@ -707,103 +1238,108 @@ func init() {
 		{
 			name:        ">=",
 			elementwise: true,
-			whichType:   binaryArithType,
+			whichType:   compareType,
 			fn: [numType]binaryFn{
-				intType: func(c Context, u, v Value) Value {
-					return toInt(u.(Int) >= v.(Int))
-				},
+				intType: compareFn(func(cmp int) bool { return cmp >= 0 }),
 				charType: func(c Context, u, v Value) Value {
-					return toInt(u.(Char) >= v.(Char))
-				},
-				bigIntType: func(c Context, u, v Value) Value {
-					i, j := u.(BigInt), v.(BigInt)
-					return toInt(i.Cmp(j.Int) >= 0)
-				},
-				bigRatType: func(c Context, u, v Value) Value {
-					i, j := u.(BigRat), v.(BigRat)
-					return toInt(i.Cmp(j.Rat) >= 0)
+					return boolValue(c, u.(Char) >= v.(Char))
 				},
+				bigIntType:  compareFn(func(cmp int) bool { return cmp >= 0 }),
+				decimalType: compareFn(func(cmp int) bool { return cmp >= 0 }),
+				bigRatType:  compareFn(func(cmp int) bool { return cmp >= 0 }),
 				bigFloatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigFloat), v.(BigFloat)
-					return toInt(i.Cmp(j.Float) >= 0)
+					return boolValue(c, i.Cmp(j.Float) >= 0)
 				},
 			},
 		},
 
 		{
 			name:        "and",
+			identity:    Int(1),
 			elementwise: true,
 			whichType:   binaryArithType,
 			fn: [numType]binaryFn{
+				boolType: func(c Context, u, v Value) Value {
+					return boolValue(c, toBool(u) && toBool(v))
+				},
 				intType: func(c Context, u, v Value) Value {
-					return toInt(toBool(u) && toBool(v))
+					return boolValue(c, toBool(u) && toBool(v))
 				},
 				charType: func(c Context, u, v Value) Value {
-					return toInt(toBool(u) && toBool(v))
+					return boolValue(c, toBool(u) && toBool(v))
 				},
 				bigIntType: func(c Context, u, v Value) Value {
-					return toInt(toBool(u) && toBool(v))
+					return boolValue(c, toBool(u) && toBool(v))
 				},
 				bigRatType: func(c Context, u, v Value) Value {
-					return toInt(toBool(u) && toBool(v))
+					return boolValue(c, toBool(u) && toBool(v))
 				},
 				bigFloatType: func(c Context, u, v Value) Value {
-					return toInt(toBool(u) && toBool(v))
+					return boolValue(c, toBool(u) && toBool(v))
 				},
 				complexType: func(c Context, u, v Value) Value {
-					return toInt(toBool(u) && toBool(v))
+					return boolValue(c, toBool(u) && toBool(v))
 				},
 			},
 		},
 
 		{
 			name:        "or",
+			identity:    Int(0),
 			elementwise: true,
 			whichType:   binaryArithType,
 			fn: [numType]binaryFn{
+				boolType: func(c Context, u, v Value) Value {
+					return boolValue(c, toBool(u) || toBool(v))
+				},
 				intType: func(c Context, u, v Value) Value {
-					return toInt(toBool(u) || toBool(v))
+					return boolValue(c, toBool(u) || toBool(v))
 				},
 				charType: func(c Context, u, v Value) Value {
-					return toInt(toBool(u) || toBool(v))
+					return boolValue(c, toBool(u) || toBool(v))
 				},
 				bigIntType: func(c Context, u, v Value) Value {
-					return toInt(toBool(u) || toBool(v))
+					return boolValue(c, toBool(u) || toBool(v))
 				},
 				bigRatType: func(c Context, u, v Value) Value {
-					return toInt(toBool(u) || toBool(v))
+					return boolValue(c, toBool(u) || toBool(v))
 				},
 				bigFloatType: func(c Context, u, v Value) Value {
-					return toInt(toBool(u) || toBool(v))
+					return boolValue(c, toBool(u) || toBool(v))
 				},
 				complexType: func(c Context, u, v Value) Value {
-					return toInt(toBool(u) || toBool(v))
+					return boolValue(c, toBool(u) || toBool(v))
 				},
 			},
 		},
 
 		{
 			name:        "xor",
+			identity:    Int(0),
 			elementwise: true,
 			whichType:   binaryArithType,
 			fn: [numType]binaryFn{
+				boolType: func(c Context, u, v Value) Value {
+					return boolValue(c, toBool(u) != toBool(v))
+				},
 				intType: func(c Context, u, v Value) Value {
-					return toInt(toBool(u) != toBool(v))
+					return boolValue(c, toBool(u) != toBool(v))
 				},
 				charType: func(c Context, u, v Value) Value {
-					return toInt(toBool(u) != toBool(v))
+					return boolValue(c, toBool(u) != toBool(v))
 				},
 				bigIntType: func(c Context, u, v Value) Value {
-					return toInt(toBool(u) != toBool(v))
+					return boolValue(c, toBool(u) != toBool(v))
 				},
 				bigRatType: func(c Context, u, v Value) Value {
-					return toInt(toBool(u) != toBool(v))
+					return boolValue(c, toBool(u) != toBool(v))
 				},
 				bigFloatType: func(c Context, u, v Value) Value {
-					return toInt(toBool(u) != toBool(v))
+					return boolValue(c, toBool(u) != toBool(v))
 				},
 				complexType: func(c Context, u, v Value) Value {
-					return toInt(toBool(u) != toBool(v))
+					return boolValue(c, toBool(u) != toBool(v))
 				},
 			},
 		},
@@ -813,23 +1349,26 @@ func init() {
 			elementwise: true,
 			whichType:   binaryArithType,
 			fn: [numType]binaryFn{
+				boolType: func(c Context, u, v Value) Value {
+					return boolValue(c, !(toBool(u) && toBool(v)))
+				},
 				intType: func(c Context, u, v Value) Value {
-					return toInt(!(toBool(u) && toBool(v)))
+					return boolValue(c, !(toBool(u) && toBool(v)))
 				},
 				charType: func(c Context, u, v Value) Value {
-					return toInt(!(toBool(u) && toBool(v)))
+					return boolValue(c, !(toBool(u) && toBool(v)))
 				},
 				bigIntType: func(c Context, u, v Value) Value {
-					return toInt(!(toBool(u) && toBool(v)))
+					return boolValue(c, !(toBool(u) && toBool(v)))
 				},
 				bigRatType: func(c Context, u, v Value) Value {
-					return toInt(!(toBool(u) && toBool(v)))
+					return boolValue(c, !(toBool(u) && toBool(v)))
 				},
 				bigFloatType: func(c Context, u, v Value) Value {
-					return toInt(!(toBool(u) && toBool(v)))
+					return boolValue(c, !(toBool(u) && toBool(v)))
 				},
 				complexType: func(c Context, u, v Value) Value {
-					return toInt(!(toBool(u) && toBool(v)))
+					return boolValue(c, !(toBool(u) && toBool(v)))
 				},
 			},
 		},
@@ -839,23 +1378,26 @@ func init() {
 			elementwise: true,
 			whichType:   binaryArithType,
 			fn: [numType]binaryFn{
+				boolType: func(c Context, u, v Value) Value {
+					return boolValue(c, !(toBool(u) || toBool(v)))
+				},
 				intType: func(c Context, u, v Value) Value {
-					return toInt(!(toBool(u) || toBool(v)))
+					return boolValue(c, !(toBool(u) || toBool(v)))
 				},
 				charType: func(c Context, u, v Value) Value {
-					return toInt(!(toBool(u) || toBool(v)))
+					return boolValue(c, !(toBool(u) || toBool(v)))
 				},
 				bigIntType: func(c Context, u, v Value) Value {
-					return toInt(!(toBool(u) || toBool(v)))
+					return boolValue(c, !(toBool(u) || toBool(v)))
 				},
 				bigRatType: func(c Context, u, v Value) Value {
-					return toInt(!(toBool(u) || toBool(v)))
+					return boolValue(c, !(toBool(u) || toBool(v)))
 				},
 				bigFloatType: func(c Context, u, v Value) Value {
-					return toInt(!(toBool(u) || toBool(v)))
+					return boolValue(c, !(toBool(u) || toBool(v)))
 				},
 				complexType: func(c Context, u, v Value) Value {
-					return toInt(!(toBool(u) || toBool(v)))
+					return boolValue(c, !(toBool(u) || toBool(v)))
 				},
 			},
 		},
@@ -866,21 +1408,7 @@ func init() {
 			whichType:   binaryArithType,
 			fn: [numType]binaryFn{
 				intType: func(c Context, u, v Value) Value {
-					A := u.(Int)
-					B := v.(Int)
-					if uint64(A) > maxInt || uint64(B) > maxInt {
-						Errorf("negative or too-large operand in %d?%d", A, B)
-					}
-					if A > B {
-						Errorf("left operand larger than right in %d?%d", A, B)
-					}
-					ints := c.Config().Random().Perm(int(B))
-					origin := c.Config().Origin()
-					res := make([]Value, A)
-					for i := range res {
-						res[i] = Int(ints[i] + origin)
-					}
-					return NewVector(res)
+					return deal(c, u.(Int), v.(Int))
 				},
 			},
 		},
@@ -1059,38 +1587,18 @@ func init() {
 
 		{
 			name:      "iota",
-			whichType: atLeastVectorType,
+			whichType: indexOfType,
 			fn: [numType]binaryFn{
+				boolType:     scalarIndexOf,
+				intType:      scalarIndexOf,
+				charType:     scalarIndexOf,
+				bigIntType:   scalarIndexOf,
+				decimalType:  scalarIndexOf,
+				bigRatType:   scalarIndexOf,
+				bigFloatType: scalarIndexOf,
+				complexType:  scalarIndexOf,
 				vectorType: func(c Context, u, v Value) Value {
-					// A⍳B: The location (index) of B in A; 0 if not found. (APL does 1+⌈/⍳⍴A)
-					A, B := u.(Vector), v.(Vector)
-					type indexed struct {
-						v     Value
-						index int
-					}
-					origin := c.Config().Origin()
-					sortedA := make([]indexed, len(A))
-					for i, a := range A {
-						sortedA[i] = indexed{a, i + origin}
-					}
-					sort.SliceStable(sortedA, func(i, j int) bool {
-						return c.EvalBinary(sortedA[i].v, "<", sortedA[j].v) == Int(1)
-					})
-					indices := make([]Value, len(B))
-					work := 2 * (1 + int(math.Log2(float64(len(A)))))
-					pfor(true, work, len(B), func(lo, hi int) {
-						for i := lo; i < hi; i++ {
-							b := B[i]
-							indices[i] = Int(origin - 1)
-							pos := sort.Search(len(sortedA), func(j int) bool {
-								return c.EvalBinary(sortedA[j].v, ">=", b) == Int(1)
-							})
-							if pos < len(sortedA) && c.EvalBinary(sortedA[pos].v, "==", b) == Int(1) {
-								indices[i] = Int(sortedA[pos].index)
-							}
-						}
-					})
-					return NewVector(indices)
+					return NewVector(indexOf(c, u.(Vector), v.(Vector)))
 				},
 				matrixType: func(c Context, u, v Value) Value {
 					A, B := u.(*Matrix), v.(*Matrix)
@@ -1128,34 +1636,18 @@ func init() {
 		{
 			name:        "min",
 			elementwise: true,
-			whichType:   binaryArithType,
+			whichType:   compareType,
 			fn: [numType]binaryFn{
-				intType: func(c Context, u, v Value) Value {
-					if u.(Int) < v.(Int) {
-						return u
-					}
-					return v
-				},
+				intType: minMaxFn(-1),
 				charType: func(c Context, u, v Value) Value {
 					if u.(Char) < v.(Char) {
 						return u
 					}
 					return v
 				},
-				bigIntType: func(c Context, u, v Value) Value {
-					i, j := u.(BigInt), v.(BigInt)
-					if i.Cmp(j.Int) < 0 {
-						return i.shrink()
-					}
-					return j.shrink()
-				},
-				bigRatType: func(c Context, u, v Value) Value {
-					i, j := u.(BigRat), v.(BigRat)
-					if i.Cmp(j.Rat) < 0 {
-						return i.shrink()
-					}
-					return j.shrink()
-				},
+				bigIntType:  minMaxFn(-1),
+				decimalType: minMaxFn(-1),
+				bigRatType:  minMaxFn(-1),
 				bigFloatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigFloat), v.(BigFloat)
 					if i.Cmp(j.Float) < 0 {
@@ -1169,34 +1661,18 @@ func init() {
 		{
 			name:        "max",
 			elementwise: true,
-			whichType:   binaryArithType,
+			whichType:   compareType,
 			fn: [numType]binaryFn{
-				intType: func(c Context, u, v Value) Value {
-					if u.(Int) > v.(Int) {
-						return u
-					}
-					return v
-				},
+				intType: minMaxFn(1),
 				charType: func(c Context, u, v Value) Value {
 					if u.(Char) > v.(Char) {
 						return u
 					}
 					return v
 				},
-				bigIntType: func(c Context, u, v Value) Value {
-					i, j := u.(BigInt), v.(BigInt)
-					if i.Cmp(j.Int) > 0 {
-						return u
-					}
-					return v
-				},
-				bigRatType: func(c Context, u, v Value) Value {
-					i, j := u.(BigRat), v.(BigRat)
-					if i.Cmp(j.Rat) > 0 {
-						return i.shrink()
-					}
-					return j.shrink()
-				},
+				bigIntType:  minMaxFn(1),
+				decimalType: minMaxFn(1),
+				bigRatType:  minMaxFn(1),
 				bigFloatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigFloat), v.(BigFloat)
 					if i.Cmp(j.Float) > 0 {
@@ -1315,6 +1791,72 @@ func init() {
 			},
 		},
 
+		{
+			name:      "range",
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return arithSeq(c, u.(Vector), v.(Vector))
+				},
+			},
+		},
+
+		{
+			name:      "partition",
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return partition(c, u.(Vector), v.(Vector))
+				},
+			},
+		},
+
+		{
+			name:      "windows",
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return windows(c, u.(Vector), v.(Vector))
+				},
+			},
+		},
+
+		{
+			name:      "lexcmp",
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return Int(lexCompare(c, u.(Vector), v.(Vector)))
+				},
+			},
+		},
+
+		{
+			name: "match",
+			fn: [numType]binaryFn{
+				func(c Context, u, v Value) Value {
+					return boolValue(c, match(c, u, v))
+				},
+			},
+		},
+
+		{
+			name: "promote",
+			fn: [numType]binaryFn{
+				promote,
+			},
+		},
+
+		{
+			name:      "zip",
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return zip(u.(Vector), v.(Vector))
+				},
+			},
+		},
+
 		{
 			name:      "rot",
 			whichType: atLeastVectorType,
@@ -1406,12 +1948,7 @@ func init() {
 					}
 					result := make([]Value, 0, count)
 					jx := 0
-					var zero Value
-					if j.AllChars() {
-						zero = Char(' ')
-					} else {
-						zero = Int(0)
-					}
+					zero := j.padding()
 					for _, x := range i {
 						y := x.(Int)
 						switch {
@@ -1438,7 +1975,7 @@ func init() {
 			whichType: vectorAndAtLeastVectorType,
 			fn: [numType]binaryFn{
 				vectorType: func(c Context, u, v Value) Value {
-					i := u.(Vector)
+					i := boolCounts(u.(Vector))
 					j := v.(Vector)
 					if len(i) == 0 {
 						return NewVector(nil)
@@ -1485,7 +2022,30 @@ func init() {
 					return NewVector(result)
 				},
 				matrixType: func(c Context, u, v Value) Value {
-					return v.(*Matrix).sel(c, u.(Vector))
+					return v.(*Matrix).sel(c, boolCounts(u.(Vector)))
+				},
+			},
+		},
+
+		{
+			name:      "pick",
+			whichType: vectorAndMatrixType,
+			fn: [numType]binaryFn{
+				matrixType: func(c Context, u, v Value) Value {
+					return v.(*Matrix).pick(c, u.(Vector))
+				},
+			},
+		},
+
+		{
+			name:      "solve",
+			whichType: matrixAndAtLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return solve(c, u.(*Matrix), v)
+				},
+				matrixType: func(c Context, u, v Value) Value {
+					return solve(c, u.(*Matrix), v)
 				},
 			},
 		},
@@ -1513,6 +2073,20 @@ func init() {
 				0: fmtText,
 			},
 		},
+
+		{
+			name: "fmt",
+			fn: [numType]binaryFn{
+				fmtValue,
+			},
+		},
+
+		{
+			name: "writebytes",
+			fn: [numType]binaryFn{
+				writeBytes,
+			},
+		},
 	}
 
 	for _, op := range ops {
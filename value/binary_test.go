@@ -0,0 +1,56 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"robpike.io/ivy/value"
+)
+
+func TestShiftErrors(t *testing.T) {
+	c := newContext()
+	half := value.BigRat{Rat: big.NewRat(1, 2)}
+	huge := value.BigInt{Int: new(big.Int).Lsh(big.NewInt(1), 100)}
+	tests := []struct {
+		u   value.Value
+		op  string
+		v   value.Value
+		err string
+	}{
+		{value.Int(1), "<<", half, "<<: count 1/2 is not an integer"},
+		{half, ">>", value.Int(1), ">>: value 1/2 is not an integer"},
+		{value.Int(1), "<<", value.NewIntVector([]int{1, 2, -3}), "<<: illegal shift count -3"},
+		{value.NewIntVector([]int{1, 2}), ">>", value.Int(-1), ">>: illegal shift count -1"},
+		{value.Int(1), "<<", value.Int(1e9), "<<: shift count 1000000000 exceeds )maxshift 1000000"},
+		{value.Int(1), "<<", huge, "<<: shift count 1267650600228229401496703205376 too large"},
+	}
+	for _, test := range tests {
+		err := catch(func() { c.EvalBinary(test.u, test.op, test.v) })
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s %s %s: got error %v; want %q", sprint(c, test.u), test.op, sprint(c, test.v), err, test.err)
+		}
+	}
+}
+
+func TestMaxShift(t *testing.T) {
+	c := newContext()
+	c.Config().SetMaxShift(10)
+	if got := sprint(c, c.EvalBinary(value.Int(1), "<<", value.Int(10))); got != "1024" {
+		t.Errorf("1 << 10 = %s; want 1024", got)
+	}
+	err := catch(func() { c.EvalBinary(value.Int(1), "<<", value.Int(11)) })
+	if err == nil || !strings.Contains(err.Error(), "exceeds )maxshift 10") {
+		t.Errorf("1 << 11 with )maxshift 10: got error %v", err)
+	}
+	// With no maximum shift, maxbits still applies.
+	c.Config().SetMaxShift(0)
+	err = catch(func() { c.EvalBinary(value.Int(1), "<<", value.Int(2e9)) })
+	if err == nil || !strings.Contains(err.Error(), "result too large") {
+		t.Errorf("1 << 2e9 with )maxshift 0: got error %v", err)
+	}
+}
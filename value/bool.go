@@ -0,0 +1,122 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"robpike.io/ivy/config"
+)
+
+// Bool is the result of a comparison or logical operator when )strictbool
+// is set. It prints as 1 or 0, or as true or false if )boolwords is set,
+// whatever the format. Used as a number it becomes the Int 1 or 0,
+// but only if )strictbool is off; otherwise that is an error, which
+// catches accidental arithmetic on truth values.
+type Bool bool
+
+func (b Bool) String() string {
+	return "(" + b.Sprint(debugConf) + ")"
+}
+
+func (b Bool) Rank() int {
+	return 0
+}
+
+func (b Bool) Sprint(conf *config.Config) string {
+	switch {
+	case conf.BoolWords() && bool(b):
+		return "true"
+	case conf.BoolWords():
+		return "false"
+	case bool(b):
+		return "1"
+	}
+	return "0"
+}
+
+// ProgString returns a comparison that yields b
+// when )strictbool is set.
+func (b Bool) ProgString() string {
+	if b {
+		return "(0 == 0)"
+	}
+	return "(0 == 1)"
+}
+
+func (b Bool) Repr() string {
+	return b.ProgString()
+}
+
+func (b Bool) Eval(Context) Value {
+	return b
+}
+
+func (b Bool) Inner() Value {
+	return b
+}
+
+func (b Bool) shrink() Value {
+	return b
+}
+
+func (b Bool) toType(op string, conf *config.Config, which valueType) Value {
+	switch which {
+	case boolType:
+		return b
+	case vectorType:
+		return NewVector([]Value{b})
+	case matrixType:
+		return NewMatrix([]int{1}, []Value{b})
+	}
+	if conf.StrictBool() {
+		Errorf("%s: cannot use bool as %s with )strictbool set", op, which)
+	}
+	return b.toInt().toType(op, conf, which)
+}
+
+func (b Bool) toInt() Int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// boolValue returns the truth value t as the result of a comparison
+// or logical operator: a Bool if )strictbool is set, otherwise an Int.
+func boolValue(c Context, t bool) Value {
+	if c.Config().StrictBool() {
+		return Bool(t)
+	}
+	return toInt(t)
+}
+
+// boolCounts returns v with any Bools replaced by the Ints 1 and 0,
+// for operators such as sel that use truth values as counts. It
+// does not modify v.
+func boolCounts(v Vector) Vector {
+	copied := false
+	for i, x := range v {
+		if b, ok := x.(Bool); ok {
+			if !copied {
+				v, copied = v.Copy(), true
+			}
+			v[i] = b.toInt()
+		}
+	}
+	return v
+}
+
+// holds reports whether u op v is true, for the comparisons with which
+// sorts and searches order elements. Bools compare as 1 and 0, as they
+// would without )strictbool, which otherwise rejects them as operands
+// of < and the other orderings.
+func holds(c Context, u Value, op string, v Value) bool {
+	if b, ok := u.(Bool); ok {
+		u = b.toInt()
+	}
+	if b, ok := v.(Bool); ok {
+		v = b.toInt()
+	}
+	return toBool(c.EvalBinary(u, op, v))
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"strings"
+
+	"robpike.io/ivy/config"
+)
+
+// A BoundOp is a unary operator derived from a binary one by binding
+// one of its operands: 2 bind (*) doubles its operand and (-) bind 1
+// subtracts 1 from it. A variable holding a BoundOp may be applied
+// like a unary op, but a BoundOp is not a number, and using it as the
+// operand of an operator is an error.
+type BoundOp struct {
+	op   string
+	arg  Value
+	left bool // Whether arg is the left operand of op.
+}
+
+// NewBoundOp returns the unary operator that applies the binary
+// operator op with arg as its left operand, if left is set, or
+// otherwise as its right operand.
+func NewBoundOp(arg Value, op string, left bool) *BoundOp {
+	return &BoundOp{op: op, arg: arg, left: left}
+}
+
+// Op returns the name of the binary operator.
+func (b *BoundOp) Op() string {
+	return b.op
+}
+
+// EvalUnary applies the operator to v.
+func (b *BoundOp) EvalUnary(c Context, v Value) Value {
+	if b.left {
+		return c.EvalBinary(b.arg, b.op, v)
+	}
+	return c.EvalBinary(v, b.op, b.arg)
+}
+
+func (b *BoundOp) String() string {
+	return "(" + b.ProgString() + ")"
+}
+
+func (b *BoundOp) Sprint(*config.Config) string {
+	return b.ProgString()
+}
+
+// ProgString returns the binding as it is written, with the operand
+// in its exact form, so it may be read back.
+func (b *BoundOp) ProgString() string {
+	arg := b.arg.Repr()
+	switch b.arg.(type) {
+	case Vector, *Matrix:
+		arg = "(" + arg + ")"
+	default:
+		if strings.HasPrefix(arg, "-") {
+			arg = "(" + arg + ")"
+		}
+	}
+	if b.left {
+		return arg + " bind (" + b.op + ")"
+	}
+	return "(" + b.op + ") bind " + arg
+}
+
+func (b *BoundOp) Repr() string {
+	return b.ProgString()
+}
+
+func (b *BoundOp) Eval(Context) Value {
+	return b
+}
+
+func (b *BoundOp) Inner() Value {
+	return b
+}
+
+func (b *BoundOp) Rank() int {
+	return 0
+}
+
+func (b *BoundOp) shrink() Value {
+	return b
+}
+
+func (b *BoundOp) toType(op string, conf *config.Config, which valueType) Value {
+	b.notValue()
+	return nil
+}
+
+// notValue reports the error of using b as an operand.
+func (b *BoundOp) notValue() {
+	Errorf("%s is an op, not a value", b.ProgString())
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"io"
+	"os"
+)
+
+// Raw bytes in files, for moving binary data in and out of ivy.
+// A file is read as a vector of integers, one per byte, and a vector
+// of integers in the range 0 to 255 is written as one byte each.
+// File names are used as given, without expansion.
+
+// readBytes implements readbytes, returning the bytes of the named
+// file as a vector of integers. Files longer than )maxread bytes
+// are an error, rather than reading all of a huge file into memory.
+func readBytes(c Context, v Value) Value {
+	name := textArg("readbytes", "file name", v)
+	fd, err := os.Open(name)
+	if err != nil {
+		Errorf("readbytes: %s", err)
+	}
+	defer fd.Close()
+	var r io.Reader = fd
+	max := c.Config().MaxRead()
+	if max != 0 {
+		r = io.LimitReader(fd, int64(max)+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		Errorf("readbytes: %s", err)
+	}
+	if max != 0 && uint64(len(data)) > uint64(max) {
+		Errorf("readbytes: %s: file larger than )maxread %d bytes", name, max)
+	}
+	elems := make([]Value, len(data))
+	for i, b := range data {
+		elems[i] = Int(b)
+	}
+	return NewVector(elems)
+}
+
+// writeBytes implements writebytes, writing the integers of v
+// to the file named by u as bytes. It returns the number written.
+func writeBytes(c Context, u, v Value) Value {
+	return Int(WriteBytes(c, "writebytes", textArg("writebytes", "file name", u), v))
+}
+
+// WriteBytes writes v, an integer or vector of integers in the range
+// 0 to 255, to the named file, one byte per element, replacing any
+// existing contents. It returns the number of bytes written. The op
+// names the operation in errors, which are all of type Error.
+func WriteBytes(c Context, op, name string, v Value) int {
+	var elems []Value
+	switch v := v.(type) {
+	case Vector:
+		elems = v
+	case *Matrix:
+		Errorf("%s: cannot write matrix; ravel it first", op)
+	default:
+		elems = []Value{v}
+	}
+	data := make([]byte, len(elems))
+	for i, elem := range elems {
+		if b, ok := elem.(Bool); ok {
+			elem = Int(b.toInt())
+		}
+		b, ok := elem.(Int)
+		if !ok || b < 0 || 255 < b {
+			Errorf("%s: element %d is %s, not a byte value 0 to 255", op, i+c.Config().Origin(), elem.Sprint(c.Config()))
+		}
+		data[i] = byte(b)
+	}
+	if err := os.WriteFile(name, data, 0666); err != nil {
+		Errorf("%s: %s", op, err)
+	}
+	return len(data)
+}
+
+// textArg returns the text held in v, the operand of op, which
+// must be a char or a vector of chars. What describes the operand.
+func textArg(op, what string, v Value) string {
+	switch v := v.(type) {
+	case Char:
+		return string(v)
+	case Vector:
+		if len(v) > 0 && v.AllChars() {
+			runes := make([]rune, len(v))
+			for i, c := range v {
+				runes[i] = rune(c.(Char))
+			}
+			return string(runes)
+		}
+	}
+	Errorf("%s: %s must be text", op, what)
+	panic("not reached")
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"robpike.io/ivy/value"
+)
+
+// text returns s as an ivy vector of chars.
+func text(s string) value.Value {
+	var elems []value.Value
+	for _, r := range s {
+		elems = append(elems, value.Char(r))
+	}
+	return value.NewVector(elems)
+}
+
+func TestBytesRoundTrip(t *testing.T) {
+	c := newContext()
+	name := filepath.Join(t.TempDir(), "data")
+	data := value.NewIntVector([]int{0, 1, 127, 128, 255, 10})
+	if n := c.EvalBinary(text(name), "writebytes", data); n != value.Int(6) {
+		t.Errorf("writebytes returned %s, want 6", sprint(c, n))
+	}
+	got, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0, 1, 127, 128, 255, 10}; !bytes.Equal(got, want) {
+		t.Errorf("file holds % x, want % x", got, want)
+	}
+	if got := sprint(c, c.EvalUnary("readbytes", text(name))); got != "0 1 127 128 255 10" {
+		t.Errorf("readbytes = %s", got)
+	}
+	// An empty file is an empty vector.
+	c.EvalBinary(text(name), "writebytes", value.NewIntVector(nil))
+	if v := c.EvalUnary("readbytes", text(name)).(value.Vector); len(v) != 0 {
+		t.Errorf("readbytes of empty file = %s", sprint(c, v))
+	}
+}
+
+func TestBytesErrors(t *testing.T) {
+	c := newContext()
+	dir := t.TempDir()
+	name := filepath.Join(dir, "data")
+	big := filepath.Join(dir, "big")
+	if err := os.WriteFile(big, make([]byte, 1001), 0666); err != nil {
+		t.Fatal(err)
+	}
+	c.Config().SetMaxRead(1000)
+	tests := []struct {
+		f   func()
+		err string
+	}{
+		{func() { c.EvalBinary(text(name), "writebytes", value.NewIntVector([]int{1, 256, 3})) }, "writebytes: element 2 is 256"},
+		{func() { c.EvalBinary(text(name), "writebytes", value.NewIntVector([]int{-1})) }, "writebytes: element 1 is -1"},
+		{func() { c.EvalBinary(text(name), "writebytes", text("ab")) }, "writebytes: element 1 is a"},
+		{func() { c.EvalBinary(value.Int(1), "writebytes", value.Int(1)) }, "writebytes: file name must be text"},
+		{func() { c.EvalBinary(text(filepath.Join(dir, "no", "such")), "writebytes", value.Int(1)) }, "no such file or directory"},
+		{func() { c.EvalUnary("readbytes", text(filepath.Join(dir, "missing"))) }, "readbytes: open"},
+		{func() { c.EvalUnary("readbytes", text(big)) }, "file larger than )maxread 1000 bytes"},
+	}
+	for _, test := range tests {
+		err := catch(test.f)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("got error %v; want %q", err, test.err)
+		}
+	}
+	// A failed write leaves no file.
+	if _, err := os.Stat(name); err == nil {
+		t.Errorf("failed writebytes created %s", name)
+	}
+	// With no limit, the large file can be read.
+	c.Config().SetMaxRead(0)
+	if v := c.EvalUnary("readbytes", text(big)).(value.Vector); len(v) != 1001 {
+		t.Errorf("readbytes with no limit read %d bytes", len(v))
+	}
+}
@@ -36,6 +36,10 @@ func (c Char) ProgString() string {
 	return fmt.Sprintf("%q", rune(c))
 }
 
+func (c Char) Repr() string {
+	return c.ProgString()
+}
+
 func (c Char) Eval(Context) Value {
 	return c
 }
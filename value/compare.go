@@ -0,0 +1,129 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import "math/big"
+
+// compareType is the type promotion used by the comparison operators.
+// Exact scalars (Int, BigInt and BigRat) are compared without
+// converting either operand, as scalarCompare can handle any mix of
+// them directly. Anything else is promoted as for arithmetic.
+func compareType(t1, t2 valueType) (valueType, valueType) {
+	if isExactType(t1) && isExactType(t2) {
+		return t1, t2
+	}
+	return binaryArithType(t1, t2)
+}
+
+func isExactType(t valueType) bool {
+	return t == intType || t == bigIntType || t == decimalType || t == bigRatType
+}
+
+// scalarCompare returns -1, 0, or 1 according to whether u is less than,
+// equal to, or greater than v. Each of u and v must be an Int, BigInt,
+// Decimal or BigRat, but they need not be the same type. Comparisons
+// involving only integers do not allocate; those involving a rational
+// allocate at most one temporary.
+func scalarCompare(u, v Value) int {
+	if d, ok := u.(Decimal); ok {
+		if e, ok := v.(Decimal); ok {
+			return d.cmp(e)
+		}
+		u = d.rat()
+	}
+	if e, ok := v.(Decimal); ok {
+		v = e.rat()
+	}
+	switch u := u.(type) {
+	case Int:
+		switch v := v.(type) {
+		case Int:
+			return cmpInt64(int64(u), int64(v))
+		case BigInt:
+			return -cmpBigInt64(v.Int, int64(u))
+		case BigRat:
+			return -cmpRatInt64(v.Rat, int64(u))
+		}
+	case BigInt:
+		switch v := v.(type) {
+		case Int:
+			return cmpBigInt64(u.Int, int64(v))
+		case BigInt:
+			return u.Cmp(v.Int)
+		case BigRat:
+			return -cmpRatBigInt(v.Rat, u.Int)
+		}
+	case BigRat:
+		switch v := v.(type) {
+		case Int:
+			return cmpRatInt64(u.Rat, int64(v))
+		case BigInt:
+			return cmpRatBigInt(u.Rat, v.Int)
+		case BigRat:
+			return u.Cmp(v.Rat)
+		}
+	}
+	Errorf("internal error: cannot compare %s and %s", whichType(u), whichType(v))
+	panic("not reached")
+}
+
+func cmpInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// cmpBigInt64 compares a big.Int with an int64.
+func cmpBigInt64(a *big.Int, b int64) int {
+	if a.IsInt64() {
+		return cmpInt64(a.Int64(), b)
+	}
+	// Too big for an int64, so the sign decides.
+	return a.Sign()
+}
+
+// cmpRatInt64 compares a big.Rat with an int64.
+func cmpRatInt64(a *big.Rat, b int64) int {
+	if a.IsInt() {
+		return cmpBigInt64(a.Num(), b)
+	}
+	// Compare num with b*denom; denom is positive so the order is preserved.
+	t := big.NewInt(b)
+	return a.Num().Cmp(t.Mul(t, a.Denom()))
+}
+
+// cmpRatBigInt compares a big.Rat with a big.Int.
+func cmpRatBigInt(a *big.Rat, b *big.Int) int {
+	if a.IsInt() {
+		return a.Num().Cmp(b)
+	}
+	t := new(big.Int).Mul(b, a.Denom())
+	return a.Num().Cmp(t)
+}
+
+// compareFn returns a binaryFn for the exact scalar types that
+// reports, as an Int, whether the comparison of its arguments
+// satisfies ok.
+func compareFn(ok func(cmp int) bool) binaryFn {
+	return func(c Context, u, v Value) Value {
+		return boolValue(c, ok(scalarCompare(u, v)))
+	}
+}
+
+// minMaxFn returns a binaryFn for the exact scalar types that returns
+// the smaller (sign < 0) or larger (sign > 0) of its arguments,
+// preferring the right argument when they are equal.
+func minMaxFn(sign int) binaryFn {
+	return func(c Context, u, v Value) Value {
+		if scalarCompare(u, v)*sign > 0 {
+			return u
+		}
+		return v
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value_test
+
+import (
+	"math/big"
+	"testing"
+
+	"robpike.io/ivy/config"
+	"robpike.io/ivy/exec"
+	"robpike.io/ivy/value"
+)
+
+// BenchmarkGradeMixed sorts a vector holding a mix of
+// Ints, BigInts and BigRats, which exercises the comparisons.
+func BenchmarkGradeMixed(b *testing.B) {
+	c := exec.NewContext(new(config.Config))
+	elems := make([]value.Value, 1000)
+	for i := range elems {
+		n := int64(len(elems)/2 - i)
+		switch i % 3 {
+		case 0:
+			elems[i] = value.Int(n)
+		case 1:
+			x := new(big.Int).Lsh(big.NewInt(n), 70)
+			elems[i] = value.BigInt{Int: x}
+		case 2:
+			elems[i] = value.BigRat{Rat: big.NewRat(2*n+1, 2)}
+		}
+	}
+	v := value.NewVector(elems)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.EvalUnary("up", v)
+	}
+}
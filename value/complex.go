@@ -47,7 +47,18 @@ func (c Complex) Sprint(conf *config.Config) string {
 }
 
 func (c Complex) ProgString() string {
-	return fmt.Sprintf("%sj%s", c.real, c.imag)
+	return fmt.Sprintf("%sj%s", c.real.ProgString(), c.imag.ProgString())
+}
+
+// Repr returns a literal, unless a part is a float, which has no
+// literal; then it applies the j operator to the parts.
+func (c Complex) Repr() string {
+	_, realFloat := c.real.(BigFloat)
+	_, imagFloat := c.imag.(BigFloat)
+	if realFloat || imagFloat {
+		return fmt.Sprintf("(%s j %s)", c.real.Repr(), c.imag.Repr())
+	}
+	return c.ProgString()
 }
 
 func (c Complex) Eval(Context) Value {
@@ -94,6 +105,10 @@ func (c Complex) neg(ctx Context) Complex {
 	return newComplex(ctx.EvalUnary("-", c.real), ctx.EvalUnary("-", c.imag))
 }
 
+func (c Complex) conj(ctx Context) Complex {
+	return newComplex(c.real, ctx.EvalUnary("-", c.imag))
+}
+
 func (c Complex) recip(ctx Context) Complex {
 	if isZero(c.real) && isZero(c.imag) {
 		Errorf("complex reciprocal of zero")
@@ -121,9 +136,9 @@ func (c Complex) phase(ctx Context) Value {
 	iPos := !isNegative(c.imag)
 	if rZero {
 		if iPos {
-			return BigFloat{floatPiBy2}
+			return BigFloat{newFloat(ctx).Set(consts(ctx.Config()).piBy2)}
 		}
-		return BigFloat{floatMinusPiBy2}
+		return BigFloat{newFloat(ctx).Set(consts(ctx.Config()).minusPiBy2)}
 	}
 	atan := atan(ctx, ctx.EvalBinary(c.imag, "/", c.real))
 	// Correct the quadrants. We lose sign information in the division.
@@ -133,9 +148,9 @@ func (c Complex) phase(ctx Context) Value {
 	case rPos && iPos: // Upper right, π/4, OK.
 	case rPos && !iPos: // Lower right, -π/4, OK.
 	case !rPos && !iPos: // Lower left, π/4, subtract π.
-		atan = ctx.EvalBinary(atan, "-", BigFloat{newFloat(ctx).Set(floatPi)})
+		atan = ctx.EvalBinary(atan, "-", BigFloat{newFloat(ctx).Set(consts(ctx.Config()).pi)})
 	case !rPos && iPos: // Upper left, -π/4, add π.
-		atan = ctx.EvalBinary(atan, "+", BigFloat{newFloat(ctx).Set(floatPi)})
+		atan = ctx.EvalBinary(atan, "+", BigFloat{newFloat(ctx).Set(consts(ctx.Config()).pi)})
 	}
 	return atan
 }
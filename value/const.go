@@ -9,13 +9,15 @@
 // In the unlikely event more digits are needed, it's easy to find the values online and
 // lengthen the constants. Or compute them: By a magic of math, log 2 is not needed
 // to compute log 2 by the algorithm here (the exponent is zero in the Taylor-Maclaurin
-// series) so it is possible to bootstrap to huge precisions.
+// series) so it is possible to bootstrap to huge precisions. For e and pi, which
+// are the constants users see, that is done: above 10000 bits they are computed
+// by computePi and computeE.
 
 package value
 
 import (
-	"fmt"
 	"math/big"
+	"sync"
 
 	"robpike.io/ivy/config"
 )
@@ -50,20 +52,34 @@ var (
 	complexHalf      = newComplex(BigRat{big.NewRat(1, 2)}, zero)
 	minusOneOverTwoI Complex
 
-	// set to constPrecision
-	floatE          *big.Float
-	floatPi         *big.Float
-	floatPiBy2      *big.Float
-	floatMinusPiBy2 *big.Float
-	floatLog2       *big.Float
-	floatLog10      *big.Float
-	floatZero       *big.Float
-	floatOne        *big.Float
-	floatTwo        *big.Float
-	floatHalf       *big.Float
-	floatMinusOne   *big.Float
+	// Small exact constants, used as operands. The precision of an
+	// operand does not matter when it holds its value exactly.
+	floatZero     = new(big.Float).SetInt64(0)
+	floatOne      = new(big.Float).SetInt64(1)
+	floatTwo      = new(big.Float).SetInt64(2)
+	floatHalf     = new(big.Float).SetFloat64(0.5)
+	floatMinusOne = new(big.Float).SetInt64(-1)
 )
 
+// floatConsts holds the fundamental constants rounded to one
+// floating-point precision.
+type floatConsts struct {
+	e          *big.Float
+	pi         *big.Float
+	piBy2      *big.Float
+	minusPiBy2 *big.Float
+	log2       *big.Float
+	log10      *big.Float
+}
+
+// floatConstsCache holds the constants for each precision in use.
+// Interpreters with different precisions may run at once, so the
+// constants cannot be package variables set for the current precision.
+var floatConstsCache struct {
+	sync.Mutex
+	m map[uint]*floatConsts
+}
+
 const strE = "2.7182818284590452353602874713526624977572470936999595749669676277240766303535475945713821785251664274274663919320030599218174135966290435729003342952605956307381323286279434907632338298807531952510190115738341879307021540891499348841675092447614606680822648001684774118537423454424371075390777449920695517027618386062613313845830007520449338265602976067371132007093287091274437470472306969772093101416928368190255151086574637721112523897844250569536967707854499699679468644549059879316368892300987931277361782154249992295763514822082698951936680331825288693984964651058209392398294887933203625094431173012381970684161403970198376793206832823764648042953118023287825098194558153017567173613320698112509961818815930416903515988885193458072738667385894228792284998920868058257492796104841984443634632449684875602336248270419786232090021609902353043699418491463140934317381436405462531520961836908887070167683964243781405927145635490613031072085103837505101157477041718986106873969655212671546889570350354021234078498193343210681701210056278802351930332247450158539047304199577770935036604169973297250886876966403555707162268447162560798826517871341951246652010305921236677194325278675398558944896970964097545918569563802363701621120477427228364896134225164450781824423529486363721417402388934412479635743702637552944483379980161254922785092577825620926226483262779333865664816277251640191059004916449982893150566047258027786318641551956532442586982946959308019152987211725563475463964479101459040905862984967912874068705048958586717479854667757573205681288459205413340539220001137863009455606881667400169842055804033637953764520304024322566135278369511778838638744396625322498506549958862342818997077332761717839280349465014345588970719425863987727547109629537415211151368350627526023264847287039207643100595841166120545297030236472549296669381151373227536450988890313602057248176585118063036442812314965507047510254465011727211555194866850800368532281831521960037356252794495158284188294787610852639813955990067376482922443752871846245780361929819713991475644882626039033814418232625150974827987779964373089970388867782271383605772978824125611907176639465070633045279546618550966661856647097113444740160704626215680717481877844371436988218559670959102596862002353718588748569652200050311734392073211390803293634479727355955277349071783793421637012050054513263835440001863239914907054797780566978533580489669062951194324730995876552368128590413832411607226029983305353708761389396391779574540161372236187893652605381558415871869255386061647798340254351284396129460352913325942794904337299085731580290958631382683291477116396337092400316894586360606458459251269946557248391865642097526850823075442545993769170419777800853627309417101634349076964237222943523661255725088147792231519747780605696725380171807763603462459278778465850656050780844211529697521890874019660906651803516501792504619501366585436632712549639908549144200014574760819302212066024330096412704894390397177195180699086998606636583232278709376502260"
 
 const strPi = "3.1415926535897932384626433832795028841971693993751058209749445923078164062862089986280348253421170679821480865132823066470938446095505822317253594081284811174502841027019385211055596446229489549303819644288109756659334461284756482337867831652712019091456485669234603486104543266482133936072602491412737245870066063155881748815209209628292540917153643678925903600113305305488204665213841469519415116094330572703657595919530921861173819326117931051185480744623799627495673518857527248912279381830119491298336733624406566430860213949463952247371907021798609437027705392171762931767523846748184676694051320005681271452635608277857713427577896091736371787214684409012249534301465495853710507922796892589235420199561121290219608640344181598136297747713099605187072113499999983729780499510597317328160963185950244594553469083026425223082533446850352619311881710100031378387528865875332083814206171776691473035982534904287554687311595628638823537875937519577818577805321712268066130019278766111959092164201989380952572010654858632788659361533818279682303019520353018529689957736225994138912497217752834791315155748572424541506959508295331168617278558890750983817546374649393192550604009277016711390098488240128583616035637076601047101819429555961989467678374494482553797747268471040475346462080466842590694912933136770289891521047521620569660240580381501935112533824300355876402474964732639141992726042699227967823547816360093417216412199245863150302861829745557067498385054945885869269956909272107975093029553211653449872027559602364806654991198818347977535663698074265425278625518184175746728909777727938000816470600161452491921732172147723501414419735685481613611573525521334757418494684385233239073941433345477624168625189835694855620992192221842725502542568876717904946016534668049886272327917860857843838279679766814541009538837863609506800642251252051173929848960841284886269456042419652850222106611863067442786220391949450471237137869609563643719172874677646575739624138908658326459958133904780275900994657640789512694683983525957098258226205224894077267194782684826014769909026401363944374553050682034962524517493996514314298091906592509372216964615157098583874105978859597729754989301617539284681382686838689427741559918559252459539594310499725246808459872736446958486538367362226260991246080512438843904512441365497627807977156914359977001296160894416948685558484063534220722258284886481584560285060168427394522674676788952521385225499546667278239864565961163548862305774564980355936345681743241125150760694794510965960940252288797108931456691368672287489405601015033086179286809208747609178249385890097149096759852613655497818931297848216829989487226588048575640142704775551323796414515237462343645428584447952658678210511413547357395231134271661021359695362314429524849371871101457654035902799344037420073105785390621983874478084784896833214457138687519435064302184531910484810053706146806749192781911979399520614196634287544406437451237181921799983910159195618146751426912397489409071864942319615679452080"
@@ -80,40 +96,103 @@ func newFloat(c Context) *big.Float {
 	return newF(c.Config())
 }
 
-func Consts(c Context) (e, pi BigFloat) {
-	conf := c.Config()
-	if conf.FloatPrec() > constPrecisionInBits {
-		fmt.Fprintf(c.Config().ErrOutput(), "warning: precision too high; only have %d digits (%d bits) of precision for e and pi", constPrecisionInDigits, constPrecisionInBits)
+// consts returns the fundamental constants at the floating-point
+// precision of the configuration. The values are shared and must not
+// be modified.
+func consts(conf *config.Config) *floatConsts {
+	prec := conf.FloatPrec()
+	floatConstsCache.Lock()
+	defer floatConstsCache.Unlock()
+	if k := floatConstsCache.m[prec]; k != nil {
+		return k
 	}
-	floatZero = newF(conf).SetInt64(0)
-	floatOne = newF(conf).SetInt64(1)
-	floatTwo = newF(conf).SetInt64(2)
-	floatHalf = newF(conf).SetFloat64(0.5)
-	floatMinusOne = newF(conf).SetInt64(-1)
-	var ok bool
-	floatE, ok = newF(conf).SetString(strE)
-	if !ok {
-		panic("setting e")
+	set := func(name, str string) *big.Float {
+		f, ok := newF(conf).SetString(str)
+		if !ok {
+			panic("setting " + name)
+		}
+		return f
 	}
-	floatPi, ok = newF(conf).SetString(strPi)
-	if !ok {
-		panic("setting pi")
+	k := &floatConsts{
+		e:     set("e", strE),
+		pi:    set("pi", strPi),
+		log2:  set("log(2)", strLog2),
+		log10: set("log(10)", strLog10),
 	}
-	floatPiBy2, ok = newF(conf).SetString(strPi)
-	if !ok {
-		panic("setting pi")
+	if prec > constPrecisionInBits {
+		k.e = computeE(prec)
+		k.pi = computePi(prec)
 	}
-	floatPiBy2.Quo(floatPiBy2, floatTwo)
-	floatMinusPiBy2 = newF(conf).Neg(floatPiBy2)
-	floatLog2, ok = newF(conf).SetString(strLog2)
-	if !ok {
-		panic("setting log(2)")
+	k.piBy2 = newF(conf).Quo(k.pi, floatTwo)
+	k.minusPiBy2 = newF(conf).Neg(k.piBy2)
+	if floatConstsCache.m == nil {
+		floatConstsCache.m = make(map[uint]*floatConsts)
 	}
-	floatLog10, ok = newF(conf).SetString(strLog10)
-	if !ok {
-		panic("setting log(10)")
+	floatConstsCache.m[prec] = k
+	return k
+}
+
+// Consts returns the values of e and pi at the floating-point
+// precision of the context.
+func Consts(c Context) (e, pi BigFloat) {
+	conf := c.Config()
+	k := consts(conf)
+	return BigFloat{newF(conf).Set(k.e)}, BigFloat{newF(conf).Set(k.pi)}
+}
+
+// constGuardBits is the number of bits beyond the precision of the
+// result that computePi and computeE carry, to absorb the truncation
+// of each term of their series.
+const constGuardBits = 64
+
+// computePi returns pi to prec bits, computed in fixed point with
+// Machin's formula, pi = 16*atan(1/5) - 4*atan(1/239).
+func computePi(prec uint) *big.Float {
+	bits := prec + constGuardBits
+	pi := atanInv(5, bits)
+	pi.Lsh(pi, 4)
+	pi.Sub(pi, new(big.Int).Lsh(atanInv(239, bits), 2))
+	return fixedToFloat(pi, bits, prec)
+}
+
+// atanInv returns atan(1/n) in fixed point with the given number of
+// fraction bits, summing the series 1/n - 1/3n³ + 1/5n⁵ - ...
+func atanInv(n int64, bits uint) *big.Int {
+	sum := new(big.Int)
+	power := new(big.Int).Lsh(big.NewInt(1), bits) // 1/n**k in fixed point.
+	n2 := big.NewInt(n * n)
+	power.Quo(power, big.NewInt(n))
+	term := new(big.Int)
+	for k := int64(1); power.Sign() != 0; k += 2 {
+		term.Quo(power, big.NewInt(k))
+		if k%4 == 1 {
+			sum.Add(sum, term)
+		} else {
+			sum.Sub(sum, term)
+		}
+		power.Quo(power, n2)
 	}
-	return BigFloat{newF(conf).Set(floatE)}, BigFloat{newF(conf).Set(floatPi)}
+	return sum
+}
+
+// computeE returns e to prec bits, computed in fixed point
+// by summing the series 1 + 1/1! + 1/2! + ...
+func computeE(prec uint) *big.Float {
+	bits := prec + constGuardBits
+	term := new(big.Int).Lsh(big.NewInt(1), bits) // 1/k! in fixed point.
+	sum := new(big.Int).Set(term)
+	for k := int64(1); term.Sign() != 0; k++ {
+		term.Quo(term, big.NewInt(k))
+		sum.Add(sum, term)
+	}
+	return fixedToFloat(sum, bits, prec)
+}
+
+// fixedToFloat returns x, a fixed-point number with the given
+// number of fraction bits, as a Float with precision prec.
+func fixedToFloat(x *big.Int, bits, prec uint) *big.Float {
+	f := new(big.Float).SetPrec(prec).SetInt(x)
+	return f.SetMantExp(f, -int(bits))
 }
 
 // -1/2i is remarkably hard to build.
@@ -0,0 +1,85 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value_test
+
+import (
+	"math/big"
+	"testing"
+
+	"robpike.io/ivy/value"
+)
+
+const (
+	pi500 = "3.14159265358979323846264338327950288419716939937510582097494459230781640628620899862803482534211706798214808651328230664709384460955058223172535940812848111745028410270193852110555964462294895493038196442881097566593344612847564823378678316527120190914564856692346034861045432664821339360726024914127372458700660631558817488152092096282925409171536436789259036001133053054882046652138414695194151160943305727036575959195309218611738193261179310511854807446237996274956735188575272489122793818301194912"
+	e500  = "2.71828182845904523536028747135266249775724709369995957496696762772407663035354759457138217852516642742746639193200305992181741359662904357290033429526059563073813232862794349076323382988075319525101901157383418793070215408914993488416750924476146066808226480016847741185374234544243710753907774499206955170276183860626133138458300075204493382656029760673711320070932870912744374704723069697720931014169283681902551510865746377211125238978442505695369677078544996996794686445490598793163688923009879312"
+)
+
+// digits returns f in decimal, truncated to n places.
+func digits(f value.BigFloat, n int) string {
+	s := f.Text('f', n+10)
+	return s[:len(s)-10]
+}
+
+func TestConsts(t *testing.T) {
+	tests := []struct {
+		prec   uint
+		places int
+	}{
+		{200, 50},
+		{1700, 500},
+		{20000, 500}, // Computed, not taken from the stored digits.
+		{100000, 500},
+	}
+	for _, test := range tests {
+		c := newContext()
+		c.Config().SetFloatPrec(test.prec)
+		e, pi := value.Consts(c)
+		if got, want := digits(pi, test.places), pi500[:2+test.places]; got != want {
+			t.Errorf("prec %d: pi = %s; want %s", test.prec, got, want)
+		}
+		if got, want := digits(e, test.places), e500[:2+test.places]; got != want {
+			t.Errorf("prec %d: e = %s; want %s", test.prec, got, want)
+		}
+	}
+}
+
+// TestComputedConsts checks that the values of e and pi computed
+// for high precisions agree with the stored digits.
+func TestComputedConsts(t *testing.T) {
+	stored := newContext()
+	stored.Config().SetFloatPrec(10000)
+	storedE, storedPi := value.Consts(stored)
+	computed := newContext()
+	computed.Config().SetFloatPrec(12000)
+	computedE, computedPi := value.Consts(computed)
+	const places = 2990
+	if got, want := digits(computedPi, places), digits(storedPi, places); got != want {
+		t.Errorf("computed pi differs from stored digits")
+	}
+	if got, want := digits(computedE, places), digits(storedE, places); got != want {
+		t.Errorf("computed e differs from stored digits")
+	}
+}
+
+func TestSqrtn(t *testing.T) {
+	c := newContext()
+	tests := []struct {
+		digits int
+		x      value.Value
+		want   string // The square root truncated to the number of digits.
+	}{
+		{50, value.Int(2), "1.41421356237309504880168872420969807856967187537694"},
+		{500, value.Int(2), "1.41421356237309504880168872420969807856967187537694807317667973799073247846210703885038753432764157273501384623091229702492483605585073721264412149709993583141322266592750559275579995050115278206057147010955997160597027453459686201472851741864088919860955232923048430871432145083976260362799525140798968725339654633180882964062061525835239505474575028775996172983557522033753185701135437460340849884716038689997069900481503054402779031645424782306849293691862158057846311159666871301301561856898723723"},
+		{30, value.BigRat{Rat: big.NewRat(1, 3)}, "0.577350269189625764509148780501"},
+		{3, value.Int(10000), "100.000"},
+	}
+	for _, test := range tests {
+		got := c.EvalBinary(value.Int(test.digits), "sqrtn", test.x)
+		want, _ := new(big.Rat).SetString(test.want)
+		if eq := c.EvalBinary(got, "==", value.BigRat{Rat: want}); eq != value.Int(1) && eq != value.Bool(true) {
+			t.Errorf("%d sqrtn %s = %s; want %s", test.digits, sprint(c, test.x), sprint(c, got), test.want)
+		}
+	}
+}
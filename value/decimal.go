@@ -0,0 +1,297 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"robpike.io/ivy/config"
+)
+
+// Decimal is an exact decimal number, mant×10**-scale, for calculations
+// such as those with money in which rationals like 1/3 make no sense.
+// The scale, which is never negative, is the number of digits after the
+// decimal point and is kept through calculations, so 19.90 stays 19.90.
+// Decimals are not shrunk to integers.
+type Decimal struct {
+	mant  *big.Int
+	scale int
+}
+
+func (d Decimal) Rank() int {
+	return 0
+}
+
+// maxDecimalExp bounds the exponent of a decimal literal and the scale
+// of a product, to keep 1e1000000000 from consuming all of memory.
+const maxDecimalExp = 1e6
+
+// parseDecimal parses s, a number such as 19.99 or 1.5e-3 with
+// decimal digits, as a Decimal.
+func parseDecimal(s string) (Decimal, error) {
+	exp := 0
+	if e := strings.IndexAny(s, "eE"); e >= 0 {
+		var err error
+		exp, err = strconv.Atoi(s[e+1:])
+		if err != nil {
+			return Decimal{}, fmt.Errorf("bad decimal exponent in %q", s)
+		}
+		if exp < -maxDecimalExp || maxDecimalExp < exp {
+			return Decimal{}, fmt.Errorf("decimal exponent too large in %q", s)
+		}
+		s = s[:e]
+	}
+	frac := ""
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		s, frac = s[:dot], s[dot+1:]
+	}
+	digits := s + frac
+	if digits == "" || digits == "-" || digits == "+" || strings.ContainsAny(digits[1:], "+-") {
+		return Decimal{}, fmt.Errorf("bad decimal number %q", digits)
+	}
+	mant, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("bad decimal number %q", digits)
+	}
+	return newDecimal(mant, len(frac)-exp), nil
+}
+
+// newDecimal returns mant×10**-scale. A negative scale
+// is folded into the mantissa.
+func newDecimal(mant *big.Int, scale int) Decimal {
+	if scale < 0 {
+		mant.Mul(mant, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{mant, scale}
+}
+
+// pow10 returns 10**n.
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(bigIntTen, big.NewInt(int64(n)), nil)
+}
+
+func (d Decimal) String() string {
+	return "(" + d.Sprint(debugConf) + ")"
+}
+
+func (d Decimal) Sprint(conf *config.Config) string {
+	if conf.Format() != "" || conf.OutputBase() != 0 && conf.OutputBase() != 10 {
+		return d.rat().Sprint(conf)
+	}
+	return decimalText(conf, d.text())
+}
+
+// text returns d as a plain decimal number with exactly
+// d.scale digits after the decimal point.
+func (d Decimal) text() string {
+	digits := new(big.Int).Abs(d.mant).String()
+	if d.scale > 0 {
+		if len(digits) <= d.scale {
+			digits = zeros(d.scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-d.scale] + "." + digits[len(digits)-d.scale:]
+	}
+	if d.mant.Sign() < 0 {
+		digits = "-" + digits
+	}
+	return digits
+}
+
+// ProgString returns a conversion of a rational literal, which is
+// a Decimal whatever the setting of )numbers.
+func (d Decimal) ProgString() string {
+	return "(decimal " + d.text() + ")"
+}
+
+func (d Decimal) Repr() string {
+	return d.ProgString()
+}
+
+func (d Decimal) Eval(Context) Value {
+	return d
+}
+
+func (d Decimal) Inner() Value {
+	return d
+}
+
+func (d Decimal) shrink() Value {
+	return d
+}
+
+// rat returns the value of d as a BigRat.
+func (d Decimal) rat() BigRat {
+	r := new(big.Rat).SetInt(d.mant)
+	if d.scale > 0 {
+		r.Quo(r, new(big.Rat).SetInt(pow10(d.scale)))
+	}
+	return BigRat{r}
+}
+
+func (d Decimal) toType(op string, conf *config.Config, which valueType) Value {
+	switch which {
+	case decimalType:
+		return d
+	case bigRatType:
+		return d.rat()
+	case bigFloatType:
+		return d.rat().toType(op, conf, bigFloatType)
+	case complexType:
+		return newComplex(d.rat().shrink(), Int(0))
+	case vectorType:
+		return NewVector([]Value{d})
+	case matrixType:
+		return NewMatrix([]int{1}, []Value{d})
+	}
+	Errorf("%s: cannot convert decimal to %s", op, which)
+	return nil
+}
+
+// toDecimal converts v, an integer or a rational that can be written
+// as a finite decimal, to a Decimal. Other rationals are rounded to the
+// scale set by )scale using the rounding mode, or are an error if no
+// scale is set.
+func toDecimal(conf *config.Config, v Value) Decimal {
+	switch v := v.(type) {
+	case Int:
+		return Decimal{big.NewInt(int64(v)), 0}
+	case BigInt:
+		return Decimal{new(big.Int).Set(v.Int), 0}
+	case Decimal:
+		return v
+	case BigRat:
+		return ratDecimal(conf, v.Rat)
+	case BigFloat:
+		// Floats are binary, so are always finite decimals,
+		// but use the shortest decimal that identifies the float.
+		d, err := parseDecimal(v.Text('e', -1))
+		if err != nil {
+			Errorf("decimal: %s", err)
+		}
+		return d
+	}
+	Errorf("cannot convert %s to decimal", whichType(v))
+	panic("not reached")
+}
+
+// ratDecimal returns r as a Decimal; see toDecimal.
+func ratDecimal(conf *config.Config, r *big.Rat) Decimal {
+	// r is a finite decimal if its denominator has no prime
+	// factors but 2 and 5. It then needs as many decimal places
+	// as the larger of the powers of 2 and 5.
+	den := new(big.Int).Set(r.Denom())
+	twos := den.TrailingZeroBits()
+	den.Rsh(den, twos)
+	fives := uint(0)
+	five := big.NewInt(5)
+	var rem big.Int
+	for {
+		q, _ := new(big.Int).QuoRem(den, five, &rem)
+		if rem.Sign() != 0 {
+			break
+		}
+		den = q
+		fives++
+	}
+	if den.Cmp(bigIntOne.Int) == 0 {
+		scale := twos
+		if fives > scale {
+			scale = fives
+		}
+		mant := new(big.Int).Mul(r.Num(), pow10(int(scale)))
+		return Decimal{mant.Quo(mant, r.Denom()), int(scale)}
+	}
+	scale := conf.DecimalScale()
+	if scale < 0 {
+		Errorf("%s is not a finite decimal; set )scale to round it", BigRat{r}.Sprint(conf))
+	}
+	return roundDecimal(conf, r, scale)
+}
+
+// roundDecimal returns r rounded to scale decimal places
+// using the rounding mode of the configuration.
+func roundDecimal(conf *config.Config, r *big.Rat, scale int) Decimal {
+	mant := ratRound(r, scale, conf.RoundingMode())
+	if r.Sign() < 0 {
+		mant.Neg(mant)
+	}
+	return Decimal{mant, scale}
+}
+
+// align returns the mantissas of d and e scaled to their common scale,
+// which is also returned. It does not modify d or e.
+func (d Decimal) align(e Decimal) (x, y *big.Int, scale int) {
+	x, y = d.mant, e.mant
+	switch {
+	case d.scale < e.scale:
+		x = new(big.Int).Mul(x, pow10(e.scale-d.scale))
+		return x, y, e.scale
+	case d.scale > e.scale:
+		y = new(big.Int).Mul(y, pow10(d.scale-e.scale))
+	}
+	return x, y, d.scale
+}
+
+func (d Decimal) add(e Decimal) Decimal {
+	x, y, scale := d.align(e)
+	return Decimal{new(big.Int).Add(x, y), scale}
+}
+
+func (d Decimal) sub(e Decimal) Decimal {
+	x, y, scale := d.align(e)
+	return Decimal{new(big.Int).Sub(x, y), scale}
+}
+
+func (d Decimal) mul(e Decimal) Decimal {
+	if d.scale+e.scale > maxDecimalExp {
+		Errorf("decimal scale too large")
+	}
+	return Decimal{new(big.Int).Mul(d.mant, e.mant), d.scale + e.scale}
+}
+
+// quo returns d/e. If )scale is set, the quotient is rounded to that
+// many decimal places; otherwise it must be a finite decimal.
+func (d Decimal) quo(conf *config.Config, e Decimal) Decimal {
+	if e.mant.Sign() == 0 {
+		Errorf("division by zero")
+	}
+	r := d.rat()
+	r.Quo(r.Rat, e.rat().Rat)
+	if scale := conf.DecimalScale(); scale >= 0 {
+		return roundDecimal(conf, r.Rat, scale)
+	}
+	q := ratDecimal(conf, r.Rat)
+	// Keep at least the scale of the dividend, so 10.00/4 is 2.50.
+	if q.scale < d.scale {
+		q = Decimal{q.mant.Mul(q.mant, pow10(d.scale-q.scale)), d.scale}
+	}
+	return q
+}
+
+// divMod returns the Euclidean quotient and remainder of d and e:
+// q is an integer and r = d - q*e satisfies 0 <= r < |e|.
+// The caller must check that e is not zero.
+func (d Decimal) divMod(e Decimal) (q *big.Int, r Decimal) {
+	x, y, scale := d.align(e)
+	q, m := new(big.Int).DivMod(x, y, new(big.Int))
+	return q, Decimal{m, scale}
+}
+
+func (d Decimal) neg() Decimal {
+	return Decimal{new(big.Int).Neg(d.mant), d.scale}
+}
+
+func (d Decimal) cmp(e Decimal) int {
+	x, y, _ := d.align(e)
+	return x.Cmp(y)
+}
+
+func (d Decimal) Sign() int {
+	return d.mant.Sign()
+}
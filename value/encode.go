@@ -0,0 +1,322 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"unicode/utf8"
+)
+
+// Binary encoding of values.
+//
+// Each value is a tag byte followed by its data. A Bool is a byte, 0 or 1;
+// integers are varints;
+// big numbers use the GobEncode form from math/big, preceded by its
+// length; a decimal is its mantissa as a big number followed by its scale;
+// a complex number is its two parts; a vector is its length followed by
+// its elements; a matrix is its rank, its shape and then its data as
+// for a vector; and a bound op is the name of its operator, a byte that
+// is 1 if its operand is on the left, and the operand.
+
+const (
+	tagInt byte = iota
+	tagChar
+	tagBigInt
+	tagBigRat
+	tagBigFloat
+	tagComplex
+	tagVector
+	tagMatrix
+	tagDecimal
+	tagBool
+	tagBoundOp
+)
+
+var errShortData = errors.New("data too short")
+
+// AppendBinary appends the binary encoding of v to b and returns
+// the extended slice. DecodeBinary recovers the value.
+func AppendBinary(b []byte, v Value) []byte {
+	switch v := v.(type) {
+	case Bool:
+		return append(b, tagBool, byte(v.toInt()))
+	case Int:
+		b = append(b, tagInt)
+		return appendVarint(b, int64(v))
+	case Char:
+		b = append(b, tagChar)
+		return appendUvarint(b, uint64(v))
+	case BigInt:
+		return appendGob(b, tagBigInt, v.Int)
+	case Decimal:
+		b = appendGob(b, tagDecimal, v.mant)
+		return appendUvarint(b, uint64(v.scale))
+	case BigRat:
+		return appendGob(b, tagBigRat, v.Rat)
+	case BigFloat:
+		return appendGob(b, tagBigFloat, v.Float)
+	case Complex:
+		b = append(b, tagComplex)
+		b = AppendBinary(b, v.real)
+		return AppendBinary(b, v.imag)
+	case Vector:
+		b = append(b, tagVector)
+		return appendElems(b, v)
+	case *Matrix:
+		b = append(b, tagMatrix)
+		b = appendUvarint(b, uint64(len(v.shape)))
+		for _, n := range v.shape {
+			b = appendUvarint(b, uint64(n))
+		}
+		return appendElems(b, v.data)
+	case *BoundOp:
+		b = append(b, tagBoundOp)
+		b = appendUvarint(b, uint64(len(v.op)))
+		b = append(b, v.op...)
+		left := byte(0)
+		if v.left {
+			left = 1
+		}
+		b = append(b, left)
+		return AppendBinary(b, v.arg)
+	}
+	Errorf("cannot encode %T", v)
+	panic("not reached")
+}
+
+func appendVarint(b []byte, x int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutVarint(buf[:], x)]...)
+}
+
+func appendUvarint(b []byte, x uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], x)]...)
+}
+
+func appendElems(b []byte, elems []Value) []byte {
+	b = appendUvarint(b, uint64(len(elems)))
+	for _, elem := range elems {
+		b = AppendBinary(b, elem)
+	}
+	return b
+}
+
+func appendGob(b []byte, tag byte, x interface{ GobEncode() ([]byte, error) }) []byte {
+	data, err := x.GobEncode()
+	if err != nil {
+		Errorf("%s", err)
+	}
+	b = append(b, tag)
+	b = appendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// DecodeBinary decodes the value at the start of data, which was
+// encoded by AppendBinary, and returns it and the rest of the data.
+// Malformed data yields an error, not a panic.
+func DecodeBinary(data []byte) (Value, []byte, error) {
+	d := decoder{data: data}
+	v := d.value()
+	if d.err != nil {
+		return nil, nil, d.err
+	}
+	return v, d.data, nil
+}
+
+// decoder holds the state of DecodeBinary. After the first
+// error, its methods do nothing and return zero values.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+func (d *decoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.data) == 0 {
+		d.fail(errShortData)
+		return 0
+	}
+	c := d.data[0]
+	d.data = d.data[1:]
+	return c
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	x, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.fail(errShortData)
+		return 0
+	}
+	d.data = d.data[n:]
+	return x
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	x, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail(errShortData)
+		return 0
+	}
+	d.data = d.data[n:]
+	return x
+}
+
+// count returns a length read from the data. Every item counted
+// takes at least one byte, so a count larger than the remaining data
+// is an error, which stops corrupt data from causing huge allocations.
+func (d *decoder) count() int {
+	n := d.uvarint()
+	if n > uint64(len(d.data)) {
+		d.fail(errShortData)
+		return 0
+	}
+	return int(n)
+}
+
+// bytes returns a length-prefixed byte slice from the data.
+func (d *decoder) bytes() []byte {
+	n := d.count()
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *decoder) gob(x interface{ GobDecode([]byte) error }) {
+	b := d.bytes()
+	if d.err != nil {
+		return
+	}
+	if err := x.GobDecode(b); err != nil {
+		d.fail(err)
+	}
+}
+
+func (d *decoder) value() Value {
+	tag := d.byte()
+	if d.err != nil {
+		return nil
+	}
+	switch tag {
+	case tagBool:
+		switch d.byte() {
+		case 0:
+			return Bool(false)
+		case 1:
+			return Bool(true)
+		}
+		d.fail(errors.New("bad bool"))
+		return nil
+	case tagInt:
+		return Int(d.varint()).maybeBig()
+	case tagChar:
+		r := d.uvarint()
+		if r > utf8.MaxRune || !utf8.ValidRune(rune(r)) {
+			d.fail(fmt.Errorf("invalid char %#x", r))
+			return nil
+		}
+		return Char(r)
+	case tagBigInt:
+		i := BigInt{new(big.Int)}
+		d.gob(i.Int)
+		return i.shrink()
+	case tagDecimal:
+		mant := new(big.Int)
+		d.gob(mant)
+		scale := d.uvarint()
+		if scale > maxDecimalExp {
+			d.fail(errors.New("decimal scale too large"))
+			return nil
+		}
+		return Decimal{mant, int(scale)}
+	case tagBigRat:
+		r := BigRat{new(big.Rat)}
+		d.gob(r.Rat)
+		return r.shrink()
+	case tagBigFloat:
+		f := BigFloat{new(big.Float)}
+		d.gob(f.Float)
+		if d.err == nil && f.IsInf() {
+			d.fail(errors.New("infinite float"))
+		}
+		return f
+	case tagComplex:
+		re := d.value()
+		im := d.value()
+		if d.err == nil && (!simpleNumber(re) || !simpleNumber(im)) {
+			d.fail(errors.New("bad complex number"))
+		}
+		return Complex{re, im}
+	case tagVector:
+		return NewVector(d.elems())
+	case tagMatrix:
+		rank := d.count()
+		if rank == 0 && d.err == nil {
+			d.fail(errors.New("matrix of rank 0"))
+		}
+		shape := make([]int, rank)
+		size := uint64(1)
+		for i := range shape {
+			n := d.uvarint()
+			size *= n
+			if n > maxInt || size > maxInt {
+				d.fail(errors.New("matrix too large"))
+				return nil
+			}
+			shape[i] = int(n)
+		}
+		data := d.elems()
+		if d.err == nil && uint64(len(data)) != size {
+			d.fail(errors.New("inconsistent shape and data size for matrix"))
+		}
+		return &Matrix{shape: shape, data: data}
+	case tagBoundOp:
+		op := string(d.bytes())
+		left := d.byte()
+		arg := d.value()
+		if d.err != nil {
+			return nil
+		}
+		if _, ok := arg.(*BoundOp); ok || op == "" || left > 1 {
+			d.fail(errors.New("bad bound op"))
+			return nil
+		}
+		return NewBoundOp(arg, op, left == 1)
+	}
+	d.fail(fmt.Errorf("unknown value tag %d", tag))
+	return nil
+}
+
+func (d *decoder) elems() []Value {
+	n := d.count()
+	if d.err != nil {
+		return nil
+	}
+	elems := make([]Value, n)
+	for i := range elems {
+		elems[i] = d.value()
+		if d.err != nil {
+			return nil
+		}
+	}
+	return elems
+}
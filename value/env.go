@@ -0,0 +1,21 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+// env implements the env operator, which returns the number, or vector
+// of numbers separated by spaces, held in the named environment variable.
+// The numbers are read as literals are, so )ibase applies.
+func env(c Context, v Value) Value {
+	name := textArg("env", "variable name", v)
+	s, ok := c.Config().LookupEnv(name)
+	if !ok {
+		Errorf("env: $%s is not set", name)
+	}
+	x, err := ParseNumbers(c.Config(), s)
+	if err != nil {
+		Errorf("env: $%s: %s", name, err)
+	}
+	return x
+}
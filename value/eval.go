@@ -8,14 +8,17 @@ import (
 	"math/big"
 	"runtime"
 	"strings"
+	"sync"
 )
 
 type valueType int
 
 const (
-	intType valueType = iota
+	boolType valueType = iota
+	intType
 	charType
 	bigIntType
+	decimalType
 	bigRatType
 	bigFloatType
 	complexType
@@ -24,7 +27,7 @@ const (
 	numType
 )
 
-var typeName = [...]string{"int", "char", "big int", "rational", "float", "complex", "vector", "matrix"}
+var typeName = [...]string{"bool", "int", "char", "big int", "decimal", "rational", "float", "complex", "vector", "matrix"}
 
 func (t valueType) String() string {
 	return typeName[t]
@@ -41,6 +44,16 @@ type unaryOp struct {
 func (op *unaryOp) EvalUnary(c Context, v Value) Value {
 	which := whichType(v)
 	fn := op.fn[which]
+	if fn == nil && which == boolType {
+		// Bools act as Ints in ops that lack their own implementation,
+		// unless )strictbool is set.
+		v = v.toType(op.name, c.Config(), intType)
+		which, fn = intType, op.fn[intType]
+	}
+	if fn == nil && which == decimalType && op.fn[bigRatType] != nil {
+		// Decimals use the rational implementation of ops that lack their own.
+		return op.fn[bigRatType](c, v.toType(op.name, c.Config(), bigRatType))
+	}
 	if fn == nil {
 		if op.elementwise {
 			switch which {
@@ -61,17 +74,61 @@ type binaryOp struct {
 	name        string
 	elementwise bool // whether the operation applies elementwise to vectors and matrices
 	whichType   func(a, b valueType) (valueType, valueType)
+	identity    Value // the value x for which y op x is y, or nil if there is none
 	fn          [numType]binaryFn
 }
 
+// sumInts returns the sum of v, whose elements are Ints and Bools,
+// without dispatching on the types of each pair. Ints fit in 32 bits,
+// so the sum of any vector that fits in memory fits in an int64.
+func sumInts(v Vector) Value {
+	var sum int64
+	for _, x := range v {
+		switch x := x.(type) {
+		case Int:
+			sum += int64(x)
+		case Bool:
+			sum += int64(x.toInt())
+		}
+	}
+	return Int(sum).maybeBig()
+}
+
+// TypeName returns the name of the type of v, such as "int",
+// "rational" or "vector".
+func TypeName(v Value) string {
+	if _, ok := v.Inner().(*BoundOp); ok {
+		return "op"
+	}
+	return whichType(v).String()
+}
+
+// typeWord holds the names of the types as the type operator reports
+// them. They are single words, and for numbers they are the names
+// promote accepts, with bigint for integers too big for an int.
+var typeWord = [...]string{"bool", "int", "char", "bigint", "decimal", "rat", "float", "complex", "vector", "matrix"}
+
+// TypeWord returns the name of the type of v as the type operator
+// reports it, such as "int", "bigint", "rat" or "vector".
+func TypeWord(v Value) string {
+	if _, ok := v.Inner().(*BoundOp); ok {
+		return "op"
+	}
+	return typeWord[whichType(v)]
+}
+
 func whichType(v Value) valueType {
 	switch v.Inner().(type) {
+	case Bool:
+		return boolType
 	case Int:
 		return intType
 	case Char:
 		return charType
 	case BigInt:
 		return bigIntType
+	case Decimal:
+		return decimalType
 	case BigRat:
 		return bigRatType
 	case BigFloat:
@@ -82,6 +139,8 @@ func whichType(v Value) valueType {
 		return vectorType
 	case *Matrix:
 		return matrixType
+	case *BoundOp:
+		v.Inner().(*BoundOp).notValue()
 	}
 	Errorf("unknown type %T in whichType", v)
 	panic("which type")
@@ -89,14 +148,33 @@ func whichType(v Value) valueType {
 
 func (op *binaryOp) EvalBinary(c Context, u, v Value) Value {
 	if op.whichType == nil {
-		// At the moment, "text" is the only operator that leaves
-		// both arg types alone. Perhaps more will arrive.
-		if op.name != "text" {
+		// Only these operators leave both arg types alone.
+		switch op.name {
+		case "text", "fmt", "match", "promote", "rotl", "rotr", "writebytes":
+		default:
 			Errorf("internal error: nil whichType")
 		}
 		return op.fn[0](c, u, v)
 	}
-	whichU, whichV := op.whichType(whichType(u), whichType(v))
+	typeU, typeV := whichType(u), whichType(v)
+	if (typeU == boolType || typeV == boolType) && op.fn[boolType] == nil {
+		// Bools act as Ints in ops that lack their own implementation,
+		// unless )strictbool is set, in which case toType will fail.
+		if typeU == boolType {
+			typeU = intType
+		}
+		if typeV == boolType {
+			typeV = intType
+		}
+	}
+	whichU, whichV := op.whichType(typeU, typeV)
+	if whichV == decimalType && op.fn[decimalType] == nil && op.fn[bigRatType] != nil {
+		// Decimals use the rational implementation of ops that lack their own.
+		if whichU == decimalType {
+			whichU = bigRatType
+		}
+		whichV = bigRatType
+	}
 	conf := c.Config()
 	u = u.toType(op.name, conf, whichU)
 	v = v.toType(op.name, conf, whichV)
@@ -133,17 +211,17 @@ func Product(c Context, u Value, op string, v Value) Value {
 }
 
 // safeBinary reports whether the binary operator op is safe to parallelize.
-func safeBinary(op string) bool {
-	// ? uses the random number generator,
-	// which maintains global state.
-	return BinaryOps[op] != nil && op != "?"
+// Built-in operators are, other than ?, which uses the random number
+// generator, which maintains global state. A user-defined op is not, even
+// if it shares the name of a built-in one, as it runs on the context's stack.
+func safeBinary(c Context, op string) bool {
+	return BinaryOps[op] != nil && op != "?" && !c.UserDefined(op, true)
 }
 
 // safeUnary reports whether the unary operator op is safe to parallelize.
-func safeUnary(op string) bool {
-	// ? uses the random number generator,
-	// which maintains global state.
-	return UnaryOps[op] != nil && op != "?"
+// See safeBinary.
+func safeUnary(c Context, op string) bool {
+	return UnaryOps[op] != nil && op != "?" && !c.UserDefined(op, false)
 }
 
 // knownAssoc reports whether the binary op is known to be associative.
@@ -155,6 +233,29 @@ func knownAssoc(op string) bool {
 	return false
 }
 
+// identity returns the identity element of the binary operator op, the
+// value x for which y op x is y, or nil if op has none. User-defined
+// ops have none.
+func identity(c Context, op string) Value {
+	if c.UserDefined(op, true) {
+		return nil
+	}
+	if op, ok := BinaryOps[op].(*binaryOp); ok {
+		return op.identity
+	}
+	return nil
+}
+
+// mustIdentity returns the identity element of op, for the reduction
+// of an empty vector or row, or errors if op has none.
+func mustIdentity(c Context, op string) Value {
+	id := identity(c, op)
+	if id == nil {
+		Errorf("%s/ of empty vector: %s has no identity element", op, op)
+	}
+	return id
+}
+
 var pforMinWork = 100
 
 func MaxParallelismForTesting() {
@@ -205,6 +306,47 @@ func sendRecover(c chan<- interface{}) {
 	c <- recover()
 }
 
+// progressStep is how many elements an operation processes
+// between calls of the progress function.
+const progressStep = 1024
+
+// A progress reports the progress of an operation on a vector to the
+// function set by Config.SetProgressFunc. A nil *progress does nothing,
+// so an operation with no one watching pays only for a nil check.
+type progress struct {
+	mu    sync.Mutex
+	fn    func(done, total int)
+	done  int
+	total int
+}
+
+// newProgress returns a progress for an operation on total elements,
+// or nil if there is no progress function or the operation is too
+// short to be worth reporting.
+func newProgress(c Context, total int) *progress {
+	fn := c.Config().ProgressFunc()
+	if fn == nil || total < progressStep {
+		return nil
+	}
+	return &progress{fn: fn, total: total}
+}
+
+// add records that n more elements are done, calling the progress
+// function each time another progressStep elements are done and when
+// all are. It may be called from several goroutines, as by pfor.
+func (p *progress) add(n int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	before := p.done
+	p.done += n
+	if p.done == p.total || p.done/progressStep != before/progressStep {
+		p.fn(p.done, p.total)
+	}
+}
+
 // inner product computes an inner product such as "+.*".
 // u and v are known to be the same type and at least Vectors.
 func innerProduct(c Context, u Value, left, right string, v Value) Value {
@@ -233,7 +375,7 @@ func innerProduct(c Context, u Value, left, right string, v Value) Value {
 		n := v.shape[0]
 		vstride := len(v.data) / n
 		data := make(Vector, len(u.data)/n*vstride)
-		pfor(safeBinary(left) && safeBinary(right), 1, len(data), func(lo, hi int) {
+		pfor(safeBinary(c, left) && safeBinary(c, right), 1, len(data), func(lo, hi int) {
 			for x := lo; x < hi; x++ {
 				i := x / vstride * n
 				j := x % vstride
@@ -267,7 +409,7 @@ func outerProduct(c Context, u Value, op string, v Value) Value {
 			shape: []int{len(u), len(v)},
 			data:  NewVector(make(Vector, len(u)*len(v))),
 		}
-		pfor(safeBinary(op), 1, len(m.data), func(lo, hi int) {
+		pfor(safeBinary(c, op), 1, len(m.data), func(lo, hi int) {
 			for x := lo; x < hi; x++ {
 				m.data[x] = c.EvalBinary(u[x/len(v)], op, v[x%len(v)])
 			}
@@ -281,7 +423,7 @@ func outerProduct(c Context, u Value, op string, v Value) Value {
 		}
 		vdata := v.Data()
 		udata := u.Data()
-		pfor(safeBinary(op), 1, len(m.data), func(lo, hi int) {
+		pfor(safeBinary(c, op), 1, len(m.data), func(lo, hi int) {
 			for x := lo; x < hi; x++ {
 				m.data[x] = c.EvalBinary(udata[x/len(vdata)], op, vdata[x%len(vdata)])
 			}
@@ -297,15 +439,21 @@ func Reduce(c Context, op string, v Value) Value {
 	// We must be right associative; that is the grammar.
 	// -/1 2 3 == 1-2-3 is 1-(2-3) not (1-2)-3. Answer: 2.
 	switch v := v.(type) {
-	case Int, BigInt, BigRat, BigFloat, Complex:
+	case Bool, Int, BigInt, Decimal, BigRat, BigFloat, Complex:
 		return v
 	case Vector:
 		if len(v) == 0 {
-			return v
+			return mustIdentity(c, op)
 		}
+		if op == "+" && v.maxType() == intType && !c.Config().StrictBool() && !c.UserDefined(op, true) {
+			return sumInts(v)
+		}
+		prog := newProgress(c, len(v))
 		acc := v[len(v)-1]
+		prog.add(1)
 		for i := len(v) - 2; i >= 0; i-- {
 			acc = c.EvalBinary(v[i], op, acc)
+			prog.add(1)
 		}
 		return acc
 	case *Matrix:
@@ -313,12 +461,20 @@ func Reduce(c Context, op string, v Value) Value {
 			Errorf("shape for matrix is degenerate: %s", NewIntVector(v.shape))
 		}
 		stride := v.shape[v.Rank()-1]
-		if stride == 0 {
-			Errorf("shape for matrix is degenerate: %s", NewIntVector(v.shape))
-		}
 		shape := v.shape[:v.Rank()-1]
 		data := make(Vector, size(shape))
-		pfor(safeBinary(op), stride, len(data), func(lo, hi int) {
+		if stride == 0 {
+			id := mustIdentity(c, op)
+			for i := range data {
+				data[i] = id
+			}
+			if len(shape) == 1 {
+				return NewVector(data)
+			}
+			return NewMatrix(shape, data)
+		}
+		prog := newProgress(c, len(v.data))
+		pfor(safeBinary(c, op), stride, len(data), func(lo, hi int) {
 			for i := lo; i < hi; i++ {
 				index := stride * i
 				pos := index + stride - 1
@@ -329,6 +485,7 @@ func Reduce(c Context, op string, v Value) Value {
 					pos--
 				}
 				data[i] = acc
+				prog.add(stride)
 			}
 		})
 		if len(shape) == 1 { // TODO: Matrix.shrink()?
@@ -340,12 +497,199 @@ func Reduce(c Context, op string, v Value) Value {
 	panic("not reached")
 }
 
+// Fold computes a reduction such as +/ with an initial value, as in
+// 10 +/ 1 2 3. The slash has been removed. Like Reduce, it is right
+// associative, and the initial value is placed on the right, so
+// 10 -/ 1 2 3 is 1-(2-(3-10)). The fold of an empty vector, or of each
+// row of a matrix with no columns, is the initial value.
+func Fold(c Context, init Value, op string, v Value) Value {
+	if init.Rank() != 0 {
+		Errorf("%s/: initial value must be a scalar", op)
+	}
+	switch v := v.(type) {
+	case Bool, Int, Char, BigInt, Decimal, BigRat, BigFloat, Complex:
+		return c.EvalBinary(v, op, init)
+	case Vector:
+		prog := newProgress(c, len(v))
+		acc := init
+		for i := len(v) - 1; i >= 0; i-- {
+			acc = c.EvalBinary(v[i], op, acc)
+			prog.add(1)
+		}
+		return acc
+	case *Matrix:
+		if v.Rank() < 2 {
+			Errorf("shape for matrix is degenerate: %s", NewIntVector(v.shape))
+		}
+		stride := v.shape[v.Rank()-1]
+		shape := v.shape[:v.Rank()-1]
+		data := make(Vector, size(shape))
+		pfor(safeBinary(c, op), stride, len(data), func(lo, hi int) {
+			for i := lo; i < hi; i++ {
+				acc := init
+				for pos := stride*i + stride - 1; pos >= stride*i; pos-- {
+					acc = c.EvalBinary(v.data[pos], op, acc)
+				}
+				data[i] = acc
+			}
+		})
+		if len(shape) == 1 {
+			return NewVector(data)
+		}
+		return NewMatrix(shape, data)
+	}
+	Errorf("can't do reduce on %s", whichType(v))
+	panic("not reached")
+}
+
+// Each applies the unary operator op to each item of v, as in rot each v.
+// The items of a vector are its elements, and those of a matrix are its
+// subarrays along the first axis, such as the rows of a 2-dimensional
+// matrix. The results are joined by joinItems.
+func Each(c Context, op string, v Value) Value {
+	vi := items(op, v)
+	results := make([]Value, len(vi))
+	for i, x := range vi {
+		results[i] = c.EvalUnary(op, x)
+	}
+	return joinItems(op, results)
+}
+
+// EachBinary applies the binary operator op to corresponding items of u
+// and v, as in u , each v, without extending the operands as the operator
+// itself might. If one operand is a scalar, it is paired with each item of
+// the other. The results are joined by joinItems.
+func EachBinary(c Context, u Value, op string, v Value) Value {
+	var ui, vi []Value
+	switch {
+	case u.Rank() == 0:
+		vi = items(op, v)
+		ui = make([]Value, len(vi))
+		for i := range ui {
+			ui[i] = u
+		}
+	case v.Rank() == 0:
+		ui = items(op, u)
+		vi = make([]Value, len(ui))
+		for i := range vi {
+			vi[i] = v
+		}
+	default:
+		ui, vi = items(op, u), items(op, v)
+		if len(ui) != len(vi) {
+			Errorf("%s each: length mismatch: %d and %d items", op, len(ui), len(vi))
+		}
+	}
+	results := make([]Value, len(ui))
+	for i := range ui {
+		results[i] = c.EvalBinary(ui[i], op, vi[i])
+	}
+	return joinItems(op, results)
+}
+
+// items returns the items of v, as described by Each.
+func items(op string, v Value) []Value {
+	switch v := v.(type) {
+	case Vector:
+		return v
+	case *Matrix:
+		shape := v.shape[1:]
+		n := size(shape)
+		result := make([]Value, v.shape[0])
+		for i := range result {
+			data := make(Vector, n)
+			copy(data, v.data[i*n:])
+			if len(shape) == 1 {
+				result[i] = data
+			} else {
+				result[i] = NewMatrix(shape, data)
+			}
+		}
+		return result
+	}
+	Errorf("%s each: operand must be a vector or matrix, not %s", op, whichType(v))
+	panic("not reached")
+}
+
+// joinItems joins the results of Each or EachBinary into a vector if they
+// are all scalars, or into a matrix with one more dimension if they all
+// have the same shape. Ivy has no nested arrays, so results of differing
+// shapes are an error, except for strings, which are padded with blanks
+// to the length of the longest.
+func joinItems(op string, results []Value) Value {
+	if strs, ok := allStrings(results); ok {
+		results = padStrings(strs)
+	}
+	if len(results) == 0 || results[0].Rank() == 0 {
+		for _, r := range results {
+			if r.Rank() != 0 {
+				Errorf("%s each: results have different shapes", op)
+			}
+		}
+		return NewVector(results)
+	}
+	var shape []int
+	var data Vector
+	for i, r := range results {
+		var rshape []int
+		var rdata Vector
+		switch r := r.(type) {
+		case Vector:
+			rshape, rdata = []int{len(r)}, r
+		case *Matrix:
+			rshape, rdata = r.shape, r.data
+		}
+		if i == 0 {
+			shape = append([]int{len(results)}, rshape...)
+			data = make(Vector, 0, len(results)*len(rdata))
+		} else if !sameShape(shape[1:], rshape) {
+			Errorf("%s each: results have different shapes", op)
+		}
+		data = append(data, rdata...)
+	}
+	return NewMatrix(shape, data)
+}
+
+// allStrings reports whether the values are all non-empty
+// vectors of chars, and if so returns them as Vectors.
+func allStrings(values []Value) ([]Vector, bool) {
+	strs := make([]Vector, len(values))
+	for i, v := range values {
+		s, ok := v.(Vector)
+		if !ok || len(s) == 0 || !s.AllChars() {
+			return nil, false
+		}
+		strs[i] = s
+	}
+	return strs, true
+}
+
+// padStrings returns the strings padded with blanks to a common length.
+func padStrings(strs []Vector) []Value {
+	max := 0
+	for _, s := range strs {
+		if len(s) > max {
+			max = len(s)
+		}
+	}
+	padded := make([]Value, len(strs))
+	for i, s := range strs {
+		p := make(Vector, max)
+		copy(p, s)
+		for j := len(s); j < max; j++ {
+			p[j] = Char(' ')
+		}
+		padded[i] = p
+	}
+	return padded
+}
+
 // Scan computes a scan of the op; the \ has been removed.
 // It gives the successive values of reducing op through v.
 // We must be right associative; that is the grammar.
 func Scan(c Context, op string, v Value) Value {
 	switch v := v.(type) {
-	case Int, BigInt, BigRat, BigFloat, Complex:
+	case Bool, Int, BigInt, Decimal, BigRat, BigFloat, Complex:
 		return v
 	case Vector:
 		if len(v) == 0 {
@@ -356,10 +700,14 @@ func Scan(c Context, op string, v Value) Value {
 		// We make it O(n) for known associative ops.
 		values[0] = v[0]
 		if knownAssoc(op) {
+			prog := newProgress(c, len(v))
+			prog.add(1)
 			for i := 1; i < len(v); i++ {
 				values[i] = c.EvalBinary(values[i-1], op, v[i])
+				prog.add(1)
 			}
 		} else {
+			// Each reduction reports its own progress.
 			for i := 1; i < len(v); i++ {
 				values[i] = Reduce(c, op, v[:i+1])
 			}
@@ -379,7 +727,7 @@ func Scan(c Context, op string, v Value) Value {
 			// Guaranteed by NewMatrix not to overflow.
 			nrows *= v.shape[i]
 		}
-		pfor(safeBinary(op), stride, nrows, func(lo, hi int) {
+		pfor(safeBinary(c, op), stride, nrows, func(lo, hi int) {
 			for i := lo; i < hi; i++ {
 				index := i * stride
 				// This is fundamentally O(n²) in the general case.
@@ -402,11 +750,65 @@ func Scan(c Context, op string, v Value) Value {
 	panic("not reached")
 }
 
+// ExclusiveScan computes an exclusive scan of the op; the \\ has been
+// removed. Element k of the result is the reduction of the elements
+// before element k, so the first element is the identity of the op,
+// and +\\ 1 2 3 is 0 1 3. It is an error if the op has no identity.
+func ExclusiveScan(c Context, op string, v Value) Value {
+	id := identity(c, op)
+	if id == nil {
+		Errorf("exclusive scan: %s has no identity element", op)
+	}
+	switch v := v.(type) {
+	case Bool, Int, BigInt, Decimal, BigRat, BigFloat, Complex:
+		return id
+	case Vector:
+		values := make(Vector, len(v))
+		exclusiveScan(c, op, id, values, v)
+		return NewVector(values)
+	case *Matrix:
+		if v.Rank() < 2 {
+			Errorf("shape for matrix is degenerate: %s", NewIntVector(v.shape))
+		}
+		stride := v.shape[v.Rank()-1]
+		data := make(Vector, len(v.data))
+		if stride == 0 {
+			return NewMatrix(v.shape, data)
+		}
+		nrows := len(v.data) / stride
+		pfor(safeBinary(c, op), stride, nrows, func(lo, hi int) {
+			for i := lo; i < hi; i++ {
+				index := i * stride
+				exclusiveScan(c, op, id, data[index:index+stride], v.data[index:index+stride])
+			}
+		})
+		return NewMatrix(v.shape, data)
+	}
+	Errorf("can't do scan on %s", whichType(v))
+	panic("not reached")
+}
+
+// exclusiveScan stores the exclusive scan of src in dst.
+func exclusiveScan(c Context, op string, id Value, dst, src Vector) {
+	if len(dst) == 0 {
+		return
+	}
+	dst[0] = id
+	// As for Scan, this is O(n) only for known associative ops.
+	for i := 1; i < len(dst); i++ {
+		if knownAssoc(op) {
+			dst[i] = c.EvalBinary(dst[i-1], op, src[i-1])
+		} else {
+			dst[i] = Reduce(c, op, src[:i])
+		}
+	}
+}
+
 // unaryVectorOp applies op elementwise to i.
 func unaryVectorOp(c Context, op string, i Value) Value {
 	u := i.(Vector)
 	n := make([]Value, len(u))
-	pfor(safeUnary(op), 1, len(n), func(lo, hi int) {
+	pfor(safeUnary(c, op), 1, len(n), func(lo, hi int) {
 		for k := lo; k < hi; k++ {
 			n[k] = c.EvalUnary(op, u[k])
 		}
@@ -418,7 +820,7 @@ func unaryVectorOp(c Context, op string, i Value) Value {
 func unaryMatrixOp(c Context, op string, i Value) Value {
 	u := i.(*Matrix)
 	n := make([]Value, len(u.data))
-	pfor(safeUnary(op), 1, len(n), func(lo, hi int) {
+	pfor(safeUnary(c, op), 1, len(n), func(lo, hi int) {
 		for k := lo; k < hi; k++ {
 			n[k] = c.EvalUnary(op, u.data[k])
 		}
@@ -431,27 +833,40 @@ func binaryVectorOp(c Context, i Value, op string, j Value) Value {
 	u, v := i.(Vector), j.(Vector)
 	if len(u) == 1 {
 		n := make([]Value, len(v))
-		pfor(safeBinary(op), 1, len(n), func(lo, hi int) {
+		prog := newProgress(c, len(n))
+		pfor(safeBinary(c, op), 1, len(n), func(lo, hi int) {
 			for k := lo; k < hi; k++ {
 				n[k] = c.EvalBinary(u[0], op, v[k])
+				prog.add(1)
 			}
 		})
 		return NewVector(n)
 	}
 	if len(v) == 1 {
 		n := make([]Value, len(u))
-		pfor(safeBinary(op), 1, len(n), func(lo, hi int) {
+		prog := newProgress(c, len(n))
+		pfor(safeBinary(c, op), 1, len(n), func(lo, hi int) {
 			for k := lo; k < hi; k++ {
 				n[k] = c.EvalBinary(u[k], op, v[0])
+				prog.add(1)
 			}
 		})
 		return NewVector(n)
 	}
-	u.sameLength(v)
+	if len(u) != len(v) {
+		hint := ""
+		switch op {
+		case "<", "<=", "==", "!=", ">=", ">":
+			hint = "; use match to compare whole values or lexcmp to order them"
+		}
+		Errorf("%s: length mismatch: %d %d%s", op, len(u), len(v), hint)
+	}
 	n := make([]Value, len(u))
-	pfor(safeBinary(op), 1, len(n), func(lo, hi int) {
+	prog := newProgress(c, len(n))
+	pfor(safeBinary(c, op), 1, len(n), func(lo, hi int) {
 		for k := lo; k < hi; k++ {
 			n[k] = c.EvalBinary(u[k], op, v[k])
+			prog.add(1)
 		}
 	})
 	return NewVector(n)
@@ -469,7 +884,7 @@ func binaryMatrixOp(c Context, i Value, op string, j Value) Value {
 		// Scalar op Matrix.
 		shape = v.shape
 		n = make([]Value, len(v.data))
-		pfor(safeBinary(op), 1, len(n), func(lo, hi int) {
+		pfor(safeBinary(c, op), 1, len(n), func(lo, hi int) {
 			for k := lo; k < hi; k++ {
 				n[k] = c.EvalBinary(u.data[0], op, v.data[k])
 			}
@@ -477,7 +892,7 @@ func binaryMatrixOp(c Context, i Value, op string, j Value) Value {
 	case isScalar(v):
 		// Matrix op Scalar.
 		n = make([]Value, len(u.data))
-		pfor(safeBinary(op), 1, len(n), func(lo, hi int) {
+		pfor(safeBinary(c, op), 1, len(n), func(lo, hi int) {
 			for k := lo; k < hi; k++ {
 				n[k] = c.EvalBinary(u.data[k], op, v.data[0])
 			}
@@ -487,7 +902,7 @@ func binaryMatrixOp(c Context, i Value, op string, j Value) Value {
 		shape = v.shape
 		n = make([]Value, len(v.data))
 		dim := u.shape[0]
-		pfor(safeBinary(op), 1, len(n), func(lo, hi int) {
+		pfor(safeBinary(c, op), 1, len(n), func(lo, hi int) {
 			for k := lo; k < hi; k++ {
 				n[k] = c.EvalBinary(u.data[k%dim], op, v.data[k])
 			}
@@ -496,7 +911,7 @@ func binaryMatrixOp(c Context, i Value, op string, j Value) Value {
 		// Matrix op Vector.
 		n = make([]Value, len(u.data))
 		dim := v.shape[0]
-		pfor(safeBinary(op), 1, len(n), func(lo, hi int) {
+		pfor(safeBinary(c, op), 1, len(n), func(lo, hi int) {
 			for k := lo; k < hi; k++ {
 				n[k] = c.EvalBinary(u.data[k], op, v.data[k%dim])
 			}
@@ -505,7 +920,7 @@ func binaryMatrixOp(c Context, i Value, op string, j Value) Value {
 		// Matrix op Matrix.
 		u.sameShape(v)
 		n = make([]Value, len(u.data))
-		pfor(safeBinary(op), 1, len(n), func(lo, hi int) {
+		pfor(safeBinary(c, op), 1, len(n), func(lo, hi int) {
 			for k := lo; k < hi; k++ {
 				n[k] = c.EvalBinary(u.data[k], op, v.data[k])
 			}
@@ -545,6 +960,8 @@ func isZero(v Value) bool {
 		return v == 0
 	case BigInt:
 		return v.Sign() == 0
+	case Decimal:
+		return v.Sign() == 0
 	case BigRat:
 		return v.Sign() == 0
 	case BigFloat:
@@ -562,6 +979,8 @@ func isNegative(v Value) bool {
 		return v < 0
 	case BigInt:
 		return v.Sign() < 0
+	case Decimal:
+		return v.Sign() < 0
 	case BigRat:
 		return v.Sign() < 0
 	case BigFloat:
@@ -588,6 +1007,8 @@ func compare(v Value, i int) int {
 	case BigInt:
 		r := big.NewInt(int64(i))
 		return -r.Sub(r, v.Int).Sign()
+	case Decimal:
+		return compare(v.rat(), i)
 	case BigRat:
 		r := big.NewRat(int64(i), 1)
 		return -r.Sub(r, v.Rat).Sign()
@@ -604,12 +1025,16 @@ func compare(v Value, i int) int {
 // a scalar, an error results.
 func isTrue(fnName string, v Value) bool {
 	switch i := v.(type) {
+	case Bool:
+		return bool(i)
 	case Char:
 		return i != 0
 	case Int:
 		return i != 0
 	case BigInt:
 		return true // If it's a BigInt, it can't be 0 - that's an Int.
+	case Decimal:
+		return i.Sign() != 0
 	case BigRat:
 		return true // If it's a BigRat, it can't be 0 - that's an Int.
 	case BigFloat:
@@ -31,6 +31,12 @@ func fmtText(c Context, u, v Value) Value {
 		Errorf("illegal format %q", u.Sprint(config))
 	}
 	var b bytes.Buffer
+	switch d := v.(type) {
+	case Bool:
+		v = d.toInt()
+	case Decimal:
+		v = d.rat()
+	}
 	switch val := v.(type) {
 	case Int, BigInt, BigRat, BigFloat, Char:
 		formatOne(c, &b, format, verb, val)
@@ -55,14 +61,48 @@ func fmtText(c Context, u, v Value) Value {
 	default:
 		Errorf("cannot format '%s'", val.Sprint(config))
 	}
-	str := b.String()
-	elem := make([]Value, utf8.RuneCountInString(str))
-	for i, r := range str {
+	return chars(b.String())
+}
+
+// fmtValue implements fmt. If u is an integer, it is the base in which
+// to print v, as with )obase, using the same code that prints values;
+// otherwise it is a format, as for text. The result is a vector of Chars.
+func fmtValue(c Context, u, v Value) Value {
+	base, ok := u.(Int)
+	if !ok {
+		return fmtText(c, u, v)
+	}
+	if base < 2 || 16 < base {
+		Errorf("fmt: illegal base %d", base)
+	}
+	conf := c.Config().Clone()
+	ibase, _ := conf.Base()
+	conf.SetBase(ibase, int(base))
+	conf.SetFormat("")
+	return chars(v.Sprint(conf))
+}
+
+// chars returns a vector of the Chars of s.
+func chars(s string) Vector {
+	elem := make([]Value, utf8.RuneCountInString(s))
+	for i, r := range s {
 		elem[i] = Char(r)
 	}
 	return NewVector(elem)
 }
 
+// decimalText returns s, a number formatted in floating-point style
+// with a '.' decimal point, with the point replaced by the decimal
+// separator of the configuration. It is shared by all the printers of
+// rationals and floats so they agree, in fixed and scientific notation.
+func decimalText(conf *config.Config, s string) string {
+	sep := conf.DecimalSeparator()
+	if sep == '.' {
+		return s
+	}
+	return strings.Replace(s, ".", string(sep), 1)
+}
+
 // formatString returns the format string given u, the lhs of a binary text invocation.
 func formatString(c *config.Config, u Value) (string, byte) {
 	switch val := u.(type) {
@@ -0,0 +1,191 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// hashThreshold is the length of the searched vector above which
+// membership and index-of use a hash table rather than sorting.
+// Below it, the cost of computing keys outweighs the benefit.
+const hashThreshold = 32
+
+// A hashKey identifies an exact scalar. Two exact scalars
+// have the same key if and only if they are equal.
+type hashKey struct {
+	kind valueType // intType for all integers that fit in an int64.
+	i    int64
+	s    string // Bytes of the big.Int, or num/den of the big.Rat in decimal.
+}
+
+// keyOf returns the hash key for v and reports whether v has one.
+// Values are normalized first, so 2, 2/1 and BigInt(2) all have the same
+// key. Only Chars and exact numbers (Int, BigInt, BigRat) have keys;
+// floats are excluded because equality of floats is not exact.
+func keyOf(v Value) (hashKey, bool) {
+	switch v := v.(type) {
+	case Int:
+		return hashKey{kind: intType, i: int64(v)}, true
+	case Char:
+		return hashKey{kind: charType, i: int64(v)}, true
+	case BigInt:
+		return bigIntKey(v.Int), true
+	case BigRat:
+		if v.IsInt() {
+			return bigIntKey(v.Num()), true
+		}
+		// The decimal digits contain no slash, unlike the bytes of
+		// the numerator, so the key cannot be ambiguous.
+		return hashKey{kind: bigRatType, s: v.Num().String() + "/" + v.Denom().String()}, true
+	}
+	return hashKey{}, false
+}
+
+func bigIntKey(b *big.Int) hashKey {
+	if b.IsInt64() {
+		return hashKey{kind: intType, i: b.Int64()}
+	}
+	// Bytes is the absolute value, so record the sign separately.
+	return hashKey{kind: bigIntType, i: int64(b.Sign()), s: string(b.Bytes())}
+}
+
+// keysOf returns the hash keys for the elements of v, or nil
+// if any element does not have one.
+func keysOf(v []Value) []hashKey {
+	keys := make([]hashKey, len(v))
+	for i, x := range v {
+		k, ok := keyOf(x)
+		if !ok {
+			return nil
+		}
+		keys[i] = k
+	}
+	return keys
+}
+
+// Key returns a string that identifies v, for use as a map key, and
+// reports whether v has one. Exact scalars and chars have keys, as do
+// vectors and matrices of at most hashThreshold of them. Two values have
+// the same key if and only if they have the same shape and their elements
+// are equal. A key is never a prefix of another, so keys may be joined.
+func Key(v Value) (string, bool) {
+	var shape []int
+	var elems []Value
+	switch v := v.(type) {
+	case Vector:
+		shape, elems = []int{len(v)}, v
+	case *Matrix:
+		shape, elems = v.Shape(), v.Data()
+	default:
+		elems = []Value{v}
+	}
+	if len(elems) > hashThreshold {
+		return "", false
+	}
+	b := strconv.AppendInt(nil, int64(len(shape)), 10)
+	for _, n := range shape {
+		b = append(b, ',')
+		b = strconv.AppendInt(b, int64(n), 10)
+	}
+	for _, elem := range elems {
+		if x, ok := elem.(Bool); ok {
+			elem = toInt(bool(x))
+		}
+		k, ok := keyOf(elem)
+		if !ok {
+			return "", false
+		}
+		b = append(b, ';', byte('0'+k.kind))
+		b = strconv.AppendInt(b, k.i, 10)
+		b = append(b, ',')
+		b = strconv.AppendInt(b, int64(len(k.s)), 10)
+		b = append(b, ',')
+		b = append(b, k.s...)
+	}
+	return string(b), true
+}
+
+// hashMembership is the hashed implementation of membership.
+// It returns nil if some element of u or v cannot be hashed.
+func hashMembership(u, v Vector) []Value {
+	vKeys := keysOf(v)
+	if vKeys == nil {
+		return nil
+	}
+	uKeys := keysOf(u)
+	if uKeys == nil {
+		return nil
+	}
+	set := make(map[hashKey]bool, len(vKeys))
+	for _, k := range vKeys {
+		set[k] = true
+	}
+	values := make([]Value, len(u))
+	for i, k := range uKeys {
+		values[i] = toInt(set[k])
+	}
+	return values
+}
+
+// hashIndexOf is the hashed implementation of A iota B for vectors.
+// It returns nil if some element of a or b cannot be hashed.
+func hashIndexOf(a, b Vector, origin int) []Value {
+	aKeys := keysOf(a)
+	if aKeys == nil {
+		return nil
+	}
+	bKeys := keysOf(b)
+	if bKeys == nil {
+		return nil
+	}
+	first := make(map[hashKey]int, len(aKeys))
+	for i := len(aKeys) - 1; i >= 0; i-- {
+		first[aKeys[i]] = i + origin
+	}
+	indices := make([]Value, len(b))
+	for i, k := range bKeys {
+		if index, ok := first[k]; ok {
+			indices[i] = Int(index)
+		} else {
+			indices[i] = Int(origin - 1)
+		}
+	}
+	return indices
+}
+
+// unique returns the distinct elements of v in order of first appearance.
+func (v Vector) unique(c Context) Vector {
+	var result []Value
+	if keys := keysOf(v); len(v) > hashThreshold && keys != nil {
+		seen := make(map[hashKey]bool, len(keys))
+		for i, k := range keys {
+			if !seen[k] {
+				seen[k] = true
+				result = append(result, v[i])
+			}
+		}
+		return NewVector(result)
+	}
+Outer:
+	for _, x := range v {
+		for _, y := range result {
+			if compatible(x, y) && holds(c, x, "==", y) {
+				continue Outer
+			}
+		}
+		result = append(result, x)
+	}
+	return NewVector(result)
+}
+
+// compatible reports whether x and y can be compared with ==:
+// Chars compare only with Chars.
+func compatible(x, y Value) bool {
+	_, xChar := x.(Char)
+	_, yChar := y.(Char)
+	return xChar == yChar
+}
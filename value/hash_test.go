@@ -0,0 +1,113 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value_test
+
+import (
+	"math/big"
+	"testing"
+
+	"robpike.io/ivy/value"
+)
+
+// twos returns a vector of length n that is long enough to be hashed,
+// ending in three representations of 2 that must hash identically.
+func twos(n int) value.Vector {
+	v := make([]value.Value, n)
+	for i := range v {
+		v[i] = value.Int(100 + i)
+	}
+	v[n-3] = value.Int(2)
+	v[n-2] = value.BigRat{Rat: big.NewRat(4, 2)}
+	v[n-1] = value.BigInt{Int: big.NewInt(2)}
+	return value.NewVector(v)
+}
+
+func TestHashNormalization(t *testing.T) {
+	c := newContext()
+	for _, n := range []int{5, 1000} {
+		v := twos(n)
+		probe := value.NewVector([]value.Value{
+			value.BigInt{Int: big.NewInt(2)},
+			value.BigRat{Rat: big.NewRat(2, 1)},
+			value.Int(2),
+			value.Int(3),
+		})
+		if got, want := sprint(c, c.EvalBinary(probe, "in", v)), "1 1 1 0"; got != want {
+			t.Errorf("len %d: in: got %q, want %q", n, got, want)
+		}
+		want := value.Int(n - 2) // Origin 1; first of the three 2s.
+		got := c.EvalBinary(v, "iota", probe).(value.Vector)
+		for i := 0; i < 3; i++ {
+			if got[i] != want {
+				t.Errorf("len %d: iota: element %d is %v, want %v", n, i, got[i], want)
+			}
+		}
+		if u := c.EvalUnary("unique", v).(value.Vector); len(u) != n-2 {
+			t.Errorf("len %d: unique: got %d elements, want %d", n, len(u), n-2)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	c := newContext()
+	key := func(v value.Value) string {
+		t.Helper()
+		k, ok := value.Key(v)
+		if !ok {
+			t.Fatalf("no key for %s", sprint(c, v))
+		}
+		return k
+	}
+	two := key(value.Int(2))
+	for _, v := range []value.Value{value.BigInt{Int: big.NewInt(2)}, value.BigRat{Rat: big.NewRat(4, 2)}} {
+		if k := key(v); k != two {
+			t.Errorf("key of %s is %q, want %q", sprint(c, v), k, two)
+		}
+	}
+	distinct := []value.Value{
+		value.Int(2),
+		value.Char('2'),
+		value.NewIntVector([]int{2}),
+		value.NewIntVector([]int{2, 2}),
+		value.NewIntVector([]int{22}),
+		value.NewIntVector(nil),
+		value.BigRat{Rat: big.NewRat(1, 2)},
+		value.BigRat{Rat: big.NewRat(-1, 2)},
+		value.BigRat{Rat: big.NewRat(303, 2)},
+		value.BigRat{Rat: big.NewRat(1, 12034)}, // Numerator bytes of 303/2 include '/'.
+		value.NewMatrix([]int{1, 2}, value.NewIntVector([]int{2, 2})),
+		value.NewMatrix([]int{2, 1}, value.NewIntVector([]int{2, 2})),
+	}
+	seen := map[string]int{}
+	for i, v := range distinct {
+		k := key(v)
+		if j, ok := seen[k]; ok {
+			t.Errorf("%s and %s have the same key %q", sprint(c, distinct[j]), sprint(c, v), k)
+		}
+		seen[k] = i
+	}
+	for _, v := range []value.Value{
+		c.EvalUnary("sqrt", value.Int(2)),
+		value.NewIntVector(make([]int, 1000)),
+	} {
+		if k, ok := value.Key(v); ok {
+			t.Errorf("key for %s is %q, want none", sprint(c, v), k)
+		}
+	}
+}
+
+func BenchmarkMembership(b *testing.B) {
+	c := newContext()
+	v := make([]int64, 1e6)
+	for i := range v {
+		v[i] = int64(i) * 3
+	}
+	u := value.IntVector(v)
+	w := value.IntVector(v[:len(v)/2])
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.EvalBinary(u, "in", w)
+	}
+}
@@ -4,7 +4,101 @@
 
 package value
 
-import "sync"
+import (
+	"strings"
+	"sync"
+)
+
+// A Slice is an index of the form start:stop or start:stop:step, which
+// selects the elements from start to stop inclusive, counting by step.
+// Start and stop are indexes, so depend on the index origin. Any part
+// may be nil: the step defaults to 1, and the start and stop to the ends
+// of the dimension, the first and last elements if the step is positive
+// and the last and first if it is negative. A start or stop beyond the
+// ends of the dimension is an error, as with any index, but a slice may
+// be empty: in origin 1, x[3:2] has no elements.
+type Slice struct {
+	Start, Stop, Step Expr
+}
+
+func (s *Slice) ProgString() string {
+	var b strings.Builder
+	for i, x := range []Expr{s.Start, s.Stop, s.Step} {
+		if i > 0 {
+			if x == nil && i == 2 {
+				break
+			}
+			b.WriteString(":")
+		}
+		if x != nil {
+			b.WriteString(x.ProgString())
+		}
+	}
+	return b.String()
+}
+
+func (s *Slice) Eval(Context) Value {
+	Errorf("slice %s outside index", s.ProgString())
+	panic("not reached")
+}
+
+// sliceBounds holds the evaluated parts of a Slice; nil means absent.
+type sliceBounds struct {
+	start, stop, step Value
+}
+
+// evalBounds evaluates the parts of the slice, right to left, and
+// checks that each is an Int.
+func (s *Slice) evalBounds(context Context, top Expr) *sliceBounds {
+	eval := func(x Expr) Value {
+		if x == nil {
+			return nil
+		}
+		v := x.Eval(context).Inner()
+		if _, ok := v.(Int); !ok {
+			Errorf("invalid slice bound %s (%s) in %s", x.ProgString(), whichType(v), top.ProgString())
+		}
+		return v
+	}
+	var b sliceBounds
+	b.step = eval(s.Step)
+	b.stop = eval(s.Stop)
+	b.start = eval(s.Start)
+	if b.step == Int(0) {
+		Errorf("zero step in slice %s in %s", s.ProgString(), top.ProgString())
+	}
+	return &b
+}
+
+// indexes returns the indexes selected by the slice in a dimension
+// of length n.
+func (b *sliceBounds) indexes(origin, n int, top Expr) Vector {
+	step := 1
+	if b.step != nil {
+		step = int(b.step.(Int))
+	}
+	first, last := origin, origin+n-1
+	if step < 0 {
+		first, last = last, first
+	}
+	bound := func(v Value, def int) int {
+		if v == nil {
+			return def
+		}
+		i := int(v.(Int))
+		if i < origin || i >= origin+n {
+			Errorf("slice bound %d out of range for length %d in %s", i, n, top.ProgString())
+		}
+		return i
+	}
+	start := bound(b.start, first)
+	stop := bound(b.stop, last)
+	var x []Value
+	for i := start; step > 0 && i <= stop || step < 0 && i >= stop; i += step {
+		x = append(x, Int(i))
+	}
+	return NewVector(x)
+}
 
 // An indexState holds the state needed to locate
 // the values denoted by an index expression left[index],
@@ -31,10 +125,18 @@ func (ix *indexState) init(context Context, top, left Expr, index []Expr) {
 	ix.indexes = make([]Vector, len(index))
 	ix.outShape = nil          // common case - scalar indexes covering entire rank → scalar result
 	var outShapeToUpdate []int // indexes of outShape entries that need updating after lhs eval.
+	var slices []*sliceBounds  // Evaluated slices, by position in index; nil if there are none.
 	for i := len(index) - 1; i >= 0; i-- {
-		if index[i] == nil {
-			// Make this iota(dimension), to be filled in after evaluating lhs.
+		slice, isSlice := index[i].(*Slice)
+		if index[i] == nil || isSlice {
+			// Make this iota(dimension) or the slice of it, to be filled in after evaluating lhs.
 			ix.indexes[i] = nil
+			if isSlice {
+				if slices == nil {
+					slices = make([]*sliceBounds, len(index))
+				}
+				slices[i] = slice.evalBounds(context, top)
+			}
 			outShapeToUpdate = append(outShapeToUpdate, len(ix.outShape))
 			ix.outShape = append(ix.outShape, 0) // Fixed below, after we have evaluated left.
 			continue
@@ -91,12 +193,19 @@ func (ix *indexState) init(context Context, top, left Expr, index []Expr) {
 	if len(ix.indexes) > len(ix.shape) {
 		Errorf("too many dimensions in %s indexing shape %v", top.ProgString(), NewIntVector(ix.shape))
 	}
-	// Replace nil index entries, created above, with iota(dimension).
+	// Replace nil index entries, created above, with iota(dimension)
+	// or the slice of it. The entries of outShapeToUpdate were recorded
+	// right to left, so walk the indexes in that order too.
 	j := 0
-	for i, v := range ix.indexes {
-		if v == nil {
-			x := constIota(int(origin), ix.shape[i])
-			ix.indexes[i] = NewVector(x)
+	for i := len(ix.indexes) - 1; i >= 0; i-- {
+		if ix.indexes[i] == nil {
+			var x Vector
+			if slices != nil && slices[i] != nil {
+				x = slices[i].indexes(int(origin), ix.shape[i], top)
+			} else {
+				x = NewVector(constIota(int(origin), ix.shape[i]))
+			}
+			ix.indexes[i] = x
 			ix.outShape[outShapeToUpdate[j]] = len(x)
 			j++
 		}
@@ -38,7 +38,7 @@ func (i Int) Sprint(conf *config.Config) string {
 	if format != "" {
 		verb, prec, ok := conf.FloatFormat()
 		if ok {
-			return i.floatString(verb, prec)
+			return decimalText(conf, i.floatString(verb, prec))
 		}
 		return fmt.Sprintf(format, int64(i))
 	}
@@ -53,6 +53,10 @@ func (i Int) ProgString() string {
 	return strconv.FormatInt(int64(i), 10)
 }
 
+func (i Int) Repr() string {
+	return i.ProgString()
+}
+
 func (i Int) Rank() int {
 	return 0
 }
@@ -146,6 +150,8 @@ func (i Int) toType(op string, conf *config.Config, which valueType) Value {
 		return i
 	case bigIntType:
 		return bigInt64(int64(i))
+	case decimalType:
+		return Decimal{bigInt64(int64(i)).Int, 0}
 	case bigRatType:
 		return bigRatInt64(int64(i))
 	case bigFloatType:
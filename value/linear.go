@@ -0,0 +1,153 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import "math/big"
+
+// Linear algebra on matrices, in exact rational arithmetic. Gaussian
+// elimination over big.Rat never rounds, so the results are exact
+// whatever the size of the numbers; floats are converted exactly first.
+
+// ratSquare returns the square matrix m as rows of rationals, copied
+// so they may be updated in place. Op names the operator for errors.
+func ratSquare(c Context, op string, m *Matrix) [][]*big.Rat {
+	shape := m.Shape()
+	if len(shape) != 2 || shape[0] != shape[1] {
+		Errorf("%s: matrix must be square; shape is %s", op, NewIntVector(shape))
+	}
+	n := shape[0]
+	rows := make([][]*big.Rat, n)
+	for i := range rows {
+		rows[i] = make([]*big.Rat, n)
+		for j := range rows[i] {
+			rows[i][j] = new(big.Rat).Set(exactRat(c, op, m.Data()[i*n+j]))
+		}
+	}
+	return rows
+}
+
+// pivot finds a row at or below k with a non-zero element in column k
+// and swaps it into row k. It reports whether there was one, and whether
+// rows were swapped.
+func pivot(rows [][]*big.Rat, k int) (found, swapped bool) {
+	for i := k; i < len(rows); i++ {
+		if rows[i][k].Sign() != 0 {
+			rows[k], rows[i] = rows[i], rows[k]
+			return true, i != k
+		}
+	}
+	return false, false
+}
+
+// eliminate subtracts multiples of row k from each row from row first
+// on, other than row k itself, so the element in column k becomes zero.
+// Row k must have a non-zero element in column k.
+func eliminate(rows [][]*big.Rat, k, first int) {
+	f := new(big.Rat)
+	t := new(big.Rat)
+	for i := first; i < len(rows); i++ {
+		row := rows[i]
+		if i == k || row[k].Sign() == 0 {
+			continue
+		}
+		f.Quo(row[k], rows[k][k])
+		for j := k; j < len(row); j++ {
+			row[j].Sub(row[j], t.Mul(f, rows[k][j]))
+		}
+	}
+}
+
+// determinant returns the determinant of the square matrix m.
+func determinant(c Context, m *Matrix) Value {
+	rows := ratSquare(c, "det", m)
+	det := big.NewRat(1, 1)
+	for k := range rows {
+		found, swapped := pivot(rows, k)
+		if !found {
+			return Int(0)
+		}
+		if swapped {
+			det.Neg(det)
+		}
+		// Only the rows below need clearing to reach triangular form.
+		eliminate(rows, k, k+1)
+		det.Mul(det, rows[k][k])
+	}
+	return BigRat{det}.shrink()
+}
+
+// inverse returns the inverse of the square matrix m. It reduces m,
+// with the identity matrix alongside, to the identity, which turns
+// the identity into the inverse.
+func inverse(c Context, m *Matrix) Value {
+	rows := ratSquare(c, "inverse", m)
+	n := len(rows)
+	for i, row := range rows {
+		for j := 0; j < n; j++ {
+			row = append(row, new(big.Rat))
+		}
+		row[n+i].SetInt64(1)
+		rows[i] = row
+	}
+	for k := range rows {
+		if found, _ := pivot(rows, k); !found {
+			Errorf("inverse: matrix is singular")
+		}
+		eliminate(rows, k, 0)
+	}
+	data := make(Vector, 0, n*n)
+	for k, row := range rows {
+		for _, x := range row[n:] {
+			data = append(data, BigRat{x.Quo(x, row[k])}.shrink())
+		}
+	}
+	return NewMatrix([]int{n, n}, data)
+}
+
+// solve returns the solution x of the system of linear equations
+// a +.* x == b, where a is a square matrix. If b is a vector, so is x;
+// if b is a matrix, x is a matrix whose columns solve for the columns
+// of b.
+func solve(c Context, a *Matrix, b Value) Value {
+	rows := ratSquare(c, "solve", a)
+	n := len(rows)
+	var shape []int
+	var data Vector
+	switch b := b.(type) {
+	case Vector:
+		shape, data = []int{len(b)}, b
+	case *Matrix:
+		shape, data = b.Shape(), b.Data()
+	}
+	if len(shape) > 2 || shape[0] != n {
+		Errorf("solve: right operand has shape %s; must have %d rows", NewIntVector(shape), n)
+	}
+	k := 1 // Number of columns of b.
+	if len(shape) == 2 {
+		k = shape[1]
+	}
+	for i, row := range rows {
+		for _, x := range data[i*k : (i+1)*k] {
+			row = append(row, new(big.Rat).Set(exactRat(c, "solve", x)))
+		}
+		rows[i] = row
+	}
+	for j := range rows {
+		if found, _ := pivot(rows, j); !found {
+			Errorf("solve: matrix is singular")
+		}
+		eliminate(rows, j, 0)
+	}
+	x := make(Vector, 0, len(data))
+	for j, row := range rows {
+		for _, y := range row[n:] {
+			x = append(x, BigRat{y.Quo(y, row[j])}.shrink())
+		}
+	}
+	if len(shape) == 1 {
+		return x
+	}
+	return NewMatrix(shape, x)
+}
@@ -128,7 +128,7 @@ func floatLog(c Context, x *big.Float) *big.Float {
 	mantissa := newFloat(c)
 	exp2 := x.MantExp(mantissa)
 	exp := newFloat(c).SetInt64(int64(exp2))
-	exp.Mul(exp, floatLog2)
+	exp.Mul(exp, consts(c.Config()).log2)
 	if invert {
 		exp.Neg(exp)
 	}
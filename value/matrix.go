@@ -10,6 +10,7 @@ import (
 	"io"
 	"math/bits"
 	"sort"
+	"strconv"
 	"strings"
 
 	"robpike.io/ivy/config"
@@ -123,6 +124,7 @@ func (m *Matrix) String() string {
 }
 
 func (m *Matrix) Sprint(conf *config.Config) string {
+	conf = conf.Snapshot() // Read the settings once, not for every element.
 	var b bytes.Buffer
 	switch m.Rank() {
 	case 0:
@@ -145,12 +147,9 @@ func (m *Matrix) Sprint(conf *config.Config) string {
 			}
 			break
 		}
-		// We print the elements into one big string,
-		// slice that, and then format so they line up.
-		// Will need some rethinking when decimal points
-		// can appear.
-		// Vector.String does what we want for the first part.
-		strs := strings.Split(m.data.makeString(conf, true), " ")
+		// We print the elements individually
+		// and then format them so they line up.
+		strs := m.data.elemStrings(conf)
 		wid := 1
 		for _, s := range strs {
 			if wid < len(s) {
@@ -175,7 +174,7 @@ func (m *Matrix) Sprint(conf *config.Config) string {
 		}
 		// As for 2d: print the vector elements, compute the
 		// global width, and use that to print each 2d submatrix.
-		strs := strings.Split(m.data.Sprint(conf), " ")
+		strs := m.data.elemStrings(conf)
 		wid := 1
 		for _, s := range strs {
 			if wid < len(s) {
@@ -207,6 +206,20 @@ func (m *Matrix) ProgString() string {
 	panic("matrix.ProgString - cannot happen")
 }
 
+// Repr returns the shape reshaping the data. An empty matrix
+// reshapes 0, as rho cannot reshape an empty vector.
+func (m *Matrix) Repr() string {
+	shape := make([]string, len(m.shape))
+	for i, n := range m.shape {
+		shape[i] = strconv.Itoa(n)
+	}
+	data := "0"
+	if len(m.data) > 0 {
+		data = m.data.Repr()
+	}
+	return strings.Join(shape, " ") + " rho " + data
+}
+
 func (m *Matrix) higherDim(conf *config.Config, prefix string, indentation int) string {
 	if m.Rank() <= 3 {
 		return indent(indentation, m.Sprint(conf))
@@ -588,6 +601,32 @@ func (x *Matrix) catenate(y *Matrix) *Matrix {
 	return NewMatrix(shape, data)
 }
 
+// pick returns, for each element of the mask v, which must be 0 or 1,
+// the corresponding element of the first row of m if it is 1, or of
+// the second row if it is 0. The matrix must have two rows with as
+// many elements as v.
+func (m *Matrix) pick(c Context, v Vector) Vector {
+	if len(m.shape) != 2 || m.shape[0] != 2 {
+		Errorf("pick: right operand must have two rows; shape is %s", NewIntVector(m.shape))
+	}
+	n := m.shape[1]
+	if len(v) != n {
+		Errorf("pick: mask has %d elements; rows have %d", len(v), n)
+	}
+	result := make([]Value, n)
+	for i, x := range boolCounts(v) {
+		switch x {
+		case Int(1):
+			result[i] = m.data[i]
+		case Int(0):
+			result[i] = m.data[n+i]
+		default:
+			Errorf("pick: mask must be 0 or 1, not %s", x.Sprint(c.Config()))
+		}
+	}
+	return NewVector(result)
+}
+
 // sel returns the selection of m according to v.
 // The selection applies to the final axis.
 func (m *Matrix) sel(c Context, v Vector) *Matrix {
@@ -746,13 +785,7 @@ func (m *Matrix) grade(c Context) Vector {
 	sort.Slice(x, func(i, j int) bool {
 		i = x[i] * stride
 		j = x[j] * stride
-		for k := 0; k < stride; k++ {
-			if toBool(c.EvalBinary(v[i+k], "==", v[j+k])) {
-				continue
-			}
-			return toBool(c.EvalBinary(v[i+k], "<", v[j+k]))
-		}
-		return false
+		return lexCompare(c, v[i:i+stride], v[j:j+stride]) < 0
 	})
 	origin := c.Config().Origin()
 	for i := range x {
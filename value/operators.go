@@ -0,0 +1,203 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// OpInfo describes a built-in operator.
+type OpInfo struct {
+	Name   string
+	Binary bool     // Whether this describes the binary form of the operator.
+	Types  []string // The types of operand the operator accepts, such as "int" or "vector".
+	Help   string   // The documentation for the operator, if any.
+	// Identity is the identity element of a binary operator, the value
+	// x for which y op x is y, or nil if it has none. It is the result of
+	// reducing an empty vector with the operator, as in +/ on no values.
+	Identity Value
+}
+
+// OperatorHelp returns the documentation for the unary or binary
+// form of the named operator. It is installed by package parse, which
+// holds the documentation; it is nil if that package is not linked in.
+var OperatorHelp func(name string, binary bool) string
+
+// Operators returns a description of every built-in operator, including
+// those added by RegisterUnary and RegisterBinary, sorted by name.
+// The unary form of an operator is listed before its binary form.
+func Operators() []OpInfo {
+	var ops []OpInfo
+	for name, op := range UnaryOps {
+		ops = append(ops, opInfo(name, false, func(t valueType) bool {
+			switch op := op.(type) {
+			case *unaryOp:
+				return op.fn[t] != nil || op.elementwise && t >= vectorType
+			}
+			return true
+		}))
+	}
+	for name, op := range BinaryOps {
+		info := opInfo(name, true, func(t valueType) bool {
+			switch op := op.(type) {
+			case *binaryOp:
+				if op.whichType == nil {
+					return true
+				}
+				_, which := op.whichType(t, t)
+				return op.fn[which] != nil || op.elementwise && which >= vectorType
+			}
+			return true
+		})
+		if op, ok := op.(*binaryOp); ok {
+			info.Identity = op.identity
+		}
+		ops = append(ops, info)
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Name != ops[j].Name {
+			return ops[i].Name < ops[j].Name
+		}
+		return !ops[i].Binary && ops[j].Binary
+	})
+	return ops
+}
+
+func opInfo(name string, binary bool, accepts func(valueType) bool) OpInfo {
+	info := OpInfo{
+		Name:   name,
+		Binary: binary,
+	}
+	for t := boolType; t < numType; t++ {
+		if accepts(t) {
+			info.Types = append(info.Types, t.String())
+		}
+	}
+	if OperatorHelp != nil {
+		info.Help = OperatorHelp(name, binary)
+	}
+	return info
+}
+
+// IsOperator reports whether name is a built-in unary or binary operator.
+func IsOperator(name string) bool {
+	return UnaryOps[name] != nil || BinaryOps[name] != nil
+}
+
+// RegisterUnary adds fn as the built-in unary operator with the given
+// name, replacing any existing unary operator of that name. The function
+// is called with the operand as is, without type conversion, and is
+// responsible for handling vectors and matrices. The name may be a word,
+// like rho, or a sequence of symbols, like <=>; the scanner recognizes
+// symbolic names by looking for the longest one registered.
+//
+// A name may be registered as both a unary and a binary operator,
+// as - is built in as both negation and subtraction. The parser
+// chooses the form by position: the binary form when the operator
+// has an operand on its left, the unary form otherwise.
+//
+// Operators must be registered before evaluation starts. Elementwise
+// operations on large vectors may call fn from several goroutines at
+// once, so it must be safe for concurrent use.
+func RegisterUnary(name string, fn func(c Context, v Value) Value) {
+	UnaryOps[name] = unaryFunc(fn)
+}
+
+// RegisterBinary adds fn as the built-in binary operator with the given
+// name, replacing any existing binary operator of that name. The function
+// is called with the operands as they are, without type conversion, and is
+// responsible for handling vectors and matrices. See RegisterUnary
+// for operators that have both forms.
+func RegisterBinary(name string, fn func(c Context, u, v Value) Value) {
+	BinaryOps[name] = binaryFunc(fn)
+}
+
+type unaryFunc func(c Context, v Value) Value
+
+func (fn unaryFunc) EvalUnary(c Context, v Value) Value {
+	return fn(c, v)
+}
+
+type binaryFunc func(c Context, u, v Value) Value
+
+func (fn binaryFunc) EvalBinary(c Context, u, v Value) Value {
+	return fn(c, u, v)
+}
+
+// aplSymbols maps the APL symbols and mathematical signs that may be
+// used in place of operator names to the names of the operators' unary
+// and binary forms, as listed in the APL column of the documentation.
+// An empty name means the symbol has no such form in ivy.
+var aplSymbols = map[rune]struct{ unary, binary string }{
+	'×': {"sgn", "*"},
+	'÷': {"/", "/"},
+	'−': {"-", "-"},
+	'⋆': {"**", "**"},
+	'⍟': {"log", "log"},
+	'⌈': {"ceil", "max"},
+	'⌊': {"floor", "min"},
+	'∣': {"abs", ""}, // APL's residue, A∣B, is B mod A.
+	'⍳': {"iota", "iota"},
+	'⍴': {"rho", "rho"},
+	'⌽': {"rot", "rot"},
+	'⊖': {"flip", "flip"},
+	'⍉': {"transp", "transp"},
+	'⍋': {"up", ""},
+	'⍒': {"down", ""},
+	'∪': {"unique", ""},
+	'↑': {"head", "take"},
+	'↓': {"", "drop"},
+	'∈': {"", "in"},
+	'⊥': {"", "decode"},
+	'⊤': {"", "encode"},
+	'⍎': {"ivy", ""},
+	'⍕': {"text", "text"},
+	'∼': {"not", ""},
+	'∧': {"", "and"},
+	'∨': {"", "or"},
+	'⍲': {"", "nand"},
+	'⍱': {"", "nor"},
+	'≤': {"", "<="},
+	'≥': {"", ">="},
+	'≠': {"", "!="},
+}
+
+// IsSymbol reports whether r is an APL symbol that may be used
+// in place of the name of an operator.
+func IsSymbol(r rune) bool {
+	_, ok := aplSymbols[r]
+	return ok
+}
+
+// OperatorName returns the name of the operator spelled as op, which
+// may contain APL symbols, as in ×/ or +.×. If unary is set and op is a
+// single symbol, the result is the name of its unary form; otherwise
+// symbols are replaced by the names of their binary forms, as is
+// appropriate in a reduction or product. If a symbol has no form to
+// replace it with, op is returned unchanged.
+func OperatorName(op string, unary bool) string {
+	if strings.IndexFunc(op, IsSymbol) < 0 {
+		return op
+	}
+	var b strings.Builder
+	for _, r := range op {
+		names, ok := aplSymbols[r]
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+		name := names.binary
+		if unary && len(op) == utf8.RuneLen(r) {
+			name = names.unary
+		}
+		if name == "" {
+			return op
+		}
+		b.WriteString(name)
+	}
+	return b.String()
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value_test
+
+import (
+	"strings"
+	"testing"
+
+	_ "robpike.io/ivy/parse" // Installs value.OperatorHelp.
+	"robpike.io/ivy/value"
+)
+
+func findOp(name string, binary bool) (value.OpInfo, bool) {
+	for _, op := range value.Operators() {
+		if op.Name == name && op.Binary == binary {
+			return op, true
+		}
+	}
+	return value.OpInfo{}, false
+}
+
+func TestOperators(t *testing.T) {
+	tests := []struct {
+		name   string
+		binary bool
+		types  string
+		help   string
+	}{
+		{"+", true, "int big int decimal rational float complex vector matrix", "Sum of A and B"},
+		{"==", true, "bool int char big int decimal rational float complex vector matrix", "Equal"},
+		{"idiv", true, "int big int vector matrix", "A divided by B (Go)"},
+		{"iota", false, "int", "Vector of the first B integers"},
+		{"rho", false, "bool int char big int rational float complex vector matrix", "Number of components"},
+	}
+	for _, test := range tests {
+		op, ok := findOp(test.name, test.binary)
+		if !ok {
+			t.Errorf("%s (binary %t) not found", test.name, test.binary)
+			continue
+		}
+		if got := strings.Join(op.Types, " "); got != test.types {
+			t.Errorf("%s (binary %t): types %q, want %q", test.name, test.binary, got, test.types)
+		}
+		if !strings.Contains(op.Help, test.help) {
+			t.Errorf("%s (binary %t): help %q does not contain %q", test.name, test.binary, op.Help, test.help)
+		}
+	}
+	for _, test := range []struct {
+		name     string
+		identity string
+	}{
+		{"+", "0"},
+		{"*", "1"},
+		{"and", "1"},
+		{"max", ""},
+		{"rho", ""},
+	} {
+		op, _ := findOp(test.name, true)
+		got := ""
+		if op.Identity != nil {
+			got = sprint(newContext(), op.Identity)
+		}
+		if got != test.identity {
+			t.Errorf("identity of %s is %q, want %q", test.name, got, test.identity)
+		}
+	}
+	if !value.IsOperator("rho") || value.IsOperator("rhombus") {
+		t.Errorf("IsOperator is wrong")
+	}
+}
+
+func TestRegisterOperator(t *testing.T) {
+	value.RegisterUnary("twice", func(c value.Context, v value.Value) value.Value {
+		return c.EvalBinary(value.Int(2), "*", v)
+	})
+	defer delete(value.UnaryOps, "twice")
+	if !value.IsOperator("twice") {
+		t.Fatal("twice is not an operator after registration")
+	}
+	op, ok := findOp("twice", false)
+	if !ok {
+		t.Fatal("twice not found in Operators")
+	}
+	if len(op.Types) == 0 || op.Help != "" {
+		t.Errorf("twice: unexpected info %+v", op)
+	}
+	c := newContext()
+	if got := sprint(c, c.EvalUnary("twice", value.IntVector([]int64{1, 2}))); got != "2 4" {
+		t.Errorf("twice 1 2 = %q, want %q", got, "2 4")
+	}
+}
+
+func TestOperatorName(t *testing.T) {
+	tests := []struct {
+		op    string
+		unary bool
+		name  string
+	}{
+		{"×", false, "*"},
+		{"×", true, "sgn"},
+		{"⌈", true, "ceil"},
+		{"⌈", false, "max"},
+		{"⌈/", true, "max/"},
+		{"+.×", false, "+.*"},
+		{"≤", false, "<="},
+		{"≤", true, "≤"}, // No unary form.
+		{"÷", true, "/"},
+		{"−", false, "-"},
+		{"∨.∧", false, "or.and"},
+		{"↓", true, "↓"},
+		{"∣", false, "∣"}, // Residue has its operands reversed.
+		{"+", true, "+"},
+		{"iota", true, "iota"},
+	}
+	for _, test := range tests {
+		if got := value.OperatorName(test.op, test.unary); got != test.name {
+			t.Errorf("OperatorName(%q, %t) = %q, want %q", test.op, test.unary, got, test.name)
+		}
+	}
+}
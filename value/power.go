@@ -63,7 +63,7 @@ func floatPower(c Context, bx, bexp BigFloat) *big.Float {
 	// Easy cases.
 	switch {
 	case x.Cmp(floatOne) == 0, x.Sign() == 0:
-		return x
+		return newFloat(c).Set(x)
 	case fexp.Cmp(floatHalf) == 0:
 		z := floatSqrt(c, x)
 		if !positive {
@@ -0,0 +1,44 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import "math/big"
+
+// The random operators. Both draw from the configured random source and
+// count from the index origin, so the "first n integers" are 0 through n-1
+// in origin 0 and 1 through n in origin 1.
+
+// roll implements unary ?n: a single integer chosen uniformly
+// from the first n integers. In particular, ?1 is always the origin.
+func roll(c Context, n Int) Value {
+	if n <= 0 {
+		Errorf("illegal roll value %v", n)
+	}
+	return Int(c.Config().Origin()) + Int(c.Config().Random().Int63n(int64(n)))
+}
+
+// bigIntRand is roll for BigInts.
+func bigIntRand(c Context, a, b *big.Int) *big.Int {
+	a.Rand(c.Config().Random(), b)
+	return a.Add(a, c.Config().BigOrigin())
+}
+
+// deal implements binary a?b: a vector of a distinct integers chosen
+// uniformly from the first b integers, in random order.
+func deal(c Context, a, b Int) Value {
+	if uint64(a) > maxInt || uint64(b) > maxInt {
+		Errorf("negative or too-large operand in %d?%d", a, b)
+	}
+	if a > b {
+		Errorf("left operand larger than right in %d?%d", a, b)
+	}
+	ints := c.Config().Random().Perm(int(b))
+	origin := c.Config().Origin()
+	res := make([]Value, a)
+	for i := range res {
+		res[i] = Int(ints[i] + origin)
+	}
+	return NewVector(res)
+}
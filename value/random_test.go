@@ -0,0 +1,99 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value_test
+
+import (
+	"math/big"
+	"testing"
+
+	"robpike.io/ivy/value"
+)
+
+// inRange reports whether v is an integer in [lo, lo+n).
+func inRange(v value.Value, lo, n int64) bool {
+	var x *big.Int
+	switch v := v.(type) {
+	case value.Int:
+		x = big.NewInt(int64(v))
+	case value.BigInt:
+		x = v.Int
+	default:
+		return false
+	}
+	return x.Cmp(big.NewInt(lo)) >= 0 && x.Cmp(big.NewInt(lo+n)) < 0
+}
+
+func TestRollOrigin(t *testing.T) {
+	c := newContext()
+	c.Config().SetRandomSeed(1)
+	for _, origin := range []int{0, 1} {
+		c.Config().SetOrigin(origin)
+		for _, n := range []int64{1, 2, 6, 1e10} {
+			var arg value.Value = value.Int(n)
+			if n > 1<<31 {
+				arg = value.BigInt{Int: big.NewInt(n)}
+			}
+			sawLo, sawHi := false, false
+			for i := 0; i < 1000; i++ {
+				v := c.EvalUnary("?", arg)
+				if !inRange(v, int64(origin), n) {
+					t.Fatalf("origin %d: ?%d = %v, out of range", origin, n, v)
+				}
+				sawLo = sawLo || sprint(c, v) == sprint(c, value.Int(origin))
+				sawHi = sawHi || inRange(v, int64(origin)+n-1, 1)
+			}
+			if n <= 6 && !(sawLo && sawHi) {
+				t.Errorf("origin %d: ?%d never produced both ends of the range", origin, n)
+			}
+		}
+		// ?1 has only one possible value.
+		for i := 0; i < 100; i++ {
+			if v := c.EvalUnary("?", value.Int(1)); v != value.Int(origin) {
+				t.Fatalf("origin %d: ?1 = %v", origin, v)
+			}
+		}
+	}
+}
+
+func TestRollErrors(t *testing.T) {
+	c := newContext()
+	for _, v := range []value.Value{value.Int(0), value.Int(-3), value.BigInt{Int: big.NewInt(-1e10)}} {
+		if err := catch(func() { c.EvalUnary("?", v) }); err == nil {
+			t.Errorf("?%v: no error", v)
+		}
+	}
+}
+
+func TestDealOrigin(t *testing.T) {
+	c := newContext()
+	c.Config().SetRandomSeed(1)
+	for _, origin := range []int{0, 1} {
+		c.Config().SetOrigin(origin)
+		for i := 0; i < 100; i++ {
+			v := c.EvalBinary(value.Int(5), "?", value.Int(8)).(value.Vector)
+			if len(v) != 5 {
+				t.Fatalf("origin %d: 5?8 has length %d", origin, len(v))
+			}
+			seen := make(map[value.Value]bool)
+			for _, x := range v {
+				if !inRange(x, int64(origin), 8) || seen[x] {
+					t.Fatalf("origin %d: 5?8 = %s: bad element %v", origin, sprint(c, v), x)
+				}
+				seen[x] = true
+			}
+		}
+		// Dealing all of them is a permutation.
+		v := c.EvalBinary(value.Int(3), "?", value.Int(3)).(value.Vector)
+		seen := make(map[value.Value]bool)
+		for _, x := range v {
+			seen[x] = true
+		}
+		for j := 0; j < 3; j++ {
+			if !seen[value.Int(origin+j)] {
+				t.Errorf("origin %d: 3?3 = %s, missing %d", origin, sprint(c, v), origin+j)
+			}
+		}
+	}
+}
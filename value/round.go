@@ -0,0 +1,40 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"math/big"
+
+	"robpike.io/ivy/config"
+)
+
+// roundRat returns x rounded to an integer as specified by mode.
+func roundRat(x *big.Rat, mode config.RoundingMode) *big.Int {
+	i := ratRound(x, 0, mode)
+	if x.Sign() < 0 {
+		i.Neg(i)
+	}
+	return i
+}
+
+// floatRat returns the exact rational value of the float v.
+func floatRat(c Context, op string, v Value) *big.Rat {
+	f := v.(BigFloat)
+	if f.IsInf() {
+		Errorf("%s of %s", op, v.Sprint(c.Config()))
+	}
+	r, _ := f.Rat(nil)
+	return r
+}
+
+// roundTo returns x rounded to the nearest multiple of q, with
+// halves rounded to the even multiple.
+func roundTo(x, q *big.Rat) *big.Rat {
+	if q.Sign() <= 0 {
+		Errorf("roundto: quantum must be positive")
+	}
+	n := roundRat(new(big.Rat).Quo(x, q), config.RoundHalfEven)
+	return new(big.Rat).Mul(new(big.Rat).SetInt(n), q)
+}
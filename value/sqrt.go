@@ -82,3 +82,53 @@ func floatSqrt(c Context, x *big.Float) *big.Float {
 	}
 	return z
 }
+
+// sqrtn implements d sqrtn x, the square root of x truncated to d
+// decimal places, as an exact rational. It works in integers: for x
+// a rational p/q, the result is isqrt(floor(p*10**(2*d)/q)) / 10**d,
+// which is floor(sqrt(x)*10**d) / 10**d.
+func sqrtn(c Context, u, v Value) Value {
+	d := wordInt(c, "sqrtn", "digit count", u)
+	if d.Sign() < 0 || !d.IsInt64() || d.Int64() >= maxInt {
+		Errorf("sqrtn: illegal digit count %s", u.Sprint(c.Config()))
+	}
+	digits := d.Int64()
+	mustFit(c.Config(), digits*10/3) // 10**d has about 3.32*d bits.
+	x := exactRat(c, "sqrtn", v)
+	if x.Sign() < 0 {
+		Errorf("sqrtn: square root of negative number")
+	}
+	scale := new(big.Int).Exp(bigIntTen, big.NewInt(digits), nil)
+	n := new(big.Int).Mul(x.Num(), scale)
+	n.Mul(n, scale)
+	n.Quo(n, x.Denom())
+	n.Sqrt(n) // Newton's method in integers.
+	return BigRat{new(big.Rat).SetFrac(n, scale)}.shrink()
+}
+
+// exactRat returns the value of the real number v as a big.Rat,
+// exactly, even for a float. The result may share storage with v.
+// Op names the operator for errors.
+func exactRat(c Context, op string, v Value) *big.Rat {
+	switch v := v.(type) {
+	case Bool:
+		return big.NewRat(int64(v.toInt()), 1)
+	case Int:
+		return big.NewRat(int64(v), 1)
+	case BigInt:
+		return new(big.Rat).SetInt(v.Int)
+	case Decimal:
+		return v.rat().Rat
+	case BigRat:
+		return v.Rat
+	case BigFloat:
+		r, _ := v.Rat(nil)
+		return r
+	case Complex:
+		if v.isReal() {
+			return exactRat(c, op, v.real)
+		}
+	}
+	Errorf("%s: bad operand %s", op, whichType(v))
+	panic("not reached")
+}
@@ -6,7 +6,9 @@ package value
 
 import (
 	"math/big"
-	"unicode/utf8"
+	"math/bits"
+
+	"robpike.io/ivy/config"
 )
 
 // Unary operators.
@@ -50,23 +52,165 @@ func bigFloatWrap(op func(*big.Float, *big.Float) *big.Float) func(Context, *big
 	}
 }
 
-// bigIntRand sets a to a random number in [origin, origin+b].
-func bigIntRand(c Context, a, b *big.Int) *big.Int {
-	a.Rand(c.Config().Random(), b)
-	return a.Add(a, c.Config().BigOrigin())
+// hasComplex reports whether any element of v is complex.
+func hasComplex(v Vector) bool {
+	for _, x := range v {
+		if _, ok := x.(Complex); ok {
+			return true
+		}
+	}
+	return false
 }
 
 func self(c Context, v Value) Value {
 	return v
 }
 
+// extreme returns the largest or smallest of the elements,
+// according to op, which is "max" or "min".
+func extreme(c Context, op string, elems Vector) Value {
+	if len(elems) == 0 {
+		Errorf("%s of empty value", op)
+	}
+	x := elems[0]
+	for _, elem := range elems[1:] {
+		x = c.EvalBinary(x, op, elem)
+	}
+	return x
+}
+
+// extremePos returns the index of the first of the largest or smallest
+// of the elements, according to op, which is "maxpos" or "minpos".
+func extremePos(c Context, op string, elems Vector) Value {
+	nonEmpty(op, elems)
+	cmp := "<"
+	if op == "maxpos" {
+		cmp = ">"
+	}
+	k := 0
+	for i, elem := range elems[1:] {
+		if holds(c, elem, cmp, elems[k]) {
+			k = i + 1
+		}
+	}
+	return Int(k + c.Config().Origin())
+}
+
+// differences returns the differences of the adjacent elements of v,
+// each element minus the one before it, a vector one shorter than v.
+func differences(c Context, v Vector) Value {
+	if len(v) < 2 {
+		return Vector{}
+	}
+	d := make(Vector, len(v)-1)
+	for i := range d {
+		d[i] = c.EvalBinary(v[i+1], "-", v[i])
+	}
+	return NewVector(d)
+}
+
+// returnOrigin returns the index origin, the index of
+// the only element of a scalar.
+func returnOrigin(c Context, v Value) Value {
+	return Int(c.Config().Origin())
+}
+
+// returnEmpty returns an empty vector: a scalar
+// without its only element.
+func returnEmpty(c Context, v Value) Value {
+	return Vector{}
+}
+
+// nonEmpty returns v, or errors if it is empty.
+func nonEmpty(op string, v Vector) Vector {
+	if len(v) == 0 {
+		Errorf("%s of empty vector", op)
+	}
+	return v
+}
+
 func returnZero(c Context, v Value) Value {
 	return Int(0)
 }
 
+// popcount returns the number of one bits in i, which must not be negative,
+// as a negative number has infinitely many in two's complement.
+func popcount(i *big.Int) Value {
+	if i.Sign() < 0 {
+		Errorf("popcount of negative number %s", i)
+	}
+	n := 0
+	for _, w := range i.Bits() {
+		n += bits.OnesCount(uint(w))
+	}
+	return Int(n)
+}
+
+// toBits returns the binary digits of i, which must not be negative,
+// most significant first. Zero has the single digit 0.
+func toBits(i *big.Int) Value {
+	if i.Sign() < 0 {
+		Errorf("tobits of negative number %s", i)
+	}
+	n := i.BitLen()
+	if n == 0 {
+		return NewVector([]Value{Int(0)})
+	}
+	digits := make([]Value, n)
+	for k := range digits {
+		digits[k] = Int(i.Bit(n - 1 - k))
+	}
+	return NewVector(digits)
+}
+
+// fromBits returns the integer whose binary digits, most significant
+// first, are the elements of v, each 0 or 1. It is the inverse of toBits.
+func fromBits(c Context, v Vector) Value {
+	mustFit(c.Config(), int64(len(v)))
+	z := bigInt64(0)
+	for _, x := range v {
+		z.Lsh(z.Int, 1)
+		switch x {
+		case Int(0), Bool(false):
+		case Int(1), Bool(true):
+			z.SetBit(z.Int, 0, 1)
+		default:
+			Errorf("frombits: %s is not a bit", x.Sprint(c.Config()))
+		}
+	}
+	return z.shrink()
+}
+
+func returnOne(c Context, v Value) Value {
+	return Int(1)
+}
+
+// mixedScalar returns the whole part of v, truncated toward zero, and
+// the remaining proper fraction, which has the sign of v, so that their
+// sum is v: mixed 7/3 is 2 1/3 and mixed -7/3 is -2 -1/3.
+func mixedScalar(c Context, v Value) Value {
+	r, ok := v.(BigRat)
+	if !ok {
+		return NewVector([]Value{v, Int(0)})
+	}
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	frac := BigRat{new(big.Rat).SetFrac(m, r.Denom())}
+	return NewVector([]Value{BigInt{q}.shrink(), frac.shrink()})
+}
+
+// mixedArray returns the mixed forms of the elements of an array with
+// the given shape and data, as a matrix with a final axis of length 2.
+func mixedArray(c Context, shape []int, data Vector) Value {
+	result := make(Vector, 0, 2*len(data))
+	for _, x := range data {
+		result = append(result, c.EvalUnary("mixed", x).(Vector)...)
+	}
+	return NewMatrix(append(append([]int{}, shape...), 2), result)
+}
+
 func realPhase(c Context, v Value) Value {
 	if isNegative(v) {
-		return BigFloat{newFloat(c).Set(floatPi)}
+		return BigFloat{newFloat(c).Set(consts(c.Config()).pi)}
 	}
 	return Int(0)
 }
@@ -89,6 +233,11 @@ func floatValueSelf(c Context, v Value) Value {
 }
 
 // floatSelf promotes v to type BigFloat.
+// decimalSelf converts v, a real number, to a Decimal.
+func decimalSelf(c Context, v Value) Value {
+	return toDecimal(c.Config(), v)
+}
+
 func floatSelf(c Context, v Value) BigFloat {
 	conf := c.Config()
 	switch v := v.(type) {
@@ -96,6 +245,8 @@ func floatSelf(c Context, v Value) BigFloat {
 		return v.toType("float", conf, bigFloatType).(BigFloat)
 	case BigInt:
 		return v.toType("float", conf, bigFloatType).(BigFloat)
+	case Decimal:
+		return v.toType("float", conf, bigFloatType).(BigFloat)
 	case BigRat:
 		return v.toType("float", conf, bigFloatType).(BigFloat)
 	case BigFloat:
@@ -108,12 +259,12 @@ func floatSelf(c Context, v Value) BigFloat {
 // text returns a vector of Chars holding the string representation
 // of the value.
 func text(c Context, v Value) Value {
-	str := v.Sprint(c.Config())
-	elem := make([]Value, utf8.RuneCountInString(str))
-	for i, r := range str {
-		elem[i] = Char(r)
-	}
-	return NewVector(elem)
+	return chars(v.Sprint(c.Config()))
+}
+
+// typeOf returns the name of the type of the value as a vector of Chars.
+func typeOf(c Context, v Value) Value {
+	return chars(TypeWord(v))
 }
 
 // Implemented in package run, handled as a func to avoid a dependency loop.
@@ -140,11 +291,7 @@ func init() {
 			elementwise: true,
 			fn: [numType]unaryFn{
 				intType: func(c Context, v Value) Value {
-					i := int64(v.(Int))
-					if i <= 0 {
-						Errorf("illegal roll value %v", v)
-					}
-					return Int(c.Config().Origin()) + Int(c.Config().Random().Int63n(i))
+					return roll(c, v.(Int))
 				},
 				bigIntType: func(c Context, v Value) Value {
 					if v.(BigInt).Sign() <= 0 {
@@ -182,13 +329,28 @@ func init() {
 		{
 			name: "+",
 			fn: [numType]unaryFn{
+				boolType:     self,
 				intType:      self,
+				charType:     self,
 				bigIntType:   self,
+				decimalType:  self,
 				bigRatType:   self,
 				bigFloatType: self,
-				complexType:  self,
-				vectorType:   self,
-				matrixType:   self,
+				complexType: func(c Context, v Value) Value {
+					return v.(Complex).conj(c)
+				},
+				vectorType: func(c Context, v Value) Value {
+					if !hasComplex(v.(Vector)) {
+						return v
+					}
+					return unaryVectorOp(c, "+", v)
+				},
+				matrixType: func(c Context, v Value) Value {
+					if !hasComplex(v.(*Matrix).data) {
+						return v
+					}
+					return unaryMatrixOp(c, "+", v)
+				},
 			},
 		},
 
@@ -197,11 +359,15 @@ func init() {
 			elementwise: true,
 			fn: [numType]unaryFn{
 				intType: func(c Context, v Value) Value {
-					return -v.(Int)
+					// The negation of the most negative Int is not an Int.
+					return (-v.(Int)).maybeBig()
 				},
 				bigIntType: func(c Context, v Value) Value {
 					return unaryBigIntOp(c, bigIntWrap((*big.Int).Neg), v)
 				},
+				decimalType: func(c Context, v Value) Value {
+					return v.(Decimal).neg()
+				},
 				bigRatType: func(c Context, v Value) Value {
 					return unaryBigRatOp((*big.Rat).Neg, v)
 				},
@@ -308,35 +474,38 @@ func init() {
 			name:        "not",
 			elementwise: true,
 			fn: [numType]unaryFn{
+				boolType: func(c Context, v Value) Value {
+					return boolValue(c, !bool(v.(Bool)))
+				},
 				intType: func(c Context, v Value) Value {
 					if v.(Int) == 0 {
-						return one
+						return boolValue(c, true)
 					}
-					return zero
+					return boolValue(c, false)
 				},
 				bigIntType: func(c Context, v Value) Value {
 					if v.(BigInt).Sign() == 0 {
-						return one
+						return boolValue(c, true)
 					}
-					return zero
+					return boolValue(c, false)
 				},
 				bigRatType: func(c Context, v Value) Value {
 					if v.(BigRat).Sign() == 0 {
-						return one
+						return boolValue(c, true)
 					}
-					return zero
+					return boolValue(c, false)
 				},
 				bigFloatType: func(c Context, v Value) Value {
 					if v.(BigFloat).Sign() == 0 {
-						return one
+						return boolValue(c, true)
 					}
-					return zero
+					return boolValue(c, false)
 				},
 				complexType: func(c Context, v Value) Value {
 					if isZero(v) {
-						return one
+						return boolValue(c, true)
 					}
-					return zero
+					return boolValue(c, false)
 				},
 			},
 		},
@@ -350,11 +519,17 @@ func init() {
 					if i < 0 {
 						i = -i
 					}
-					return i
+					return i.maybeBig()
 				},
 				bigIntType: func(c Context, v Value) Value {
 					return unaryBigIntOp(c, bigIntWrap((*big.Int).Abs), v)
 				},
+				decimalType: func(c Context, v Value) Value {
+					if d := v.(Decimal); d.Sign() < 0 {
+						return d.neg()
+					}
+					return v
+				},
 				bigRatType: func(c Context, v Value) Value {
 					return unaryBigRatOp((*big.Rat).Abs, v)
 				},
@@ -373,6 +548,7 @@ func init() {
 			fn: [numType]unaryFn{
 				intType:      self,
 				bigIntType:   self,
+				decimalType:  self,
 				bigRatType:   self,
 				bigFloatType: self,
 				complexType: func(c Context, v Value) Value {
@@ -381,12 +557,114 @@ func init() {
 			},
 		},
 
+		{
+			name:        "popcount",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType: func(c Context, v Value) Value {
+					i := v.(Int)
+					if i < 0 {
+						Errorf("popcount of negative number %d", i)
+					}
+					return Int(bits.OnesCount64(uint64(i)))
+				},
+				bigIntType: func(c Context, v Value) Value {
+					return popcount(v.(BigInt).Int)
+				},
+			},
+		},
+
+		{
+			name:        "bitlength",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType: func(c Context, v Value) Value {
+					i := int64(v.(Int))
+					if i < 0 {
+						i = -i // Overflow is harmless; the conversion below makes it 1<<63.
+					}
+					return Int(bits.Len64(uint64(i)))
+				},
+				bigIntType: func(c Context, v Value) Value {
+					return Int(v.(BigInt).BitLen())
+				},
+			},
+		},
+
+		{
+			name: "tobits",
+			fn: [numType]unaryFn{
+				intType: func(c Context, v Value) Value {
+					return toBits(big.NewInt(int64(v.(Int))))
+				},
+				bigIntType: func(c Context, v Value) Value {
+					return toBits(v.(BigInt).Int)
+				},
+			},
+		},
+
+		{
+			name: "frombits",
+			fn: [numType]unaryFn{
+				boolType: func(c Context, v Value) Value {
+					return fromBits(c, NewVector([]Value{v}))
+				},
+				intType: func(c Context, v Value) Value {
+					return fromBits(c, NewVector([]Value{v}))
+				},
+				vectorType: func(c Context, v Value) Value {
+					return fromBits(c, v.(Vector))
+				},
+			},
+		},
+
+		{
+			name:        "num",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:    self,
+				bigIntType: self,
+				bigRatType: func(c Context, v Value) Value {
+					return BigInt{new(big.Int).Set(v.(BigRat).Num())}.shrink()
+				},
+			},
+		},
+
+		{
+			name:        "den",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:    returnOne,
+				bigIntType: returnOne,
+				bigRatType: func(c Context, v Value) Value {
+					return BigInt{new(big.Int).Set(v.(BigRat).Denom())}.shrink()
+				},
+			},
+		},
+
+		{
+			name: "mixed",
+			fn: [numType]unaryFn{
+				intType:    mixedScalar,
+				bigIntType: mixedScalar,
+				bigRatType: mixedScalar,
+				vectorType: func(c Context, v Value) Value {
+					return mixedArray(c, []int{len(v.(Vector))}, v.(Vector))
+				},
+				matrixType: func(c Context, v Value) Value {
+					m := v.(*Matrix)
+					return mixedArray(c, m.shape, m.data)
+				},
+			},
+		},
+
 		{
 			name:        "imag",
 			elementwise: true,
 			fn: [numType]unaryFn{
 				intType:      returnZero,
 				bigIntType:   returnZero,
+				decimalType:  returnZero,
 				bigRatType:   returnZero,
 				bigFloatType: returnZero,
 				complexType: func(c Context, v Value) Value {
@@ -401,6 +679,7 @@ func init() {
 			fn: [numType]unaryFn{
 				intType:      realPhase,
 				bigIntType:   realPhase,
+				decimalType:  realPhase,
 				bigRatType:   realPhase,
 				bigFloatType: realPhase,
 				complexType: func(c Context, v Value) Value {
@@ -496,6 +775,36 @@ func init() {
 			},
 		},
 
+		{
+			name:        "round",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:    self,
+				bigIntType: self,
+				bigRatType: func(c Context, v Value) Value {
+					return BigInt{roundRat(v.(BigRat).Rat, config.RoundHalfEven)}.shrink()
+				},
+				bigFloatType: func(c Context, v Value) Value {
+					return BigInt{roundRat(floatRat(c, "round", v), config.RoundHalfEven)}.shrink()
+				},
+			},
+		},
+
+		{
+			name:        "trunc",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:    self,
+				bigIntType: self,
+				bigRatType: func(c Context, v Value) Value {
+					return BigInt{roundRat(v.(BigRat).Rat, config.RoundTowardZero)}.shrink()
+				},
+				bigFloatType: func(c Context, v Value) Value {
+					return BigInt{roundRat(floatRat(c, "trunc", v), config.RoundTowardZero)}.shrink()
+				},
+			},
+		},
+
 		{
 			name: "iota",
 			fn: [numType]unaryFn{
@@ -518,6 +827,9 @@ func init() {
 		{
 			name: "rho",
 			fn: [numType]unaryFn{
+				boolType: func(c Context, v Value) Value {
+					return Int(0)
+				},
 				intType: func(c Context, v Value) Value {
 					return Int(0)
 				},
@@ -548,9 +860,11 @@ func init() {
 		{
 			name: ",",
 			fn: [numType]unaryFn{
+				boolType:     vectorSelf,
 				intType:      vectorSelf,
 				charType:     vectorSelf,
 				bigIntType:   vectorSelf,
+				decimalType:  vectorSelf,
 				bigRatType:   vectorSelf,
 				bigFloatType: vectorSelf,
 				complexType:  vectorSelf,
@@ -564,9 +878,11 @@ func init() {
 		{
 			name: "up",
 			fn: [numType]unaryFn{
+				boolType:     self,
 				intType:      self,
 				charType:     self,
 				bigIntType:   self,
+				decimalType:  self,
 				bigRatType:   self,
 				bigFloatType: self,
 				vectorType: func(c Context, v Value) Value {
@@ -578,12 +894,121 @@ func init() {
 			},
 		},
 
+		{
+			name: "unique",
+			fn: [numType]unaryFn{
+				boolType:     vectorSelf,
+				intType:      vectorSelf,
+				charType:     vectorSelf,
+				bigIntType:   vectorSelf,
+				decimalType:  vectorSelf,
+				bigRatType:   vectorSelf,
+				bigFloatType: vectorSelf,
+				complexType:  vectorSelf,
+				vectorType: func(c Context, v Value) Value {
+					return v.(Vector).unique(c)
+				},
+			},
+		},
+
+		{
+			name: "head",
+			fn: [numType]unaryFn{
+				boolType:     self,
+				intType:      self,
+				charType:     self,
+				bigIntType:   self,
+				decimalType:  self,
+				bigRatType:   self,
+				bigFloatType: self,
+				complexType:  self,
+				vectorType: func(c Context, v Value) Value {
+					return nonEmpty("head", v.(Vector))[0]
+				},
+			},
+		},
+
+		{
+			name: "first",
+			fn: [numType]unaryFn{
+				boolType:     self,
+				intType:      self,
+				charType:     self,
+				bigIntType:   self,
+				decimalType:  self,
+				bigRatType:   self,
+				bigFloatType: self,
+				complexType:  self,
+				vectorType: func(c Context, v Value) Value {
+					return nonEmpty("first", v.(Vector))[0]
+				},
+				matrixType: func(c Context, v Value) Value {
+					return nonEmpty("first", v.(*Matrix).data)[0]
+				},
+			},
+		},
+
+		{
+			name: "tail",
+			fn: [numType]unaryFn{
+				boolType:     returnEmpty,
+				intType:      returnEmpty,
+				charType:     returnEmpty,
+				bigIntType:   returnEmpty,
+				decimalType:  returnEmpty,
+				bigRatType:   returnEmpty,
+				bigFloatType: returnEmpty,
+				complexType:  returnEmpty,
+				vectorType: func(c Context, v Value) Value {
+					return nonEmpty("tail", v.(Vector))[1:].Copy()
+				},
+			},
+		},
+
+		{
+			name: "init",
+			fn: [numType]unaryFn{
+				boolType:     returnEmpty,
+				intType:      returnEmpty,
+				charType:     returnEmpty,
+				bigIntType:   returnEmpty,
+				decimalType:  returnEmpty,
+				bigRatType:   returnEmpty,
+				bigFloatType: returnEmpty,
+				complexType:  returnEmpty,
+				vectorType: func(c Context, v Value) Value {
+					u := nonEmpty("init", v.(Vector))
+					return u[:len(u)-1].Copy()
+				},
+			},
+		},
+
+		{
+			name: "last",
+			fn: [numType]unaryFn{
+				boolType:     self,
+				intType:      self,
+				charType:     self,
+				bigIntType:   self,
+				decimalType:  self,
+				bigRatType:   self,
+				bigFloatType: self,
+				complexType:  self,
+				vectorType: func(c Context, v Value) Value {
+					u := nonEmpty("last", v.(Vector))
+					return u[len(u)-1]
+				},
+			},
+		},
+
 		{
 			name: "down",
 			fn: [numType]unaryFn{
+				boolType:     self,
 				intType:      self,
 				charType:     self,
 				bigIntType:   self,
+				decimalType:  self,
 				bigRatType:   self,
 				bigFloatType: self,
 				vectorType: func(c Context, v Value) Value {
@@ -595,12 +1020,101 @@ func init() {
 			},
 		},
 
+		{
+			name: "max",
+			fn: [numType]unaryFn{
+				boolType:     self,
+				intType:      self,
+				charType:     self,
+				bigIntType:   self,
+				decimalType:  self,
+				bigRatType:   self,
+				bigFloatType: self,
+				vectorType: func(c Context, v Value) Value {
+					return extreme(c, "max", v.(Vector))
+				},
+				matrixType: func(c Context, v Value) Value {
+					return extreme(c, "max", v.(*Matrix).Data())
+				},
+			},
+		},
+
+		{
+			name: "min",
+			fn: [numType]unaryFn{
+				boolType:     self,
+				intType:      self,
+				charType:     self,
+				bigIntType:   self,
+				decimalType:  self,
+				bigRatType:   self,
+				bigFloatType: self,
+				vectorType: func(c Context, v Value) Value {
+					return extreme(c, "min", v.(Vector))
+				},
+				matrixType: func(c Context, v Value) Value {
+					return extreme(c, "min", v.(*Matrix).Data())
+				},
+			},
+		},
+
+		{
+			name: "maxpos",
+			fn: [numType]unaryFn{
+				boolType:     returnOrigin,
+				intType:      returnOrigin,
+				charType:     returnOrigin,
+				bigIntType:   returnOrigin,
+				decimalType:  returnOrigin,
+				bigRatType:   returnOrigin,
+				bigFloatType: returnOrigin,
+				vectorType: func(c Context, v Value) Value {
+					return extremePos(c, "maxpos", v.(Vector))
+				},
+			},
+		},
+
+		{
+			name: "minpos",
+			fn: [numType]unaryFn{
+				boolType:     returnOrigin,
+				intType:      returnOrigin,
+				charType:     returnOrigin,
+				bigIntType:   returnOrigin,
+				decimalType:  returnOrigin,
+				bigRatType:   returnOrigin,
+				bigFloatType: returnOrigin,
+				vectorType: func(c Context, v Value) Value {
+					return extremePos(c, "minpos", v.(Vector))
+				},
+			},
+		},
+
+		{
+			name: "diff",
+			fn: [numType]unaryFn{
+				boolType:     returnEmpty,
+				intType:      returnEmpty,
+				charType:     returnEmpty,
+				bigIntType:   returnEmpty,
+				decimalType:  returnEmpty,
+				bigRatType:   returnEmpty,
+				bigFloatType: returnEmpty,
+				complexType:  returnEmpty,
+				vectorType: func(c Context, v Value) Value {
+					return differences(c, v.(Vector))
+				},
+			},
+		},
+
 		{
 			name: "rot",
 			fn: [numType]unaryFn{
+				boolType:     self,
 				intType:      self,
 				charType:     self,
 				bigIntType:   self,
+				decimalType:  self,
 				bigRatType:   self,
 				bigFloatType: self,
 				complexType:  self,
@@ -631,9 +1145,11 @@ func init() {
 		{
 			name: "flip",
 			fn: [numType]unaryFn{
+				boolType:     self,
 				intType:      self,
 				charType:     self,
 				bigIntType:   self,
+				decimalType:  self,
 				bigRatType:   self,
 				bigFloatType: self,
 				complexType:  self,
@@ -668,9 +1184,11 @@ func init() {
 		{
 			name: "transp",
 			fn: [numType]unaryFn{
+				boolType:     self,
 				intType:      self,
 				charType:     self,
 				bigIntType:   self,
+				decimalType:  self,
 				bigRatType:   self,
 				bigFloatType: self,
 				complexType:  self,
@@ -687,6 +1205,24 @@ func init() {
 			},
 		},
 
+		{
+			name: "det",
+			fn: [numType]unaryFn{
+				matrixType: func(c Context, v Value) Value {
+					return determinant(c, v.(*Matrix))
+				},
+			},
+		},
+
+		{
+			name: "inverse",
+			fn: [numType]unaryFn{
+				matrixType: func(c Context, v Value) Value {
+					return inverse(c, v.(*Matrix))
+				},
+			},
+		},
+
 		{
 			name:        "log",
 			elementwise: true,
@@ -703,11 +1239,11 @@ func init() {
 			name:        "cos",
 			elementwise: true,
 			fn: [numType]unaryFn{
-				intType:      func(c Context, v Value) Value { return cos(c, v) },
-				bigIntType:   func(c Context, v Value) Value { return cos(c, v) },
-				bigRatType:   func(c Context, v Value) Value { return cos(c, v) },
-				bigFloatType: func(c Context, v Value) Value { return cos(c, v) },
-				complexType:  func(c Context, v Value) Value { return cos(c, v) },
+				intType:      func(c Context, v Value) Value { return cos(c, angleIn(c, v)) },
+				bigIntType:   func(c Context, v Value) Value { return cos(c, angleIn(c, v)) },
+				bigRatType:   func(c Context, v Value) Value { return cos(c, angleIn(c, v)) },
+				bigFloatType: func(c Context, v Value) Value { return cos(c, angleIn(c, v)) },
+				complexType:  func(c Context, v Value) Value { return cos(c, angleIn(c, v)) },
 			},
 		},
 
@@ -715,11 +1251,11 @@ func init() {
 			name:        "sin",
 			elementwise: true,
 			fn: [numType]unaryFn{
-				intType:      func(c Context, v Value) Value { return sin(c, v) },
-				bigIntType:   func(c Context, v Value) Value { return sin(c, v) },
-				bigRatType:   func(c Context, v Value) Value { return sin(c, v) },
-				bigFloatType: func(c Context, v Value) Value { return sin(c, v) },
-				complexType:  func(c Context, v Value) Value { return sin(c, v) },
+				intType:      func(c Context, v Value) Value { return sin(c, angleIn(c, v)) },
+				bigIntType:   func(c Context, v Value) Value { return sin(c, angleIn(c, v)) },
+				bigRatType:   func(c Context, v Value) Value { return sin(c, angleIn(c, v)) },
+				bigFloatType: func(c Context, v Value) Value { return sin(c, angleIn(c, v)) },
+				complexType:  func(c Context, v Value) Value { return sin(c, angleIn(c, v)) },
 			},
 		},
 
@@ -727,11 +1263,11 @@ func init() {
 			name:        "tan",
 			elementwise: true,
 			fn: [numType]unaryFn{
-				intType:      func(c Context, v Value) Value { return tan(c, v) },
-				bigIntType:   func(c Context, v Value) Value { return tan(c, v) },
-				bigRatType:   func(c Context, v Value) Value { return tan(c, v) },
-				bigFloatType: func(c Context, v Value) Value { return tan(c, v) },
-				complexType:  func(c Context, v Value) Value { return tan(c, v) },
+				intType:      func(c Context, v Value) Value { return tan(c, angleIn(c, v)) },
+				bigIntType:   func(c Context, v Value) Value { return tan(c, angleIn(c, v)) },
+				bigRatType:   func(c Context, v Value) Value { return tan(c, angleIn(c, v)) },
+				bigFloatType: func(c Context, v Value) Value { return tan(c, angleIn(c, v)) },
+				complexType:  func(c Context, v Value) Value { return tan(c, angleIn(c, v)) },
 			},
 		},
 
@@ -739,11 +1275,11 @@ func init() {
 			name:        "asin",
 			elementwise: true,
 			fn: [numType]unaryFn{
-				intType:      func(c Context, v Value) Value { return asin(c, v) },
-				bigIntType:   func(c Context, v Value) Value { return asin(c, v) },
-				bigRatType:   func(c Context, v Value) Value { return asin(c, v) },
-				bigFloatType: func(c Context, v Value) Value { return asin(c, v) },
-				complexType:  func(c Context, v Value) Value { return asin(c, v) },
+				intType:      func(c Context, v Value) Value { return angleOut(c, asin(c, v)) },
+				bigIntType:   func(c Context, v Value) Value { return angleOut(c, asin(c, v)) },
+				bigRatType:   func(c Context, v Value) Value { return angleOut(c, asin(c, v)) },
+				bigFloatType: func(c Context, v Value) Value { return angleOut(c, asin(c, v)) },
+				complexType:  func(c Context, v Value) Value { return angleOut(c, asin(c, v)) },
 			},
 		},
 
@@ -751,11 +1287,11 @@ func init() {
 			name:        "acos",
 			elementwise: true,
 			fn: [numType]unaryFn{
-				intType:      func(c Context, v Value) Value { return acos(c, v) },
-				bigIntType:   func(c Context, v Value) Value { return acos(c, v) },
-				bigRatType:   func(c Context, v Value) Value { return acos(c, v) },
-				bigFloatType: func(c Context, v Value) Value { return acos(c, v) },
-				complexType:  func(c Context, v Value) Value { return acos(c, v) },
+				intType:      func(c Context, v Value) Value { return angleOut(c, acos(c, v)) },
+				bigIntType:   func(c Context, v Value) Value { return angleOut(c, acos(c, v)) },
+				bigRatType:   func(c Context, v Value) Value { return angleOut(c, acos(c, v)) },
+				bigFloatType: func(c Context, v Value) Value { return angleOut(c, acos(c, v)) },
+				complexType:  func(c Context, v Value) Value { return angleOut(c, acos(c, v)) },
 			},
 		},
 
@@ -763,11 +1299,35 @@ func init() {
 			name:        "atan",
 			elementwise: true,
 			fn: [numType]unaryFn{
-				intType:      func(c Context, v Value) Value { return atan(c, v) },
-				bigIntType:   func(c Context, v Value) Value { return atan(c, v) },
-				bigRatType:   func(c Context, v Value) Value { return atan(c, v) },
-				bigFloatType: func(c Context, v Value) Value { return atan(c, v) },
-				complexType:  func(c Context, v Value) Value { return atan(c, v) },
+				intType:      func(c Context, v Value) Value { return angleOut(c, atan(c, v)) },
+				bigIntType:   func(c Context, v Value) Value { return angleOut(c, atan(c, v)) },
+				bigRatType:   func(c Context, v Value) Value { return angleOut(c, atan(c, v)) },
+				bigFloatType: func(c Context, v Value) Value { return angleOut(c, atan(c, v)) },
+				complexType:  func(c Context, v Value) Value { return angleOut(c, atan(c, v)) },
+			},
+		},
+
+		{
+			name:        "torad",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:      toRadians,
+				bigIntType:   toRadians,
+				bigRatType:   toRadians,
+				bigFloatType: toRadians,
+				complexType:  toRadians,
+			},
+		},
+
+		{
+			name:        "todeg",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:      toDegrees,
+				bigIntType:   toDegrees,
+				bigRatType:   toDegrees,
+				bigFloatType: toDegrees,
+				complexType:  toDegrees,
 			},
 		},
 
@@ -913,12 +1473,45 @@ func init() {
 			},
 		},
 
+		{
+			name: "type",
+			fn: [numType]unaryFn{
+				boolType:     typeOf,
+				intType:      typeOf,
+				charType:     typeOf,
+				bigIntType:   typeOf,
+				decimalType:  typeOf,
+				bigRatType:   typeOf,
+				bigFloatType: typeOf,
+				complexType:  typeOf,
+				vectorType:   typeOf,
+				matrixType:   typeOf,
+			},
+		},
+
+		{
+			name: "env",
+			fn: [numType]unaryFn{
+				charType:   env,
+				vectorType: env,
+			},
+		},
+
+		{
+			name: "readbytes",
+			fn: [numType]unaryFn{
+				charType:   readBytes,
+				vectorType: readBytes,
+			},
+		},
+
 		{
 			name:        "float",
 			elementwise: true,
 			fn: [numType]unaryFn{
 				intType:      floatValueSelf,
 				bigIntType:   floatValueSelf,
+				decimalType:  floatValueSelf,
 				bigRatType:   floatValueSelf,
 				bigFloatType: floatValueSelf,
 				complexType: func(c Context, v Value) Value {
@@ -927,6 +1520,18 @@ func init() {
 				},
 			},
 		},
+
+		{
+			name:        "decimal",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:      decimalSelf,
+				bigIntType:   decimalSelf,
+				decimalType:  decimalSelf,
+				bigRatType:   decimalSelf,
+				bigFloatType: decimalSelf,
+			},
+		},
 	}
 
 	for _, op := range ops {
@@ -37,8 +37,18 @@ type Value interface {
 	// ProgString is like String, but suitable for program listing.
 	// For instance, it ignores the user format for numbers and
 	// puts quotes on chars, guaranteeing a correct representation.
+	// For an integer, rational or complex number it is the canonical
+	// form: Parse, with the default configuration, returns the same
+	// value from it.
 	ProgString() string
 
+	// Repr returns ivy source text that evaluates to the value, for
+	// serializing it. Unlike ProgString, it is defined for every value,
+	// including floats, vectors and matrices, and unlike Sprint it
+	// does not depend on the configuration: numbers are exact, as
+	// integers, rationals or decimals, whatever the output format.
+	Repr() string
+
 	toType(string, *config.Config, valueType) Value
 }
 
@@ -82,7 +92,33 @@ func parseTwo(conf *config.Config, s string) (Value, Value, string, error) {
 	return v1, v2, sep, err
 }
 
+// Parse parses s, the text of a number, and returns its value.
+// The accepted syntax, from the outside in, is:
+//
+//	A complex number is a real part and an imaginary part separated by j: 1j2.
+//	A rational is a numerator and denominator separated by a slash: 3/4.
+//	A real number is an optional sign, + or -, followed by an integer or
+//	a floating-point number such as 1.5 or 1e-3. Floating-point numbers
+//	are always decimal and are converted exactly to the smallest type
+//	that holds them, so 1.5e2 is the integer 150 and 1e-2 the rational 1/100.
+//	If the configuration sets decimal literals, they are instead Decimals,
+//	so 19.90 keeps its trailing zero.
+//	An integer is a run of digits in the input base. In base 0,
+//	the default, the prefixes 0x, 0o and 0b select hexadecimal, octal and
+//	binary, while any other leading zeros are ignored: 007 is 7 and 010 is 10.
+//
+// Underscores may separate digits, as in 1_000_000 or 0x_ff_ff, but each
+// must lie between two digits or follow a base prefix.
+// A sign alone, like an empty string, is an error.
 func Parse(conf *config.Config, s string) (Value, error) {
+	switch s {
+	case "", "+", "-":
+		Errorf("bad number syntax: %q", s)
+	}
+	s = stripUnderscores(conf, s)
+	if conf.InputBase() == 0 {
+		s = trimLeadingZeros(s)
+	}
 	// Is it a complex or rational?
 	v1, v2, sep, err := parseTwo(conf, s)
 	if err != nil {
@@ -90,7 +126,13 @@ func Parse(conf *config.Config, s string) (Value, error) {
 	}
 	switch sep {
 	case "j":
-		// A complex.
+		// A complex. Its parts may not be decimals.
+		if d, ok := v1.(Decimal); ok {
+			v1 = d.rat().shrink()
+		}
+		if d, ok := v2.(Decimal); ok {
+			v2 = d.rat().shrink()
+		}
 		return newComplex(v1, v2), nil
 	case "/":
 		// A rational. It's tricky.
@@ -115,6 +157,13 @@ func Parse(conf *config.Config, s string) (Value, error) {
 	if err == nil {
 		return b.shrink(), nil
 	}
+	if conf.DecimalLiterals() && (conf.InputBase() == 0 || conf.InputBase() == 10) {
+		d, err := parseDecimal(s)
+		if err != nil {
+			return nil, err
+		}
+		return d, nil
+	}
 	r, err := setBigRatFromFloatString(s) // We know there is no slash.
 	if err == nil {
 		return r.shrink(), nil
@@ -122,6 +171,92 @@ func Parse(conf *config.Config, s string) (Value, error) {
 	return nil, err
 }
 
+// ParseNumbers parses s, a list of numbers separated by spaces, each
+// as by Parse. A single number yields a scalar and more yield a vector.
+// Unlike Parse, it reports all bad input as an error, without panicking.
+func ParseNumbers(conf *config.Config, s string) (v Value, err error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no number in %q", s)
+	}
+	defer func() {
+		if e, ok := recover().(Error); ok {
+			v, err = nil, e
+		}
+	}()
+	elems := make([]Value, len(fields))
+	for i, field := range fields {
+		elems[i], err = Parse(conf, field)
+		if err != nil {
+			return nil, fmt.Errorf("bad number syntax: %s", field)
+		}
+	}
+	if len(elems) == 1 {
+		return elems[0], nil
+	}
+	return NewVector(elems), nil
+}
+
+// trimLeadingZeros removes redundant leading zeros from the base 0
+// integer s, so it is read as decimal rather than, as strconv and
+// math/big would have it, octal. Prefixes such as 0x are left alone,
+// as are numbers with a decimal point or exponent, which are decimal anyway.
+func trimLeadingZeros(s string) string {
+	if strings.ContainsAny(s, "./jeE") && !strings.HasPrefix(strings.TrimLeft(s, "+-"), "0x") {
+		return s
+	}
+	sign := ""
+	if s[0] == '+' || s[0] == '-' {
+		sign, s = s[:1], s[1:]
+	}
+	i := 0
+	for i < len(s)-1 && s[i] == '0' && '0' <= s[i+1] && s[i+1] <= '9' {
+		i++
+	}
+	return sign + s[i:]
+}
+
+// stripUnderscores returns s, the text of a number, with the underscores
+// separating its digits removed. An underscore must appear between two
+// digits of the input base, so leading, trailing and doubled underscores
+// are errors, as are underscores next to a base prefix, an exponent, a
+// decimal point, the slash of a rational or the j of a complex number.
+// In base 0, the digits are decimal, or hexadecimal after a 0x prefix.
+func stripUnderscores(conf *config.Config, s string) string {
+	if !strings.Contains(s, "_") {
+		return s
+	}
+	base := conf.InputBase()
+	if base == 0 {
+		base = 10
+		if t := strings.TrimLeft(s, "+-"); strings.HasPrefix(t, "0x") || strings.HasPrefix(t, "0X") {
+			base = 16
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '_' && (i == 0 || i == len(s)-1 || !isDigitByte(s[i-1], base) || !isDigitByte(s[i+1], base)) {
+			Errorf("bad number syntax: %q: '_' must separate successive digits", s)
+		}
+	}
+	return strings.ReplaceAll(s, "_", "")
+}
+
+// isDigitByte reports whether c is a digit in the base.
+func isDigitByte(c byte, base int) bool {
+	var d int
+	switch {
+	case '0' <= c && c <= '9':
+		d = int(c - '0')
+	case 'a' <= c && c <= 'z':
+		d = int(c-'a') + 10
+	case 'A' <= c && c <= 'Z':
+		d = int(c-'A') + 10
+	default:
+		return false
+	}
+	return d < base
+}
+
 func bigInt64(x int64) BigInt {
 	return BigInt{big.NewInt(x)}
 }
@@ -0,0 +1,321 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value_test
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"robpike.io/ivy/config"
+	"robpike.io/ivy/exec"
+	"robpike.io/ivy/run"
+	"robpike.io/ivy/value"
+)
+
+var parseTests = []struct {
+	in   string
+	base int
+	out  string // Empty means an error.
+}{
+	{"5", 0, "5"},
+	{"+5", 0, "5"},
+	{"-5", 0, "-5"},
+	{"007", 0, "7"},
+	{"+007", 0, "7"},
+	{"-007", 0, "-7"},
+	{"010", 0, "10"},
+	{"000", 0, "0"},
+	{"08", 0, "8"},
+	{"0o17", 0, "15"},
+	{"0x1f", 0, "31"},
+	{"0b101", 0, "5"},
+	{"00000000000000000000000000012345678901234567890", 0, "12345678901234567890"},
+	{"007/010", 0, "7/10"},
+	{"007.5", 0, "15/2"},
+	{"010", 10, "10"},
+	{"010", 8, "8"},
+	{"", 0, ""},
+	{"+", 0, ""},
+	{"-", 0, ""},
+	{"--5", 0, ""},
+	{"5-", 0, ""},
+	{"1_000_000", 0, "1000000"},
+	{"-1_000", 0, "-1000"},
+	{"3_000/7", 0, "3000/7"},
+	{"0xff_ff", 0, "65535"},
+	{"0b1_0_1", 0, "5"},
+	{"1_0.2_5", 0, "41/4"},
+	{"1e1_0", 0, "10000000000"},
+	{"1_0j2_0", 0, "10j20"},
+	{"1_f_f", 16, "511"},
+	{"_1", 0, ""},
+	{"1_", 0, ""},
+	{"1__0", 0, ""},
+	{"-_1", 0, ""},
+	{"1_/3", 0, ""},
+	{"1/_3", 0, ""},
+	{"1_.5", 0, ""},
+	{"1_j2", 0, ""},
+	{"1_e5", 0, ""},
+	{"1e_5", 0, ""},
+	{"1E_5", 0, ""},
+	{"1._5", 0, ""},
+	{"1j_2", 0, ""},
+	{"0x_ff", 0, ""},
+	{"0_x1", 0, ""},
+	{"1_9", 8, ""},
+	{"1_a", 10, ""},
+	{"f_f", 16, "255"},
+}
+
+func TestParse(t *testing.T) {
+	c := newContext()
+	conf := c.Config()
+	defer conf.SetBase(0, 0)
+	for _, test := range parseTests {
+		conf.SetBase(test.base, 0)
+		var got string
+		err := catch(func() {
+			v, err := value.Parse(conf, test.in)
+			if err == nil {
+				got = sprint(c, v)
+			}
+		})
+		if err != nil {
+			got = ""
+		}
+		if got != test.out {
+			t.Errorf("Parse(%q) in base %d = %q, want %q", test.in, test.base, got, test.out)
+		}
+	}
+}
+
+func TestParseSignAloneError(t *testing.T) {
+	conf := newContext().Config()
+	for _, s := range []string{"-", "+"} {
+		err := catch(func() { value.Parse(conf, s) })
+		if err == nil || err.Error() != `bad number syntax: "`+s+`"` {
+			t.Errorf("Parse(%q): error %v", s, err)
+		}
+	}
+}
+
+func TestParseUnderscoreError(t *testing.T) {
+	conf := newContext().Config()
+	err := catch(func() { value.Parse(conf, "1__000") })
+	if err == nil || err.Error() != `bad number syntax: "1__000": '_' must separate successive digits` {
+		t.Errorf("Parse(%q): error %v", "1__000", err)
+	}
+}
+
+// TestParseExponent checks that numbers with exponents have
+// the smallest exact type that can hold them.
+func TestParseExponent(t *testing.T) {
+	c := newContext()
+	tests := []struct {
+		in  string
+		out string
+		typ string
+	}{
+		{"1e3", "1000", "value.Int"},
+		{"1.5e2", "150", "value.Int"},
+		{"1E+3", "1000", "value.Int"},
+		{"-4e0", "-4", "value.Int"},
+		{"0e9", "0", "value.Int"},
+		{"0.0e-9", "0", "value.Int"},
+		{"1e0", "1", "value.Int"},
+		{"1.5e0", "3/2", "value.BigRat"},
+		{"1e-2", "1/100", "value.BigRat"},
+		{"1.3e-2", "13/1000", "value.BigRat"},
+		{"2500e-2", "25", "value.Int"},
+		{"1e10", "10000000000", "value.BigInt"},
+		{"-1.5e20", "-150000000000000000000", "value.BigInt"},
+	}
+	for _, test := range tests {
+		v, err := value.Parse(c.Config(), test.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", test.in, err)
+			continue
+		}
+		if got, typ := sprint(c, v), fmt.Sprintf("%T", v); got != test.out || typ != test.typ {
+			t.Errorf("Parse(%q) = %s (%s), want %s (%s)", test.in, got, typ, test.out, test.typ)
+		}
+	}
+}
+
+// TestDecimalSeparator checks that every printer of numbers in
+// floating-point format uses the configured decimal separator.
+func TestDecimalSeparator(t *testing.T) {
+	c := newContext()
+	conf := c.Config()
+	defer conf.SetFormat("")
+	defer conf.SetDecimalSeparator('.')
+	third := c.EvalBinary(value.Int(1), "/", value.Int(3))
+	root2 := c.EvalUnary("sqrt", value.Int(2))
+	huge := c.EvalBinary(root2, "*", c.EvalBinary(value.Int(10), "**", value.Int(25000)))
+	tests := []struct {
+		format string
+		v      value.Value
+		out    string // With '.' as the separator.
+	}{
+		{"%.2f", value.Int(3), "3.00"},
+		{"%.2f", c.EvalBinary(value.Int(10), "**", value.Int(20)), "100000000000000000000.00"},
+		{"%.2f", third, "0.33"},
+		{"%.2f", root2, "1.41"},
+		{"%.2e", value.Int(300), "3.00e+02"},
+		{"%.2e", c.EvalBinary(value.Int(10), "**", value.Int(20)), "1.00e+20"},
+		{"%.2e", third, "3.33e-01"},
+		{"%.2e", root2, "1.41e+00"},
+		{"%.3g", c.EvalBinary(third, "/", value.Int(1000000)), "3.33e-07"},
+		{"", root2, "1.41421356237"},
+		{"", huge, "1.41421356237e+25000"},
+	}
+	for _, sep := range []rune{'.', ','} {
+		conf.SetDecimalSeparator(sep)
+		for _, test := range tests {
+			conf.SetFormat(test.format)
+			want := strings.Replace(test.out, ".", string(sep), 1)
+			if got := sprint(c, test.v); got != want {
+				t.Errorf("separator %q, format %q: got %q, want %q", sep, test.format, got, want)
+			}
+		}
+	}
+	// Exact values without a format have no decimal point.
+	conf.SetFormat("")
+	if got := sprint(c, third); got != "1/3" {
+		t.Errorf("separator ',': got %q for 1/3", got)
+	}
+}
+
+// TestNegate checks unary - and abs, in particular that negating the
+// most negative Int or int64 promotes the result to a BigInt.
+func TestNegate(t *testing.T) {
+	c := newContext()
+	minInt64 := value.IntVector([]int64{math.MinInt64})[0]
+	tests := []struct {
+		op  string
+		in  value.Value
+		out string
+		typ string
+	}{
+		{"-", value.Int(3), "-3", "value.Int"},
+		{"-", value.Int(math.MinInt32), "2147483648", "value.BigInt"},
+		{"-", minInt64, "9223372036854775808", "value.BigInt"},
+		{"-", value.RatVector([]int64{-1}, []int64{3})[0], "1/3", "value.BigRat"},
+		{"-", value.IntVector([]int64{1, -2, math.MinInt32}), "-1 2 2147483648", "value.Vector"},
+		{"abs", value.Int(-3), "3", "value.Int"},
+		{"abs", value.Int(math.MinInt32), "2147483648", "value.BigInt"},
+		{"abs", minInt64, "9223372036854775808", "value.BigInt"},
+	}
+	for _, test := range tests {
+		v := c.EvalUnary(test.op, test.in)
+		if got, typ := sprint(c, v), fmt.Sprintf("%T", v); got != test.out || typ != test.typ {
+			t.Errorf("%s %s = %s (%s), want %s (%s)", test.op, sprint(c, test.in), got, typ, test.out, test.typ)
+		}
+	}
+}
+
+// TestProgStringRoundTrip checks that the program text of a number,
+// its canonical form, parses back to the same value. Unlike the text
+// printed by Sprint, it does not depend on the configured format.
+func TestProgStringRoundTrip(t *testing.T) {
+	c := newContext()
+	big := c.EvalBinary(value.Int(2), "**", value.Int(100))
+	values := []value.Value{
+		value.Int(0),
+		value.Int(7),
+		value.Int(-7),
+		value.Int(math.MaxInt32),
+		value.Int(math.MinInt32),
+		big,
+		c.EvalUnary("-", big),
+		c.EvalBinary(value.Int(1), "/", value.Int(3)),
+		c.EvalBinary(value.Int(-22), "/", value.Int(7)),
+		c.EvalBinary(big, "/", value.Int(3)),
+		c.EvalBinary(value.Int(-1), "/", big),
+		c.EvalBinary(value.Int(1), "j", value.Int(-2)),
+		c.EvalBinary(c.EvalBinary(value.Int(-1), "/", value.Int(2)), "j", big),
+	}
+	conf := new(config.Config)
+	for _, v := range values {
+		prog := v.ProgString()
+		var got value.Value
+		err := catch(func() {
+			var err error
+			got, err = value.Parse(conf, prog)
+			if err != nil {
+				value.Errorf("%v", err)
+			}
+		})
+		if err != nil {
+			t.Errorf("%T: Parse(%q): %v", v, prog, err)
+			continue
+		}
+		if got.ProgString() != prog || fmt.Sprintf("%T", got) != fmt.Sprintf("%T", v) {
+			t.Errorf("Parse(%q) = %s (%T), want %s (%T)", prog, got.ProgString(), got, prog, v)
+		}
+	}
+	// Sprint, by contrast, follows the format.
+	conf.SetFormat("%x")
+	if s := value.Int(255).Sprint(conf); s != "ff" {
+		t.Errorf("Sprint of 255 with format %%x is %q", s)
+	}
+}
+
+// TestRepr checks that the Repr of a value evaluates back to the
+// same value, and that it does not depend on the output format.
+func TestRepr(t *testing.T) {
+	exprs := []string{
+		"7",
+		"-7",
+		"2**100",
+		"-22/7",
+		"1j-2",
+		"(float 1/3) j 2",
+		"'x'",
+		"'\\n'",
+		"(0 == 0)",
+		"decimal 1.25",
+		"float 1/3",
+		"sqrt 2",
+		"-float 2**-80",
+		"1 -2 3/4",
+		",5",
+		"iota 0",
+		"'hello, \"world\"'",
+		"'a' 1 (float 1/2)",
+		"2 3 rho iota 6",
+		"2 2 rho 'abcd'",
+		"1 1 rho 5",
+		"2 0 rho 0",
+		"2 2 2 rho 1/2 (sqrt 2)",
+	}
+	newConf := func() *config.Config {
+		conf := new(config.Config)
+		conf.SetStrictBool(true)
+		return conf
+	}
+	for _, expr := range exprs {
+		conf := newConf()
+		v := run.IvyEval(exec.NewContext(conf), expr)
+		repr := v.Repr()
+		conf.SetFormat("%.2e")
+		conf.SetBase(0, 16)
+		if r := v.Repr(); r != repr {
+			t.Errorf("%s: Repr changed with format: %q then %q", expr, repr, r)
+		}
+		var got value.Value
+		err := catch(func() { got = run.IvyEval(exec.NewContext(newConf()), repr) })
+		if err != nil {
+			t.Errorf("%s: evaluating Repr %q: %v", expr, repr, err)
+			continue
+		}
+		if got.Repr() != repr || fmt.Sprintf("%T", got) != fmt.Sprintf("%T", v) {
+			t.Errorf("%s: Repr %q evaluates to %s (%T), want %T", expr, repr, got.Repr(), got, v)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strings"
 
 	"robpike.io/ivy/config"
 )
@@ -20,7 +21,8 @@ func (v Vector) String() string {
 }
 
 func (v Vector) Sprint(conf *config.Config) string {
-	return v.makeString(conf, !v.AllChars())
+	// Read the settings once, not for every element.
+	return v.makeString(conf.Snapshot(), !v.AllChars())
 }
 
 func (v Vector) Rank() int {
@@ -33,21 +35,56 @@ func (v Vector) ProgString() string {
 	panic("vector.ProgString - cannot happen")
 }
 
+// Repr returns the elements separated by spaces, or a string literal
+// if they are all chars. A vector of one element is raveled, so it does
+// not read back as a scalar, and an empty vector is iota 0.
+func (v Vector) Repr() string {
+	switch len(v) {
+	case 0:
+		return "iota 0"
+	case 1:
+		return ", " + v[0].Repr()
+	}
+	if v.AllChars() {
+		var b strings.Builder
+		for _, c := range v {
+			b.WriteRune(rune(c.(Char)))
+		}
+		return fmt.Sprintf("%q", b.String())
+	}
+	strs := make([]string, len(v))
+	for i, elem := range v {
+		strs[i] = elem.Repr()
+	}
+	return strings.Join(strs, " ")
+}
+
 // makeString is like String but takes a flag specifying
-// whether to put spaces between the elements. By
-// default (that is, by calling String) spaces are suppressed
-// if all the elements of the Vector are Chars.
+// whether to put separators between the elements. By
+// default (that is, by calling String) separators are suppressed
+// if all the elements of the Vector are Chars. The separator
+// is set in the configuration, and is a single space by default.
 func (v Vector) makeString(conf *config.Config, spaces bool) string {
 	var b bytes.Buffer
+	sep := conf.Separator()
 	for i, elem := range v {
 		if spaces && i > 0 {
-			fmt.Fprint(&b, " ")
+			b.WriteString(sep)
 		}
-		fmt.Fprintf(&b, "%s", elem.Sprint(conf))
+		b.WriteString(elem.Sprint(conf))
 	}
 	return b.String()
 }
 
+// elemStrings returns the formatted elements of v.
+func (v Vector) elemStrings(conf *config.Config) []string {
+	strs := make([]string, len(v))
+	for i, elem := range v {
+		strs[i] = elem.Sprint(conf)
+	}
+	return strs
+}
+
 // AllChars reports whether the vector contains only Chars.
 func (v Vector) AllChars() bool {
 	for _, c := range v {
@@ -68,6 +105,21 @@ func (v Vector) AllInts() bool {
 	return true
 }
 
+// maxType returns the widest type of the elements of v, so if it is
+// intType, every element is an Int or a Bool. Fast paths use it to
+// check that they may treat the vector as homogeneous; a single wider
+// element, such as a rational, sends the vector down the general path.
+// The type of an empty vector is boolType.
+func (v Vector) maxType() valueType {
+	t := boolType
+	for _, x := range v {
+		if w := whichType(x); w > t {
+			t = w
+		}
+	}
+	return t
+}
+
 func NewVector(elems []Value) Vector {
 	return Vector(elems)
 }
@@ -80,6 +132,31 @@ func NewIntVector(elems []int) Vector {
 	return Vector(vec)
 }
 
+// IntVector returns a Vector holding the integers in elems.
+// Elements too large for an Int are stored as BigInts.
+func IntVector(elems []int64) Vector {
+	vec := make([]Value, len(elems))
+	for i, elem := range elems {
+		vec[i] = Int(elem).maybeBig()
+	}
+	return Vector(vec)
+}
+
+// RatVector returns a Vector holding the rationals nums[i]/dens[i].
+// The slices must be the same length and no denominator may be zero.
+// Elements are reduced to lowest terms, and those that are integers
+// are stored as Ints or BigInts.
+func RatVector(nums, dens []int64) Vector {
+	if len(nums) != len(dens) {
+		Errorf("RatVector: length mismatch: %d %d", len(nums), len(dens))
+	}
+	vec := make([]Value, len(nums))
+	for i := range nums {
+		vec[i] = bigRatTwoInt64s(nums[i], dens[i]).shrink()
+	}
+	return Vector(vec)
+}
+
 func (v Vector) Eval(Context) Value {
 	return v
 }
@@ -88,6 +165,28 @@ func (v Vector) Inner() Value {
 	return v
 }
 
+// At returns the element of v with index i, counting from the
+// index origin of the configuration. An index out of range is an error.
+func (v Vector) At(c Context, i int) Value {
+	origin := c.Config().Origin()
+	if i < origin || i-origin >= len(v) {
+		Errorf("index %d out of range for shape %d", i, len(v))
+	}
+	return v[i-origin]
+}
+
+// Range calls f for each element of v in order, passing the index,
+// counting from the index origin of the configuration, and the element.
+// It stops early if f returns false.
+func (v Vector) Range(c Context, f func(i int, elem Value) bool) {
+	origin := c.Config().Origin()
+	for i, elem := range v {
+		if !f(i+origin, elem) {
+			return
+		}
+	}
+}
+
 func (v Vector) Copy() Vector {
 	elem := make([]Value, len(v))
 	copy(elem, v)
@@ -111,6 +210,54 @@ func (v Vector) sameLength(x Vector) {
 	}
 }
 
+// lexCompare compares u and v lexicographically, returning -1, 0 or 1.
+// The first unequal elements decide; if there are none, the shorter
+// vector is first.
+func lexCompare(c Context, u, v Vector) int {
+	for k := 0; k < len(u) && k < len(v); k++ {
+		if holds(c, u[k], "==", v[k]) {
+			continue
+		}
+		if holds(c, u[k], "<", v[k]) {
+			return -1
+		}
+		return 1
+	}
+	return cmpInt64(int64(len(u)), int64(len(v)))
+}
+
+// match reports whether u and v are the same value: they have
+// the same shape and their corresponding elements are equal.
+func match(c Context, u, v Value) bool {
+	ushape, udata := shapeAndData(u)
+	vshape, vdata := shapeAndData(v)
+	if !sameShape(ushape, vshape) {
+		return false
+	}
+	for k := range udata {
+		// Chars equal only chars, but == cannot compare them with numbers.
+		if (whichType(udata[k]) == charType) != (whichType(vdata[k]) == charType) {
+			return false
+		}
+		if !toBool(c.EvalBinary(udata[k], "==", vdata[k])) {
+			return false
+		}
+	}
+	return true
+}
+
+// shapeAndData returns the shape and elements of v.
+// A scalar has an empty shape and is its own single element.
+func shapeAndData(v Value) ([]int, Vector) {
+	switch v := v.(type) {
+	case Vector:
+		return []int{len(v)}, v
+	case *Matrix:
+		return v.shape, v.data
+	}
+	return nil, Vector{v}
+}
+
 // rotate returns a copy of v with elements rotated left by n.
 func (v Vector) rotate(n int) Value {
 	if len(v) == 0 {
@@ -140,7 +287,7 @@ func (v Vector) grade(c Context) Vector {
 		x[i] = i
 	}
 	sort.SliceStable(x, func(i, j int) bool {
-		return toBool(c.EvalBinary(v[x[i]], "<", v[x[j]]))
+		return holds(c, v[x[i]], "<", v[x[j]])
 	})
 	origin := c.Config().Origin()
 	for i := range x {
@@ -158,10 +305,114 @@ func (v Vector) reverse() Vector {
 	return r
 }
 
+// arithSeq returns the arithmetic sequence of count elements starting
+// at start with the given step, where start and step are the elements of
+// u and count is the single element of v. Each element is computed
+// directly as start+i*step, so exact operands give an exact result.
+func arithSeq(c Context, u, v Vector) Vector {
+	if len(u) != 2 {
+		Errorf("range: left operand must be start and step")
+	}
+	for _, x := range u {
+		switch x.(type) {
+		case Int, BigInt, Decimal, BigRat, BigFloat:
+		default:
+			Errorf("range: start and step must be real numbers")
+		}
+	}
+	if len(v) != 1 {
+		Errorf("range: count must be a scalar")
+	}
+	count, ok := v[0].(Int)
+	if !ok || count < 0 {
+		Errorf("range: bad count %s", v[0].Sprint(c.Config()))
+	}
+	start, step := u[0], u[1]
+	elems := make([]Value, count)
+	for i := range elems {
+		elems[i] = c.EvalBinary(start, "+", c.EvalBinary(Int(i), "*", step))
+	}
+	return NewVector(elems)
+}
+
+// zip returns the elements of u and v interleaved: u[1] v[1] u[2] v[2] ...
+// The vectors must have the same length, but a single element is
+// repeated to the length of the other vector.
+func zip(u, v Vector) Vector {
+	n := len(u)
+	switch {
+	case len(u) == 1:
+		n = len(v)
+	case len(v) != 1:
+		u.sameLength(v)
+	}
+	elems := make([]Value, 2*n)
+	for i := 0; i < n; i++ {
+		elems[2*i] = u[i%len(u)]
+		elems[2*i+1] = v[i%len(v)]
+	}
+	return NewVector(elems)
+}
+
+// partition returns the elements of v as the rows of a matrix of chunks,
+// each with the number of elements given by the single element of u.
+// If the chunk size does not divide the length of v, the last chunk is
+// padded with zeros, or with blanks if v is a string, as by fill.
+func partition(c Context, u, v Vector) *Matrix {
+	if len(u) != 1 {
+		Errorf("partition: chunk size must be a scalar")
+	}
+	n, ok := u[0].(Int)
+	if !ok || n <= 0 {
+		Errorf("partition: bad chunk size %s", u[0].Sprint(c.Config()))
+	}
+	rows := (len(v) + int(n) - 1) / int(n)
+	elems := make([]Value, rows*int(n))
+	copy(elems, v)
+	for i := len(v); i < len(elems); i++ {
+		elems[i] = v.padding()
+	}
+	return NewMatrix([]int{rows, int(n)}, elems)
+}
+
+// windows returns the overlapping windows of v, each with the number of
+// elements given by the single element of u, as the rows of a matrix.
+// Reducing the rows gives, for instance, moving sums.
+func windows(c Context, u, v Vector) *Matrix {
+	if len(u) != 1 {
+		Errorf("windows: size must be a scalar")
+	}
+	n, ok := u[0].(Int)
+	if !ok || n <= 0 || int(n) > len(v) {
+		Errorf("windows: bad size %s for length %d", u[0].Sprint(c.Config()), len(v))
+	}
+	rows := len(v) - int(n) + 1
+	elems := make([]Value, 0, rows*int(n))
+	for i := 0; i < rows; i++ {
+		elems = append(elems, v[i:i+int(n)]...)
+	}
+	return NewMatrix([]int{rows, int(n)}, elems)
+}
+
+// padding returns the element used to extend v: a blank if
+// v is a string, and otherwise zero.
+func (v Vector) padding() Value {
+	if v.AllChars() {
+		return Char(' ')
+	}
+	return Int(0)
+}
+
 // membership creates a vector of size len(u) reporting
 // whether each element is an element of v.
-// Algorithm is O(nV log nV + nU log nV) where nU==len(u) and nV==len(V).
+// Algorithm is O(nV log nV + nU log nV) where nU==len(u) and nV==len(V),
+// or O(nV + nU) using a hash table if v is long and all elements are hashable.
 func membership(c Context, u, v Vector) []Value {
+	if len(v) > hashThreshold {
+		if values := hashMembership(u, v); values != nil {
+			return values
+		}
+	}
 	values := make([]Value, len(u))
 	sortedV := v.sortedCopy(c)
 	work := 2 * (1 + int(math.Log2(float64(len(v)))))
@@ -173,12 +424,57 @@ func membership(c Context, u, v Vector) []Value {
 	return values
 }
 
+// indexOf implements A iota B for vectors: the location (index) of each
+// element of b in a, or origin-1 if it is not there. (APL does 1+⌈/⍳⍴A.)
+// Like membership, it sorts a, or uses a hash table if a is long and all
+// elements are hashable.
+func indexOf(c Context, a, b Vector) []Value {
+	origin := c.Config().Origin()
+	if len(a) > hashThreshold {
+		if indices := hashIndexOf(a, b, origin); indices != nil {
+			return indices
+		}
+	}
+	type indexed struct {
+		v     Value
+		index int
+	}
+	sortedA := make([]indexed, len(a))
+	for i, x := range a {
+		sortedA[i] = indexed{x, i + origin}
+	}
+	sort.SliceStable(sortedA, func(i, j int) bool {
+		return holds(c, sortedA[i].v, "<", sortedA[j].v)
+	})
+	indices := make([]Value, len(b))
+	work := 2 * (1 + int(math.Log2(float64(len(a)))))
+	pfor(true, work, len(b), func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			x := b[i]
+			indices[i] = Int(origin - 1)
+			pos := sort.Search(len(sortedA), func(j int) bool {
+				return holds(c, sortedA[j].v, ">=", x)
+			})
+			if pos < len(sortedA) && holds(c, sortedA[pos].v, "==", x) {
+				indices[i] = Int(sortedA[pos].index)
+			}
+		}
+	})
+	return indices
+}
+
+// scalarIndexOf implements A iota B for a vector A and a scalar B,
+// for which the result is a scalar.
+func scalarIndexOf(c Context, u, v Value) Value {
+	return indexOf(c, u.(Vector), Vector{v})[0]
+}
+
 // sortedCopy returns a copy of v, in ascending sorted order.
 func (v Vector) sortedCopy(c Context) Vector {
 	sortedV := make([]Value, len(v))
 	copy(sortedV, v)
 	sort.Slice(sortedV, func(i, j int) bool {
-		return c.EvalBinary(sortedV[i], "<", sortedV[j]) == Int(1)
+		return holds(c, sortedV[i], "<", sortedV[j])
 	})
 	return sortedV
 }
@@ -187,9 +483,9 @@ func (v Vector) sortedCopy(c Context) Vector {
 // sorted order.
 func (v Vector) contains(c Context, x Value) bool {
 	pos := sort.Search(len(v), func(j int) bool {
-		return c.EvalBinary(v[j], ">=", x) == Int(1)
+		return holds(c, v[j], ">=", x)
 	})
-	return pos < len(v) && c.EvalBinary(v[pos], "==", x) == Int(1)
+	return pos < len(v) && holds(c, v[pos], "==", x)
 }
 
 func (v Vector) shrink() Value {
@@ -0,0 +1,157 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value_test
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"robpike.io/ivy/config"
+	"robpike.io/ivy/exec"
+	"robpike.io/ivy/value"
+)
+
+func newContext() value.Context {
+	conf := new(config.Config)
+	conf.SetFormat("")
+	conf.SetMaxBits(1e9)
+	conf.SetMaxDigits(1e4)
+	conf.SetOrigin(1)
+	return exec.NewContext(conf)
+}
+
+func sprint(c value.Context, v value.Value) string {
+	return v.Sprint(c.Config())
+}
+
+// catch runs f and returns the ivy error it raised, if any.
+func catch(f func()) (err error) {
+	defer func() {
+		if e, ok := recover().(value.Error); ok {
+			err = e
+		}
+	}()
+	f()
+	return nil
+}
+
+func TestIntVector(t *testing.T) {
+	c := newContext()
+	v := value.IntVector([]int64{1, -2, 3, math.MaxInt64})
+	if got, want := sprint(c, v), "1 -2 3 9223372036854775807"; got != want {
+		t.Errorf("IntVector: got %q, want %q", got, want)
+	}
+	if _, ok := v[3].(value.BigInt); !ok {
+		t.Errorf("IntVector: large element has type %T, want BigInt", v[3])
+	}
+	sum := c.EvalBinary(v, "+", value.IntVector([]int64{1, 1, 1, 1}))
+	if got, want := sprint(c, sum), "2 -1 4 9223372036854775808"; got != want {
+		t.Errorf("IntVector +: got %q, want %q", got, want)
+	}
+	if got, want := sprint(c, c.EvalUnary("+/", v)), "9223372036854775809"; got != want {
+		t.Errorf("IntVector +/: got %q, want %q", got, want)
+	}
+}
+
+func TestRatVector(t *testing.T) {
+	c := newContext()
+	v := value.RatVector([]int64{1, 4, -3}, []int64{2, 2, 4})
+	if got, want := sprint(c, v), "1/2 2 -3/4"; got != want {
+		t.Errorf("RatVector: got %q, want %q", got, want)
+	}
+	if _, ok := v[1].(value.Int); !ok {
+		t.Errorf("RatVector: integral element has type %T, want Int", v[1])
+	}
+	prod := c.EvalBinary(v, "*", value.IntVector([]int64{2, 3, 4}))
+	if got, want := sprint(c, prod), "1 6 -3"; got != want {
+		t.Errorf("RatVector *: got %q, want %q", got, want)
+	}
+	if err := catch(func() { value.RatVector([]int64{1}, []int64{0}) }); err == nil {
+		t.Errorf("RatVector: no error for zero denominator")
+	}
+	if err := catch(func() { value.RatVector([]int64{1, 2}, []int64{1}) }); err == nil {
+		t.Errorf("RatVector: no error for length mismatch")
+	}
+}
+
+func TestVectorAt(t *testing.T) {
+	c := newContext()
+	v := value.IntVector([]int64{10, 20, 30})
+	for _, origin := range []int{0, 1, 5} {
+		c.Config().SetOrigin(origin)
+		if got := v.At(c, origin); got != value.Int(10) {
+			t.Errorf("origin %d: At(%d) = %v, want 10", origin, origin, got)
+		}
+		if got := v.At(c, origin+2); got != value.Int(30) {
+			t.Errorf("origin %d: At(%d) = %v, want 30", origin, origin+2, got)
+		}
+		for _, i := range []int{origin - 1, origin + 3} {
+			err := catch(func() { v.At(c, i) })
+			if err == nil {
+				t.Errorf("origin %d: At(%d) did not fail", origin, i)
+				continue
+			}
+			want := fmt.Sprintf("index %d out of range for shape 3", i)
+			if err.Error() != want {
+				t.Errorf("origin %d: At(%d) error %q, want %q", origin, i, err, want)
+			}
+		}
+	}
+}
+
+func TestVectorRange(t *testing.T) {
+	c := newContext()
+	c.Config().SetOrigin(0)
+	v := value.IntVector([]int64{10, 20, 30})
+	var got []string
+	v.Range(c, func(i int, elem value.Value) bool {
+		got = append(got, fmt.Sprintf("%d:%s", i, sprint(c, elem)))
+		return i < 1
+	})
+	if want := "0:10 1:20"; strings.Join(got, " ") != want {
+		t.Errorf("Range: got %q, want %q", strings.Join(got, " "), want)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	c := newContext()
+	var calls, last, total int
+	c.Config().SetProgressFunc(func(done, n int) {
+		if done < last {
+			t.Errorf("progress went backwards: %d after %d", done, last)
+		}
+		calls++
+		last, total = done, n
+	})
+	const n = 10000
+	elems := make([]int, n)
+	for i := range elems {
+		elems[i] = i
+	}
+	v := value.NewIntVector(elems)
+	for _, op := range []string{"+", "max"} {
+		calls, last, total = 0, 0, 0
+		c.EvalBinary(v, op, v)
+		if calls < n/1024 || last != n || total != n {
+			t.Errorf("%s: %d calls, last %d of %d", op, calls, last, total)
+		}
+	}
+	// +/ of integers has a fast path that needs no reporting.
+	for _, op := range []string{"max/", "-/", "+\\"} {
+		calls, last, total = 0, 0, 0
+		c.EvalUnary(op, v)
+		if calls < n/1024 || last != n || total != n {
+			t.Errorf("%s: %d calls, last %d of %d", op, calls, last, total)
+		}
+	}
+	// Short operations are not reported.
+	calls = 0
+	c.EvalBinary(value.NewIntVector([]int{1, 2, 3}), "+", value.Int(1))
+	if calls != 0 {
+		t.Errorf("progress reported for short vector")
+	}
+}